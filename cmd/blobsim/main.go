@@ -0,0 +1,179 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// blobsim replays recorded blob transaction traces against a blob pool to
+// evaluate configuration changes offline.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/internal/debug"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+)
+
+var app = flags.NewApp("go-ethereum blob pool simulator")
+
+var (
+	datacapFlag = &cli.Uint64Flag{
+		Name:  "datacap",
+		Usage: "Disk space to allocate for pending blob transactions (soft limit)",
+		Value: blobpool.DefaultConfig.Datacap,
+	}
+	priceBumpFlag = &cli.Uint64Flag{
+		Name:  "pricebump",
+		Usage: "Price bump percentage to replace an already existing blob transaction",
+		Value: blobpool.DefaultConfig.PriceBump,
+	}
+	gasTipFlag = &cli.Uint64Flag{
+		Name:  "gastip",
+		Usage: "Minimum gas tip (in wei) to accept blob transactions into the pool",
+		Value: params.GWei / 1000,
+	}
+	osakaFlag = &cli.BoolFlag{
+		Name:  "osaka",
+		Usage: "Simulate with the Osaka fork active (cell proof sidecars)",
+	}
+	finalityFlag = &cli.Uint64Flag{
+		Name:  "finality",
+		Usage: "Number of blocks after which included blobs are considered final",
+		Value: 64,
+	}
+	jsonFlag = &cli.BoolFlag{
+		Name:  "json",
+		Usage: "Output the simulation report as JSON",
+	}
+	rpcFlag = &cli.StringFlag{
+		Name:  "rpc",
+		Usage: "RPC endpoint of the node to record the trace from",
+		Value: "http://localhost:8545",
+	}
+	fromFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block to record",
+	}
+	toFlag = &cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block to record (0 = chain head)",
+	}
+	outFlag = &cli.StringFlag{
+		Name:  "out",
+		Usage: "File to write the recorded trace into (default = stdout)",
+	}
+)
+
+var (
+	runCommand = &cli.Command{
+		Name:      "run",
+		Usage:     "Replay a recorded trace against a blob pool",
+		ArgsUsage: "<trace>",
+		Action:    runSimulation,
+		Flags: []cli.Flag{
+			datacapFlag,
+			priceBumpFlag,
+			gasTipFlag,
+			osakaFlag,
+			finalityFlag,
+			jsonFlag,
+		},
+		Description: `
+The run command replays a trace of blob transactions and block fee environments
+against a blob pool backed by an in-memory store. Blocks are built from the pool
+contents using the recorded fees and the report shows how many transactions were
+accepted, rejected, replaced, evicted and included.`,
+	}
+	recordCommand = &cli.Command{
+		Name:   "record",
+		Usage:  "Record a trace of blob transactions and block fees from a node",
+		Action: recordTrace,
+		Flags: []cli.Flag{
+			rpcFlag,
+			fromFlag,
+			toFlag,
+			outFlag,
+		},
+		Description: `
+The record command retrieves a range of blocks from a node over RPC and emits the
+blob transactions they contain, followed by the fee environment of each block.
+Transactions are recorded as arriving right before the block including them.`,
+	}
+)
+
+func init() {
+	app.Flags = append(app.Flags, debug.Flags...)
+	app.Before = func(ctx *cli.Context) error {
+		flags.MigrateGlobalFlags(ctx)
+		return debug.Setup(ctx)
+	}
+	app.After = func(ctx *cli.Context) error {
+		debug.Exit()
+		return nil
+	}
+	app.Commands = []*cli.Command{
+		runCommand,
+		recordCommand,
+	}
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// runSimulation replays the trace given as the first argument against a blob
+// pool configured from the command line flags.
+func runSimulation(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("need trace file as the only argument")
+	}
+	f, err := os.Open(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	trace, err := readTrace(f)
+	if err != nil {
+		return err
+	}
+	config := &simConfig{
+		Pool: blobpool.Config{
+			Datacap:   ctx.Uint64(datacapFlag.Name),
+			PriceBump: ctx.Uint64(priceBumpFlag.Name),
+		},
+		GasTip:   ctx.Uint64(gasTipFlag.Name),
+		Osaka:    ctx.Bool(osakaFlag.Name),
+		Finality: ctx.Uint64(finalityFlag.Name),
+	}
+	report, err := simulate(config, trace)
+	if err != nil {
+		return err
+	}
+	if ctx.Bool(jsonFlag.Name) {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	report.print(os.Stdout)
+	return nil
+}
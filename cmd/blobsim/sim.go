@@ -0,0 +1,560 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+)
+
+// simConfig contains the tunables of a simulation run.
+type simConfig struct {
+	Pool     blobpool.Config // Blob pool configuration to evaluate
+	GasTip   uint64          // Minimum gas tip to accept into the pool and to include
+	Osaka    bool            // Whether the Osaka fork (cell proofs) is active
+	Finality uint64          // Number of blocks after which blocks are finalized
+}
+
+// simChain is an in-memory chain backing the simulated blob pool. Blocks are not
+// executed, only the sender nonces of the included transactions are updated.
+type simChain struct {
+	config   *params.ChainConfig
+	db       state.Database
+	finality uint64
+
+	head   *types.Header
+	canon  map[uint64]*types.Header
+	blocks map[common.Hash]*types.Block
+}
+
+// Config retrieves the chain's fork configuration.
+func (c *simChain) Config() *params.ChainConfig { return c.config }
+
+// CurrentBlock returns the current head of the chain.
+func (c *simChain) CurrentBlock() *types.Header { return c.head }
+
+// CurrentFinalBlock returns the block the configured finality distance below
+// the current head.
+func (c *simChain) CurrentFinalBlock() *types.Header {
+	head := c.head.Number.Uint64()
+	if head < c.finality {
+		return c.canon[0]
+	}
+	return c.canon[head-c.finality]
+}
+
+// GetBlock retrieves a specific block, used during pool resets.
+func (c *simChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if block := c.blocks[hash]; block != nil && block.NumberU64() == number {
+		return block
+	}
+	return nil
+}
+
+// StateAt returns a state database for a given root hash.
+func (c *simChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	return state.New(root, c.db)
+}
+
+// insert links a new block into the chain as the new head.
+func (c *simChain) insert(block *types.Block) {
+	c.blocks[block.Hash()] = block
+	c.canon[block.NumberU64()] = block.Header()
+	c.head = block.Header()
+}
+
+// tracked is a transaction accepted into the pool which has not yet been
+// included, replaced or evicted.
+type tracked struct {
+	sender common.Address
+	nonce  uint64
+	added  uint64 // Chain head when the transaction was accepted
+}
+
+// simulator replays a recorded trace against a live blob pool.
+type simulator struct {
+	config *simConfig
+	chain  *simChain
+	pool   *blobpool.BlobPool
+	signer types.Signer
+	gasTip *uint256.Int
+
+	keys   map[common.Address]*ecdsa.PrivateKey      // Simulated keys of the recorded senders
+	nonces map[common.Address]map[uint64]uint64      // Recorded nonces remapped to gapless ones
+	live   map[common.Hash]*tracked                  // Pooled transactions being tracked
+	slots  map[common.Address]map[uint64]common.Hash // Pooled transactions by sender and nonce
+
+	blobs []*simBlob // Synthetic blobs to attach to the transactions
+
+	report *report
+}
+
+// simBlob is a synthetic blob along with its precomputed commitment and proofs.
+type simBlob struct {
+	blob       kzg4844.Blob
+	commitment kzg4844.Commitment
+	proof      kzg4844.Proof
+	cellProofs []kzg4844.Proof
+	vhash      common.Hash
+}
+
+// simulate replays the given trace against a fresh blob pool and reports the
+// resulting pool behavior.
+func simulate(config *simConfig, trace []*traceEntry) (*report, error) {
+	sim, err := newSimulator(config, trace)
+	if err != nil {
+		return nil, err
+	}
+	defer sim.pool.Close()
+
+	for _, entry := range trace {
+		switch {
+		case entry.Tx != nil:
+			if err := sim.addTx(entry.Tx); err != nil {
+				return nil, err
+			}
+		case entry.Block != nil:
+			if err := sim.mine(entry.Block); err != nil {
+				return nil, err
+			}
+		}
+		sim.sweep()
+	}
+	sim.report.finalize(len(sim.live))
+	return sim.report, nil
+}
+
+// newSimulator creates an in-memory chain and blob pool, seeding the state with
+// all the senders appearing in the trace.
+func newSimulator(config *simConfig, trace []*traceEntry) (*simulator, error) {
+	chainConfig := *params.MergedTestChainConfig
+	chainConfig.BlobScheduleConfig = params.DefaultBlobSchedule
+	if config.Osaka {
+		chainConfig.OsakaTime = new(uint64)
+	}
+	sim := &simulator{
+		config: config,
+		signer: types.LatestSigner(&chainConfig),
+		gasTip: uint256.NewInt(config.GasTip),
+		keys:   make(map[common.Address]*ecdsa.PrivateKey),
+		nonces: make(map[common.Address]map[uint64]uint64),
+		live:   make(map[common.Hash]*tracked),
+		slots:  make(map[common.Address]map[uint64]common.Hash),
+		report: newReport(),
+	}
+	// Recorded nonces might be gapped due to non-blob transactions from the same
+	// sender, so remap them into a gapless sequence starting from zero.
+	seen := make(map[common.Address][]uint64)
+	for _, entry := range trace {
+		if entry.Tx != nil {
+			seen[entry.Tx.From] = append(seen[entry.Tx.From], uint64(entry.Tx.Nonce))
+		}
+	}
+	for from, nonces := range seen {
+		slices.Sort(nonces)
+		nonces = slices.Compact(nonces)
+
+		sim.nonces[from] = make(map[uint64]uint64, len(nonces))
+		for i, nonce := range nonces {
+			sim.nonces[from][nonce] = uint64(i)
+		}
+		key, err := crypto.ToECDSA(crypto.Keccak256([]byte("blobsim"), from[:]))
+		if err != nil {
+			return nil, err
+		}
+		sim.keys[from] = key
+	}
+	// Create the genesis state funding all the simulated senders
+	db := state.NewDatabase(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil), nil)
+	statedb, err := state.New(types.EmptyRootHash, db)
+	if err != nil {
+		return nil, err
+	}
+	funds := new(uint256.Int).Mul(uint256.NewInt(1_000_000_000), uint256.NewInt(params.Ether))
+	for _, key := range sim.keys {
+		statedb.AddBalance(crypto.PubkeyToAddress(key.PublicKey), funds, tracing.BalanceChangeUnspecified)
+	}
+	root, err := statedb.Commit(0, false, false)
+	if err != nil {
+		return nil, err
+	}
+	genesis := &types.Header{
+		Number:        new(big.Int),
+		GasLimit:      params.GenesisGasLimit,
+		BaseFee:       big.NewInt(params.InitialBaseFee),
+		Difficulty:    new(big.Int),
+		Root:          root,
+		ExcessBlobGas: new(uint64),
+		BlobGasUsed:   new(uint64),
+	}
+	for _, entry := range trace {
+		if entry.Block != nil {
+			if time := uint64(entry.Block.Time); time > 0 {
+				genesis.Time = time - 1
+			}
+			break
+		}
+	}
+	sim.chain = &simChain{
+		config:   &chainConfig,
+		db:       db,
+		finality: config.Finality,
+		canon:    make(map[uint64]*types.Header),
+		blocks:   make(map[common.Hash]*types.Block),
+	}
+	sim.chain.insert(types.NewBlockWithHeader(genesis))
+
+	// Create the blob pool with an in-memory store
+	poolConfig := config.Pool
+	poolConfig.Datadir = ""
+
+	sim.pool = blobpool.New(poolConfig, sim.chain)
+	if err := sim.pool.Init(config.GasTip, genesis, func(common.Address, bool) error { return nil }); err != nil {
+		return nil, err
+	}
+	return sim, nil
+}
+
+// blob returns the synthetic blob with the given index, creating it if needed.
+func (s *simulator) blob(index int) (*simBlob, error) {
+	for len(s.blobs) <= index {
+		blob := new(simBlob)
+
+		// Fill the blob with distinct but valid field elements
+		n := len(s.blobs)
+		blob.blob[1] = byte(n >> 8)
+		blob.blob[2] = byte(n)
+
+		var err error
+		if blob.commitment, err = kzg4844.BlobToCommitment(&blob.blob); err != nil {
+			return nil, err
+		}
+		if blob.proof, err = kzg4844.ComputeBlobProof(&blob.blob, blob.commitment); err != nil {
+			return nil, err
+		}
+		if s.config.Osaka {
+			if blob.cellProofs, err = kzg4844.ComputeCellProofs(&blob.blob); err != nil {
+				return nil, err
+			}
+		}
+		blob.vhash = kzg4844.CalcBlobHashV1(sha256.New(), &blob.commitment)
+		s.blobs = append(s.blobs, blob)
+	}
+	return s.blobs[index], nil
+}
+
+// makeTx creates a signed blob transaction from a recorded one, using the keys
+// and nonces of the simulated sender.
+func (s *simulator) makeTx(rec *txRecord) (*types.Transaction, error) {
+	sidecar := &types.BlobTxSidecar{}
+	if s.config.Osaka {
+		sidecar.Version = types.BlobSidecarVersion1
+	}
+	var hashes []common.Hash
+	for i := 0; i < rec.Blobs; i++ {
+		blob, err := s.blob(i)
+		if err != nil {
+			return nil, err
+		}
+		sidecar.Blobs = append(sidecar.Blobs, blob.blob)
+		sidecar.Commitments = append(sidecar.Commitments, blob.commitment)
+		if s.config.Osaka {
+			sidecar.Proofs = append(sidecar.Proofs, blob.cellProofs...)
+		} else {
+			sidecar.Proofs = append(sidecar.Proofs, blob.proof)
+		}
+		hashes = append(hashes, blob.vhash)
+	}
+	return types.SignNewTx(s.keys[rec.From], s.signer, &types.BlobTx{
+		ChainID:    uint256.MustFromBig(s.chain.config.ChainID),
+		Nonce:      s.nonces[rec.From][uint64(rec.Nonce)],
+		GasTipCap:  uint256.MustFromBig((*big.Int)(rec.GasTipCap)),
+		GasFeeCap:  uint256.MustFromBig((*big.Int)(rec.GasFeeCap)),
+		Gas:        params.TxGas,
+		BlobFeeCap: uint256.MustFromBig((*big.Int)(rec.BlobFeeCap)),
+		BlobHashes: hashes,
+		Sidecar:    sidecar,
+	})
+}
+
+// addTx injects a recorded transaction into the pool and tracks it if accepted.
+func (s *simulator) addTx(rec *txRecord) error {
+	tx, err := s.makeTx(rec)
+	if err != nil {
+		return err
+	}
+	s.report.Transactions++
+
+	if err := s.pool.Add([]*types.Transaction{tx}, true)[0]; err != nil {
+		s.report.Rejected[rejectReason(err)]++
+		return nil
+	}
+	s.report.Accepted++
+
+	sender := crypto.PubkeyToAddress(s.keys[rec.From].PublicKey)
+	if s.slots[sender] == nil {
+		s.slots[sender] = make(map[uint64]common.Hash)
+	}
+	if prev, ok := s.slots[sender][tx.Nonce()]; ok {
+		delete(s.live, prev)
+		s.report.Replaced++
+	}
+	s.slots[sender][tx.Nonce()] = tx.Hash()
+	s.live[tx.Hash()] = &tracked{
+		sender: sender,
+		nonce:  tx.Nonce(),
+		added:  s.chain.head.Number.Uint64(),
+	}
+	return nil
+}
+
+// mine creates a new block on top of the current head using the recorded fee
+// environment, filling it with the most profitable pooled blob transactions.
+func (s *simulator) mine(rec *blockRecord) error {
+	parent := s.chain.head
+	header := &types.Header{
+		ParentHash:    parent.Hash(),
+		Number:        new(big.Int).Add(parent.Number, common.Big1),
+		Time:          uint64(rec.Time),
+		GasLimit:      uint64(rec.GasLimit),
+		GasUsed:       uint64(rec.GasUsed),
+		BaseFee:       (*big.Int)(rec.BaseFee),
+		Difficulty:    new(big.Int),
+		ExcessBlobGas: new(uint64),
+		BlobGasUsed:   new(uint64),
+	}
+	*header.ExcessBlobGas = uint64(rec.ExcessBlobGas)
+	if header.Time <= parent.Time {
+		header.Time = parent.Time + 12
+	}
+	if header.GasLimit == 0 {
+		header.GasLimit = parent.GasLimit
+	}
+	var (
+		basefee  = uint256.MustFromBig(header.BaseFee)
+		blobfee  = uint256.MustFromBig(eip4844.CalcBlobFee(s.chain.config, header))
+		capacity = eip4844.MaxBlobGasPerBlock(s.chain.config, header.Time)
+	)
+	pending := s.pool.Pending(txpool.PendingFilter{
+		MinTip:      s.gasTip,
+		BaseFee:     basefee,
+		BlobFee:     blobfee,
+		OnlyBlobTxs: true,
+	})
+	statedb, err := s.chain.StateAt(parent.Root)
+	if err != nil {
+		return err
+	}
+	var txs []*types.Transaction
+	for _, ltx := range selectTxs(pending, basefee, capacity) {
+		tx := ltx.Resolve()
+		if tx == nil {
+			continue
+		}
+		item := s.live[tx.Hash()]
+		if item == nil {
+			return fmt.Errorf("untracked transaction %x in pool", tx.Hash())
+		}
+		statedb.SetNonce(item.sender, tx.Nonce()+1, tracing.NonceChangeUnspecified)
+		txs = append(txs, tx.WithoutBlobTxSidecar())
+
+		*header.BlobGasUsed += tx.BlobGas()
+		s.report.included(header.Number.Uint64()-item.added, len(tx.BlobHashes()))
+
+		delete(s.live, tx.Hash())
+		delete(s.slots[item.sender], item.nonce)
+	}
+	if header.Root, err = statedb.Commit(header.Number.Uint64(), false, false); err != nil {
+		return err
+	}
+	block := types.NewBlock(header, &types.Body{Transactions: txs}, nil, trie.NewStackTrie(nil))
+	s.chain.insert(block)
+	s.pool.Reset(parent, block.Header())
+
+	s.report.Blocks++
+	return nil
+}
+
+// selectTxs picks the pending transactions to include into a block, ordered by
+// effective miner tip, respecting nonce ordering and the blob gas capacity.
+func selectTxs(pending map[common.Address][]*txpool.LazyTransaction, basefee *uint256.Int, capacity uint64) []*txpool.LazyTransaction {
+	tip := func(ltx *txpool.LazyTransaction) *uint256.Int {
+		tip := new(uint256.Int).Sub(ltx.GasFeeCap, basefee)
+		if tip.Gt(ltx.GasTipCap) {
+			tip = ltx.GasTipCap
+		}
+		return tip
+	}
+	var selected []*txpool.LazyTransaction
+	for len(pending) > 0 {
+		var (
+			best    common.Address
+			bestTip *uint256.Int
+		)
+		for addr, txs := range pending {
+			if t := tip(txs[0]); bestTip == nil || t.Gt(bestTip) || (t.Eq(bestTip) && addr.Cmp(best) < 0) {
+				best, bestTip = addr, t
+			}
+		}
+		ltx := pending[best][0]
+		if ltx.BlobGas > capacity {
+			// Doesn't fit, the rest of the account's transactions are unusable
+			delete(pending, best)
+			continue
+		}
+		capacity -= ltx.BlobGas
+		selected = append(selected, ltx)
+
+		if pending[best] = pending[best][1:]; len(pending[best]) == 0 {
+			delete(pending, best)
+		}
+	}
+	return selected
+}
+
+// sweep checks which of the tracked transactions were dropped from the pool and
+// counts them as evicted.
+func (s *simulator) sweep() {
+	for hash, item := range s.live {
+		if !s.pool.Has(hash) {
+			delete(s.live, hash)
+			delete(s.slots[item.sender], item.nonce)
+			s.report.Evicted++
+		}
+	}
+	if txs, _ := s.pool.Stats(); txs > s.report.PoolMax {
+		s.report.PoolMax = txs
+	}
+}
+
+// rejectReason maps a pool rejection error into a category for reporting.
+func rejectReason(err error) string {
+	switch {
+	case errors.Is(err, txpool.ErrAlreadyKnown):
+		return "known"
+	case errors.Is(err, txpool.ErrReplaceUnderpriced):
+		return "noreplace"
+	case errors.Is(err, txpool.ErrUnderpriced):
+		return "underpriced"
+	case errors.Is(err, core.ErrNonceTooLow):
+		return "stale"
+	case errors.Is(err, core.ErrNonceTooHigh):
+		return "gapped"
+	case errors.Is(err, core.ErrInsufficientFunds):
+		return "overdrafted"
+	case errors.Is(err, txpool.ErrAccountLimitExceeded):
+		return "overcapped"
+	default:
+		return "invalid"
+	}
+}
+
+// report contains the statistics gathered during a simulation run.
+type report struct {
+	Blocks       int            `json:"blocks"`
+	Transactions int            `json:"transactions"`
+	Accepted     int            `json:"accepted"`
+	Rejected     map[string]int `json:"rejected"`
+	Replaced     int            `json:"replaced"`
+	Evicted      int            `json:"evicted"`
+	Included     int            `json:"included"`
+	Pending      int            `json:"pending"`
+	PoolMax      int            `json:"poolMax"`
+
+	IncludedBlobs int     `json:"includedBlobs"`
+	DelayMean     float64 `json:"inclusionDelayMean"`
+	DelayP50      uint64  `json:"inclusionDelayP50"`
+	DelayP90      uint64  `json:"inclusionDelayP90"`
+	DelayMax      uint64  `json:"inclusionDelayMax"`
+
+	delays []uint64
+}
+
+func newReport() *report {
+	return &report{Rejected: make(map[string]int)}
+}
+
+// included records the inclusion of a transaction after the given number of
+// blocks spent in the pool.
+func (r *report) included(delay uint64, blobs int) {
+	r.Included++
+	r.IncludedBlobs += blobs
+	r.delays = append(r.delays, delay)
+}
+
+// finalize calculates the aggregate statistics after the simulation is done.
+func (r *report) finalize(pending int) {
+	r.Pending = pending
+	if len(r.delays) == 0 {
+		return
+	}
+	slices.Sort(r.delays)
+
+	var sum uint64
+	for _, delay := range r.delays {
+		sum += delay
+	}
+	r.DelayMean = float64(sum) / float64(len(r.delays))
+	r.DelayP50 = r.delays[len(r.delays)*50/100]
+	r.DelayP90 = r.delays[len(r.delays)*90/100]
+	r.DelayMax = r.delays[len(r.delays)-1]
+}
+
+// print writes the report in a human readable form.
+func (r *report) print(w io.Writer) {
+	fmt.Fprintf(w, "Blocks:        %d\n", r.Blocks)
+	fmt.Fprintf(w, "Transactions:  %d\n", r.Transactions)
+	fmt.Fprintf(w, "  accepted:    %d\n", r.Accepted)
+	fmt.Fprintf(w, "  rejected:    %d\n", r.Transactions-r.Accepted)
+
+	reasons := make([]string, 0, len(r.Rejected))
+	for reason := range r.Rejected {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "    %-11s %d\n", reason+":", r.Rejected[reason])
+	}
+	fmt.Fprintf(w, "  replaced:    %d\n", r.Replaced)
+	fmt.Fprintf(w, "  evicted:     %d\n", r.Evicted)
+	fmt.Fprintf(w, "  included:    %d (%d blobs)\n", r.Included, r.IncludedBlobs)
+	fmt.Fprintf(w, "  pending:     %d\n", r.Pending)
+	fmt.Fprintf(w, "Pool peak:     %d txs\n", r.PoolMax)
+	fmt.Fprintf(w, "Inclusion delay (blocks): mean %.2f, p50 %d, p90 %d, max %d\n", r.DelayMean, r.DelayP50, r.DelayP90, r.DelayMax)
+}
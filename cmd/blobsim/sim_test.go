@@ -0,0 +1,70 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+)
+
+// Tests that a small trace is replayed correctly, with transactions accepted,
+// rejected, replaced and included as expected.
+func TestSimulate(t *testing.T) {
+	// Sender 0xaa has gapped nonces (remapped), 0xbb replaces its transaction
+	// and 0xcc is priced below the blob fee floor.
+	input := `
+{"tx": {"from": "0x00000000000000000000000000000000000000aa", "nonce": 5, "maxPriorityFeePerGas": 2, "maxFeePerGas": 100000000000, "maxFeePerBlobGas": 100, "blobs": 2}}
+{"tx": {"from": "0x00000000000000000000000000000000000000aa", "nonce": 7, "maxPriorityFeePerGas": 2, "maxFeePerGas": 100000000000, "maxFeePerBlobGas": 100, "blobs": 1}}
+{"tx": {"from": "0x00000000000000000000000000000000000000bb", "nonce": 0, "maxPriorityFeePerGas": 1000, "maxFeePerGas": 100000000000, "maxFeePerBlobGas": 100, "blobs": 1}}
+{"tx": {"from": "0x00000000000000000000000000000000000000bb", "nonce": 0, "maxPriorityFeePerGas": 3000, "maxFeePerGas": 300000000000, "maxFeePerBlobGas": 300, "blobs": 1}}
+{"tx": {"from": "0x00000000000000000000000000000000000000cc", "nonce": 0, "maxPriorityFeePerGas": 2, "maxFeePerGas": 100000000000, "maxFeePerBlobGas": 0, "blobs": 1}}
+{"block": {"number": 1, "timestamp": 12, "gasLimit": 30000000, "gasUsed": 15000000, "baseFeePerGas": 1000000000, "excessBlobGas": 0}}
+{"block": {"number": 2, "timestamp": 24, "gasLimit": 30000000, "gasUsed": 15000000, "baseFeePerGas": 1000000000, "excessBlobGas": 0}}
+`
+	trace, err := readTrace(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to parse trace: %v", err)
+	}
+	report, err := simulate(&simConfig{
+		Pool:     blobpool.DefaultConfig,
+		GasTip:   1,
+		Finality: 64,
+	}, trace)
+	if err != nil {
+		t.Fatalf("failed to run simulation: %v", err)
+	}
+	if report.Blocks != 2 {
+		t.Errorf("block count mismatch: have %d, want %d", report.Blocks, 2)
+	}
+	if report.Transactions != 5 || report.Accepted != 4 {
+		t.Errorf("acceptance mismatch: have %d/%d, want %d/%d", report.Accepted, report.Transactions, 4, 5)
+	}
+	if report.Rejected["underpriced"] != 1 {
+		t.Errorf("rejection mismatch: have %v", report.Rejected)
+	}
+	if report.Replaced != 1 {
+		t.Errorf("replacement mismatch: have %d, want %d", report.Replaced, 1)
+	}
+	if report.Included != 3 || report.IncludedBlobs != 4 {
+		t.Errorf("inclusion mismatch: have %d (%d blobs), want %d (%d blobs)", report.Included, report.IncludedBlobs, 3, 4)
+	}
+	if report.Evicted != 0 || report.Pending != 0 {
+		t.Errorf("leftover mismatch: evicted %d, pending %d", report.Evicted, report.Pending)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+)
+
+// traceEntry is a single event of a recorded trace. Exactly one of the fields
+// is set: either a blob transaction arriving at the pool, or a new block being
+// produced with the given fee environment.
+type traceEntry struct {
+	Tx    *txRecord    `json:"tx,omitempty"`
+	Block *blockRecord `json:"block,omitempty"`
+}
+
+// txRecord contains the fields of a blob transaction relevant for the pool. The
+// blobs themselves are not recorded, the simulator fills in synthetic ones.
+type txRecord struct {
+	From       common.Address        `json:"from"`
+	Nonce      math.HexOrDecimal64   `json:"nonce"`
+	GasTipCap  *math.HexOrDecimal256 `json:"maxPriorityFeePerGas"`
+	GasFeeCap  *math.HexOrDecimal256 `json:"maxFeePerGas"`
+	BlobFeeCap *math.HexOrDecimal256 `json:"maxFeePerBlobGas"`
+	Blobs      int                   `json:"blobs"`
+}
+
+// blockRecord contains the header fields of a block which determine the fees
+// of itself and its descendant.
+type blockRecord struct {
+	Number        math.HexOrDecimal64   `json:"number"`
+	Time          math.HexOrDecimal64   `json:"timestamp"`
+	GasLimit      math.HexOrDecimal64   `json:"gasLimit"`
+	GasUsed       math.HexOrDecimal64   `json:"gasUsed"`
+	BaseFee       *math.HexOrDecimal256 `json:"baseFeePerGas"`
+	ExcessBlobGas math.HexOrDecimal64   `json:"excessBlobGas"`
+}
+
+// readTrace parses a stream of JSON encoded trace entries.
+func readTrace(r io.Reader) ([]*traceEntry, error) {
+	var (
+		dec   = json.NewDecoder(r)
+		trace []*traceEntry
+	)
+	for {
+		entry := new(traceEntry)
+		if err := dec.Decode(entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return trace, nil
+			}
+			return nil, fmt.Errorf("entry %d: %v", len(trace), err)
+		}
+		if err := entry.validate(); err != nil {
+			return nil, fmt.Errorf("entry %d: %v", len(trace), err)
+		}
+		trace = append(trace, entry)
+	}
+}
+
+// validate checks that the trace entry is well formed.
+func (e *traceEntry) validate() error {
+	switch {
+	case e.Tx != nil && e.Block != nil:
+		return errors.New("both transaction and block set")
+	case e.Tx != nil:
+		if e.Tx.GasTipCap == nil || e.Tx.GasFeeCap == nil || e.Tx.BlobFeeCap == nil {
+			return errors.New("missing transaction fee caps")
+		}
+		if e.Tx.Blobs <= 0 {
+			return fmt.Errorf("invalid blob count %d", e.Tx.Blobs)
+		}
+	case e.Block != nil:
+		if e.Block.BaseFee == nil {
+			return errors.New("missing block base fee")
+		}
+	default:
+		return errors.New("neither transaction nor block set")
+	}
+	return nil
+}
+
+// newTxRecord creates a trace record from a blob transaction.
+func newTxRecord(from common.Address, tx *types.Transaction) *txRecord {
+	return &txRecord{
+		From:       from,
+		Nonce:      math.HexOrDecimal64(tx.Nonce()),
+		GasTipCap:  (*math.HexOrDecimal256)(tx.GasTipCap()),
+		GasFeeCap:  (*math.HexOrDecimal256)(tx.GasFeeCap()),
+		BlobFeeCap: (*math.HexOrDecimal256)(tx.BlobGasFeeCap()),
+		Blobs:      len(tx.BlobHashes()),
+	}
+}
+
+// newBlockRecord creates a trace record from a block header.
+func newBlockRecord(header *types.Header) *blockRecord {
+	rec := &blockRecord{
+		Number:   math.HexOrDecimal64(header.Number.Uint64()),
+		Time:     math.HexOrDecimal64(header.Time),
+		GasLimit: math.HexOrDecimal64(header.GasLimit),
+		GasUsed:  math.HexOrDecimal64(header.GasUsed),
+		BaseFee:  (*math.HexOrDecimal256)(header.BaseFee),
+	}
+	if header.ExcessBlobGas != nil {
+		rec.ExcessBlobGas = math.HexOrDecimal64(*header.ExcessBlobGas)
+	}
+	return rec
+}
+
+// recordTrace retrieves a range of blocks from a remote node and writes the blob
+// transactions and fee environments within into a trace.
+func recordTrace(ctx *cli.Context) error {
+	client, err := ethclient.Dial(ctx.String(rpcFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return err
+	}
+	var (
+		signer = types.LatestSignerForChainID(chainID)
+		from   = ctx.Uint64(fromFlag.Name)
+		to     = ctx.Uint64(toFlag.Name)
+	)
+	if to == 0 {
+		if to, err = client.BlockNumber(context.Background()); err != nil {
+			return err
+		}
+	}
+	if from > to {
+		return fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+	out := io.Writer(os.Stdout)
+	if path := ctx.String(outFlag.Name); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	enc := json.NewEncoder(out)
+	for number := from; number <= to; number++ {
+		block, err := client.BlockByNumber(context.Background(), new(big.Int).SetUint64(number))
+		if err != nil {
+			return fmt.Errorf("failed to retrieve block %d: %v", number, err)
+		}
+		var blobs int
+		for _, tx := range block.Transactions() {
+			if tx.Type() != types.BlobTxType {
+				continue
+			}
+			sender, err := types.Sender(signer, tx)
+			if err != nil {
+				return fmt.Errorf("failed to recover sender of %x: %v", tx.Hash(), err)
+			}
+			if err := enc.Encode(&traceEntry{Tx: newTxRecord(sender, tx)}); err != nil {
+				return err
+			}
+			blobs += len(tx.BlobHashes())
+		}
+		if err := enc.Encode(&traceEntry{Block: newBlockRecord(block.Header())}); err != nil {
+			return err
+		}
+		log.Info("Recorded block", "number", number, "blobs", blobs)
+	}
+	return nil
+}
@@ -28,8 +28,8 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/erae"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/params"
@@ -59,8 +59,20 @@ var (
 		Name:  "txs",
 		Usage: "print full transaction values",
 	}
+	summariesFlag = &cli.StringFlag{
+		Name:  "summaries",
+		Usage: "file of newline-delimited block summary roots of the beacon historical summaries, to verify the proofs of post-merge era files",
+	}
 )
 
+// capellaPeriods are the historical summary periods of the Capella fork, which
+// is when the beacon state started accumulating historical summaries.
+var capellaPeriods = map[string]uint64{
+	"mainnet": 758,
+	"sepolia": 222,
+	"hoodi":   0,
+}
+
 var (
 	blockCommand = &cli.Command{
 		Name:      "block",
@@ -80,8 +92,11 @@ var (
 	verifyCommand = &cli.Command{
 		Name:      "verify",
 		ArgsUsage: "<expected>",
-		Usage:     "verifies each era1 against expected accumulator root",
+		Usage:     "verifies each era1 and erae file against expected accumulator root",
 		Action:    verify,
+		Flags: []cli.Flag{
+			summariesFlag,
+		},
 	}
 )
 
@@ -202,16 +217,43 @@ func verify(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("error reading %s: %w", dir, err)
 	}
+	postMerge, err := erae.ReadDir(dir, network)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", dir, err)
+	}
+	entries = append(entries, postMerge...)
 
 	if len(entries) != len(roots) {
-		return errors.New("number of era1 files should match the number of accumulator hashes")
+		return errors.New("number of era files should match the number of accumulator hashes")
+	}
+	// Load the historical summaries if the post-merge proofs should be verified.
+	var summaries []common.Hash
+	if path := ctx.String(summariesFlag.Name); path != "" {
+		if summaries, err = readHashes(path); err != nil {
+			return fmt.Errorf("unable to read summaries file: %w", err)
+		}
 	}
-
 	// Verify each epoch matches the expected root.
 	for i, want := range roots {
 		// Wrap in function so defers don't stack.
 		err := func() error {
 			name := entries[i]
+			if filepath.Ext(name) == ".erae" {
+				e, err := erae.Open(filepath.Join(dir, name))
+				if err != nil {
+					return fmt.Errorf("error opening erae file %s: %w", name, err)
+				}
+				defer e.Close()
+				if got, err := e.Accumulator(); err != nil {
+					return fmt.Errorf("error retrieving accumulator for %s: %w", name, err)
+				} else if got != want {
+					return fmt.Errorf("invalid root %s: got %s, want %s", name, got, want)
+				}
+				if err := e.Verify(trie.NewStackTrie(nil), summaries, capellaPeriods[network]); err != nil {
+					return fmt.Errorf("error verify erae file %s: %w", name, err)
+				}
+				return nil
+			}
 			e, err := era.Open(filepath.Join(dir, name))
 			if err != nil {
 				return fmt.Errorf("error opening era1 file %s: %w", name, err)
@@ -224,84 +266,21 @@ func verify(ctx *cli.Context) error {
 				return fmt.Errorf("invalid root %s: got %s, want %s", name, got, want)
 			}
 			// Recompute accumulator.
-			if err := checkAccumulator(e); err != nil {
+			if err := e.Verify(trie.NewStackTrie(nil)); err != nil {
 				return fmt.Errorf("error verify era1 file %s: %w", name, err)
 			}
-			// Give the user some feedback that something is happening.
-			if time.Since(reported) >= 8*time.Second {
-				fmt.Printf("Verifying Era1 files \t\t verified=%d,\t elapsed=%s\n", i, common.PrettyDuration(time.Since(start)))
-				reported = time.Now()
-			}
 			return nil
 		}()
 		if err != nil {
 			return err
 		}
-	}
-
-	return nil
-}
-
-// checkAccumulator verifies the accumulator matches the data in the Era.
-func checkAccumulator(e *era.Era) error {
-	var (
-		err    error
-		want   common.Hash
-		td     *big.Int
-		tds    = make([]*big.Int, 0)
-		hashes = make([]common.Hash, 0)
-	)
-	if want, err = e.Accumulator(); err != nil {
-		return fmt.Errorf("error reading accumulator: %w", err)
-	}
-	if td, err = e.InitialTD(); err != nil {
-		return fmt.Errorf("error reading total difficulty: %w", err)
-	}
-	it, err := era.NewIterator(e)
-	if err != nil {
-		return fmt.Errorf("error making era iterator: %w", err)
-	}
-	// To fully verify an era the following attributes must be checked:
-	//   1) the block index is constructed correctly
-	//   2) the tx root matches the value in the block
-	//   3) the receipts root matches the value in the block
-	//   4) the starting total difficulty value is correct
-	//   5) the accumulator is correct by recomputing it locally, which verifies
-	//      the blocks are all correct (via hash)
-	//
-	// The attributes 1), 2), and 3) are checked for each block. 4) and 5) require
-	// accumulation across the entire set and are verified at the end.
-	for it.Next() {
-		// 1) next() walks the block index, so we're able to implicitly verify it.
-		if it.Error() != nil {
-			return fmt.Errorf("error reading block %d: %w", it.Number(), err)
-		}
-		block, receipts, err := it.BlockAndReceipts()
-		if it.Error() != nil {
-			return fmt.Errorf("error reading block %d: %w", it.Number(), err)
-		}
-		// 2) recompute tx root and verify against header.
-		tr := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil))
-		if tr != block.TxHash() {
-			return fmt.Errorf("tx root in block %d mismatch: want %s, got %s", block.NumberU64(), block.TxHash(), tr)
-		}
-		// 3) recompute receipt root and check value against block.
-		rr := types.DeriveSha(receipts, trie.NewStackTrie(nil))
-		if rr != block.ReceiptHash() {
-			return fmt.Errorf("receipt root in block %d mismatch: want %s, got %s", block.NumberU64(), block.ReceiptHash(), rr)
+		// Give the user some feedback that something is happening.
+		if time.Since(reported) >= 8*time.Second {
+			fmt.Printf("Verifying Era files \t\t verified=%d,\t elapsed=%s\n", i, common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
 		}
-		hashes = append(hashes, block.Hash())
-		td.Add(td, block.Difficulty())
-		tds = append(tds, new(big.Int).Set(td))
-	}
-	// 4+5) Verify accumulator and total difficulty.
-	got, err := era.ComputeAccumulator(hashes, tds)
-	if err != nil {
-		return fmt.Errorf("error computing accumulator: %w", err)
-	}
-	if got != want {
-		return fmt.Errorf("expected accumulator root does not match calculated: got %s, want %s", got, want)
 	}
+
 	return nil
 }
 
@@ -17,6 +17,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -37,12 +38,27 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/eradl"
+	"github.com/ethereum/go-ethereum/internal/era/erae"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/urfave/cli/v2"
 )
 
 var (
+	historyMirrorFlag = &cli.StringFlag{
+		Name:  "mirror",
+		Usage: "Base URL (http(s):// or file://) of an era file mirror to download the missing files from",
+	}
+	historyManifestFlag = &cli.StringFlag{
+		Name:  "mirror.checksums",
+		Usage: "URL of the checksum manifest of the mirror, relative to the mirror (default = checksums.txt)",
+	}
+	historyRootsFlag = &cli.StringFlag{
+		Name:  "mirror.roots",
+		Usage: "File of newline-delimited trusted accumulator roots, ordered by epoch, to verify the downloaded era files against",
+	}
+
 	initCommand = &cli.Command{
 		Action:    initGenesis,
 		Name:      "init",
@@ -133,13 +149,22 @@ be gzipped.`,
 		ArgsUsage: "<dir>",
 		Flags: slices.Concat([]cli.Flag{
 			utils.TxLookupLimitFlag,
+			historyMirrorFlag,
+			historyManifestFlag,
+			historyRootsFlag,
 		},
 			utils.DatabaseFlags,
 			utils.NetworkFlags,
 		),
 		Description: `
 The import-history command will import blocks and their corresponding receipts
-from Era archives.
+from Era archives, using the Era1 format (.era1) for pre-merge history and the
+post-merge era format (.erae) for the rest.
+
+If --mirror is given, the era files of the selected network missing from <dir>
+are first downloaded from the mirror and verified against its checksum manifest
+and the trusted accumulator roots given by --mirror.roots, in the format of the
+'era verify' roots file. Interrupted downloads are resumed.
 `,
 	}
 	exportHistoryCommand = &cli.Command{
@@ -150,7 +175,9 @@ from Era archives.
 		Flags:     slices.Concat(utils.DatabaseFlags),
 		Description: `
 The export-history command will export blocks and their corresponding receipts
-into Era archives. Eras are typically packaged in steps of 8192 blocks.
+into Era archives. Eras are typically packaged in steps of 8192 blocks. Steps
+holding only post-merge blocks are exported in the post-merge era format (.erae),
+the others in the Era1 format (.era1).
 `,
 	}
 	importPreimagesCommand = &cli.Command{
@@ -228,7 +255,7 @@ func initGenesis(ctx *cli.Context) error {
 	}
 	defer chaindb.Close()
 
-	triedb := utils.MakeTrieDatabase(ctx, chaindb, ctx.Bool(utils.CachePreimagesFlag.Name), false, genesis.IsVerkle())
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, ctx.Bool(utils.CachePreimagesFlag.Name), false, genesis.IsVerkle(), genesis.IsBinary())
 	defer triedb.Close()
 
 	_, hash, _, err := core.SetupGenesisBlockWithOverride(chaindb, triedb, genesis, &overrides)
@@ -420,6 +447,8 @@ func importHistory(ctx *cli.Context) error {
 		case ctx.Bool(utils.SepoliaFlag.Name):
 			network = "sepolia"
 		}
+	} else if ctx.IsSet(historyMirrorFlag.Name) {
+		return errors.New("downloading from a mirror requires a network flag")
 	} else {
 		// No network flag set, try to determine network based on files
 		// present in directory.
@@ -429,12 +458,16 @@ func importHistory(ctx *cli.Context) error {
 			if err != nil {
 				return fmt.Errorf("error reading %s: %w", dir, err)
 			}
-			if len(entries) > 0 {
+			postMerge, err := erae.ReadDir(dir, n)
+			if err != nil {
+				return fmt.Errorf("error reading %s: %w", dir, err)
+			}
+			if len(entries) > 0 || len(postMerge) > 0 {
 				networks = append(networks, n)
 			}
 		}
 		if len(networks) == 0 {
-			return fmt.Errorf("no era1 files found in %s", dir)
+			return fmt.Errorf("no era files found in %s", dir)
 		}
 		if len(networks) > 1 {
 			return errors.New("multiple networks found, use a network flag to specify desired network")
@@ -442,6 +475,22 @@ func importHistory(ctx *cli.Context) error {
 		network = networks[0]
 	}
 
+	if ctx.IsSet(historyMirrorFlag.Name) {
+		if !ctx.IsSet(historyRootsFlag.Name) {
+			return fmt.Errorf("downloading from a mirror requires --%s", historyRootsFlag.Name)
+		}
+		roots, err := eradl.ReadRoots(ctx.String(historyRootsFlag.Name))
+		if err != nil {
+			return fmt.Errorf("unable to read trusted roots: %w", err)
+		}
+		loader, err := eradl.New(ctx.String(historyMirrorFlag.Name), ctx.String(historyManifestFlag.Name), network, roots)
+		if err != nil {
+			return err
+		}
+		if err := loader.Download(context.Background(), dir); err != nil {
+			return fmt.Errorf("era download failed: %w", err)
+		}
+	}
 	if err := utils.ImportHistory(chain, db, dir, network); err != nil {
 		return err
 	}
@@ -575,7 +624,7 @@ func dump(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	triedb := utils.MakeTrieDatabase(ctx, db, true, true, false) // always enable preimage lookup
+	triedb := utils.MakeTrieDatabase(ctx, db, true, true, false, false) // always enable preimage lookup
 	defer triedb.Close()
 
 	state, err := state.New(root, state.NewDatabase(triedb, nil))
@@ -18,6 +18,7 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -41,6 +42,7 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
 	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli/v2"
 )
@@ -83,6 +85,8 @@ Remove blockchain and state databases`,
 			dbMetadataCmd,
 			dbCheckStateContentCmd,
 			dbInspectHistoryCmd,
+			dbScrubCmd,
+			dbScrubReportCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
@@ -229,6 +233,28 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		}, utils.NetworkFlags, utils.DatabaseFlags),
 		Description: "This command queries the history of the account or storage slot within the specified block range",
 	}
+	dbScrubCmd = &cli.Command{
+		Action: scrubTrieNodes,
+		Name:   "scrub",
+		Usage:  "Verify the integrity of the persisted trie nodes",
+		Flags: slices.Concat([]cli.Flag{
+			utils.SyncModeFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command walks the account and storage tries persisted by the path-based
+scheme, rehashing every node and comparing it against the reference held by its parent.
+An interrupted pass, either by this command or by the background scrubber (--state.scrub),
+is resumed. The corruptions found are recorded in the database and printed along with
+the suggested repairs.`,
+	}
+	dbScrubReportCmd = &cli.Command{
+		Action: showScrubReport,
+		Name:   "scrub-report",
+		Usage:  "Show the progress and the findings of the trie node scrubber",
+		Flags: slices.Concat([]cli.Flag{
+			utils.SyncModeFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: "This command shows the progress of the trie node scrubber, the corruptions found and the suggested repairs.",
+	}
 )
 
 func removeDB(ctx *cli.Context) error {
@@ -546,7 +572,7 @@ func dbDumpTrie(ctx *cli.Context) error {
 	db := utils.MakeChainDatabase(ctx, stack, true)
 	defer db.Close()
 
-	triedb := utils.MakeTrieDatabase(ctx, db, false, true, false)
+	triedb := utils.MakeTrieDatabase(ctx, db, false, true, false, false)
 	defer triedb.Close()
 
 	var (
@@ -881,7 +907,7 @@ func inspectHistory(ctx *cli.Context) error {
 	db := utils.MakeChainDatabase(ctx, stack, true)
 	defer db.Close()
 
-	triedb := utils.MakeTrieDatabase(ctx, db, false, false, false)
+	triedb := utils.MakeTrieDatabase(ctx, db, false, false, false, false)
 	defer triedb.Close()
 
 	var (
@@ -929,3 +955,81 @@ func inspectHistory(ctx *cli.Context) error {
 	}
 	return inspectStorage(triedb, start, end, address, slot, ctx.Bool("raw"))
 }
+
+func scrubTrieNodes(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	if scheme := rawdb.ReadStateScheme(db); scheme != rawdb.PathScheme {
+		return fmt.Errorf("trie node scrubbing is only supported by the path-based scheme, have %q", scheme)
+	}
+	if rawdb.ReadSnapSyncStatusFlag(db) == rawdb.StateSyncRunning {
+		return errors.New("state is being synced, the persisted trie is incomplete")
+	}
+	start := time.Now()
+	report := pathdb.Scrub(db)
+	log.Info("Scrubbed trie nodes", "pass", report.Pass, "accounts", report.Accounts, "storages", report.Storages,
+		"corrupted", report.Corrupted, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	printScrubReport(report)
+	return nil
+}
+
+func showScrubReport(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	report, err := pathdb.ReadScrubReport(db)
+	if err != nil {
+		return err
+	}
+	if report == nil {
+		fmt.Println("Trie node scrubber was never run")
+		return nil
+	}
+	printScrubReport(report)
+	return nil
+}
+
+// printScrubReport prints the progress and the findings of the trie node
+// scrubber, along with the suggested repairs.
+func printScrubReport(report *pathdb.ScrubReport) {
+	formatTime := func(t uint64) string {
+		if t == 0 {
+			return "-"
+		}
+		return time.Unix(int64(t), 0).Format(time.RFC3339)
+	}
+	fmt.Printf("Pass:          %d\n", report.Pass)
+	fmt.Printf("Progress:      %.2f%%\n", report.Progress()*100)
+	fmt.Printf("Started:       %s\n", formatTime(report.Started))
+	fmt.Printf("Finished:      %s\n", formatTime(report.Finished))
+	fmt.Printf("Account nodes: %d\n", report.Accounts)
+	fmt.Printf("Storage nodes: %d\n", report.Storages)
+	fmt.Printf("Corruptions:   %d\n", report.Corrupted)
+
+	if len(report.Corruptions) > 0 {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Owner", "Path", "Reason", "Expected", "Actual"})
+		for _, c := range report.Corruptions {
+			owner := "account trie"
+			if c.Owner != (common.Hash{}) {
+				owner = c.Owner.Hex()
+			}
+			table.Append([]string{owner, fmt.Sprintf("%x", []byte(c.Path)), c.Reason, c.Expected.Hex(), c.Actual.Hex()})
+		}
+		table.Render()
+	}
+	if suggestions := report.Suggestions(); len(suggestions) > 0 {
+		fmt.Println("Suggestions:")
+		for _, suggestion := range suggestions {
+			fmt.Printf("  - %s\n", suggestion)
+		}
+	}
+}
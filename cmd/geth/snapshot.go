@@ -17,16 +17,19 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"slices"
+	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
@@ -148,6 +151,36 @@ as the backend data source, making this command a lot faster.
 
 The argument is interpreted as block number or hash. If none is provided, the latest
 block is used.
+`,
+			},
+			{
+				Name:      "export",
+				Usage:     "Export the state snapshot into a portable file",
+				ArgsUsage: "<dumpfile> [<number|hash>]",
+				Action:    exportSnapshot,
+				Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth snapshot export <dumpfile> [<block number or hash>]
+will stream the accounts, storage slots and contract codes of the post-state
+of the specified block into a compressed and checksummed flat file, along with
+the block itself, its receipts and the canonical headers preceding it. The
+default export target is the HEAD block.
+`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import a state snapshot from a portable file",
+				ArgsUsage: "<dumpfile>",
+				Action:    importSnapshot,
+				Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth snapshot import <dumpfile>
+will restore a state exported with 'geth snapshot export', regenerating the
+state tries in the configured state scheme (--state.scheme) and verifying
+their root against the exported one. The exported block becomes the head of
+the chain. The headers below it are written into the ancient store, with their
+bodies and receipts pruned as if the history was expired. The database must be
+empty or hold nothing but the genesis block of the exported network.
 `,
 			},
 			{
@@ -217,7 +250,7 @@ func verifyState(ctx *cli.Context) error {
 		log.Error("Failed to load head block")
 		return errors.New("no head block")
 	}
-	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false)
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false, false)
 	defer triedb.Close()
 
 	snapConfig := snapshot.Config{
@@ -272,7 +305,7 @@ func traverseState(ctx *cli.Context) error {
 	chaindb := utils.MakeChainDatabase(ctx, stack, true)
 	defer chaindb.Close()
 
-	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false)
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false, false)
 	defer triedb.Close()
 
 	headBlock := rawdb.ReadHeadBlock(chaindb)
@@ -381,7 +414,7 @@ func traverseRawState(ctx *cli.Context) error {
 	chaindb := utils.MakeChainDatabase(ctx, stack, true)
 	defer chaindb.Close()
 
-	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false)
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false, false)
 	defer triedb.Close()
 
 	headBlock := rawdb.ReadHeadBlock(chaindb)
@@ -548,7 +581,7 @@ func dumpState(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	triedb := utils.MakeTrieDatabase(ctx, db, false, true, false)
+	triedb := utils.MakeTrieDatabase(ctx, db, false, true, false, false)
 	defer triedb.Close()
 
 	snapConfig := snapshot.Config{
@@ -619,6 +652,109 @@ func dumpState(ctx *cli.Context) error {
 	return nil
 }
 
+// exportSnapshot dumps the flat state of a snapshot to a file.
+func exportSnapshot(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		utils.Fatalf("This command requires one or two arguments.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	defer chaindb.Close()
+
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false, false)
+	defer triedb.Close()
+
+	headBlock := rawdb.ReadHeadBlock(chaindb)
+	if headBlock == nil {
+		log.Error("Failed to load head block")
+		return errors.New("no head block")
+	}
+	block := headBlock
+	if ctx.NArg() == 2 {
+		arg := ctx.Args().Get(1)
+		if hashish(arg) {
+			hash := common.HexToHash(arg)
+			if number := rawdb.ReadHeaderNumber(chaindb, hash); number != nil {
+				block = rawdb.ReadBlock(chaindb, hash, *number)
+			} else {
+				block = nil
+			}
+		} else {
+			number, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				return err
+			}
+			block = rawdb.ReadBlock(chaindb, rawdb.ReadCanonicalHash(chaindb, number), number)
+		}
+		if block == nil {
+			log.Error("Failed to load block", "block", arg)
+			return fmt.Errorf("block %s not found", arg)
+		}
+	}
+	snapConfig := snapshot.Config{
+		CacheSize:  256,
+		Recovery:   false,
+		NoBuild:    true,
+		AsyncBuild: false,
+	}
+	snaptree, err := snapshot.New(snapConfig, chaindb, triedb, headBlock.Root())
+	if err != nil {
+		log.Error("Failed to open snapshot tree", "err", err)
+		return err
+	}
+	fh, err := os.Create(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	writer := bufio.NewWriter(fh)
+	if err := snapshot.Export(writer, snaptree, block, chaindb); err != nil {
+		log.Error("Failed to export snapshot", "number", block.Number(), "root", block.Root(), "err", err)
+		return err
+	}
+	return writer.Flush()
+}
+
+// importSnapshot restores the state from a snapshot export.
+func importSnapshot(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, false)
+	defer chaindb.Close()
+
+	scheme, err := rawdb.ParseStateScheme(ctx.String(utils.StateSchemeFlag.Name), chaindb)
+	if err != nil {
+		return err
+	}
+	// Initialize the genesis block of the network if the database is empty
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, false, false, false)
+	_, _, _, err = core.SetupGenesisBlock(chaindb, triedb, utils.MakeGenesis(ctx))
+	triedb.Close()
+	if err != nil {
+		utils.Fatalf("Failed to set up genesis block: %v", err)
+	}
+	fh, err := os.Open(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	block, err := snapshot.Import(bufio.NewReader(fh), chaindb, scheme)
+	if err != nil {
+		log.Error("Failed to import snapshot", "err", err)
+		return err
+	}
+	log.Info("Imported the state", "number", block.Number(), "hash", block.Hash(), "root", block.Root(), "scheme", scheme)
+	return nil
+}
+
 // snapshotExportPreimages dumps the preimage data to a flat file.
 func snapshotExportPreimages(ctx *cli.Context) error {
 	if ctx.NArg() < 1 {
@@ -630,7 +766,7 @@ func snapshotExportPreimages(ctx *cli.Context) error {
 	chaindb := utils.MakeChainDatabase(ctx, stack, true)
 	defer chaindb.Close()
 
-	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false)
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false, false)
 	defer triedb.Close()
 
 	var root common.Hash
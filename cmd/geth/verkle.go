@@ -18,16 +18,28 @@ package main
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"slices"
+	"time"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	trieutils "github.com/ethereum/go-ethereum/trie/utils"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/database"
 	"github.com/ethereum/go-verkle"
 	"github.com/urfave/cli/v2"
 )
@@ -35,6 +47,19 @@ import (
 var (
 	zero [32]byte
 
+	verkleReferenceFlag = &cli.StringFlag{
+		Name:  "reference",
+		Usage: "Expected root of the converted tree to compare the result against",
+	}
+	verkleResetFlag = &cli.BoolFlag{
+		Name:  "reset",
+		Usage: "Discard the progress of any previous conversion and start over",
+	}
+	verkleBinaryFlag = &cli.BoolFlag{
+		Name:  "binary",
+		Usage: "Convert into an EIP-7864 binary trie instead of a verkle tree",
+	}
+
 	verkleCommand = &cli.Command{
 		Name:        "verkle",
 		Usage:       "A set of experimental verkle tree management commands",
@@ -61,6 +86,29 @@ This command takes a root commitment and attempts to rebuild the tree.
 geth verkle dump <state-root> <key 1> [<key 2> ...]
 This command will produce a dot file representing the tree, rooted at <root>.
 in which key1, key2, ... are expanded.
+ `,
+			},
+			{
+				Name:      "convert",
+				Usage:     "Convert a MPT state into a verkle or binary tree",
+				ArgsUsage: "[<state-root>]",
+				Action:    convertVerkle,
+				Flags: slices.Concat([]cli.Flag{
+					verkleReferenceFlag,
+					verkleResetFlag,
+					verkleBinaryFlag,
+				}, utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth verkle convert [<state-root>]
+This command walks the snapshot of the given state root, or of the head block
+if none is given, and inserts all accounts, codes and storage slots into a
+verkle tree stored in a separate database namespace, or into an EIP-7864
+binary trie if --binary is given. The preimages of the account addresses and
+storage keys must have been recorded (--cache.preimages).
+
+The conversion is checkpointed periodically and resumes from the last one when
+interrupted, unless --reset is given. The resulting root can be checked against
+an expected one with --reference.
  `,
 			},
 		},
@@ -212,3 +260,314 @@ func expandVerkle(ctx *cli.Context) error {
 	}
 	return nil
 }
+
+// verkleConversionKey is the key within the verkle namespace tracking the
+// progress of a state conversion.
+var verkleConversionKey = []byte("conversion")
+
+// conversionCheckpoint is the number of items (accounts, codes and storage
+// slots) converted, after which the converted tree is flushed to disk along
+// with the conversion progress.
+var conversionCheckpoint = 250_000
+
+// conversionProgress is the persisted progress of a state conversion.
+type conversionProgress struct {
+	Source   common.Hash // Root of the MPT state being converted
+	Root     common.Hash // Root of the converted tree up to the marker
+	Marker   []byte      // Last converted account hash, optionally followed by the last converted slot hash
+	Accounts uint64      // Number of accounts converted
+	Slots    uint64      // Number of storage slots converted
+	Codes    uint64      // Number of bytecodes converted
+	Done     bool        // Whether the conversion is complete
+	Binary   bool        `rlp:"optional"` // Whether the state is converted into a binary trie
+}
+
+// conversionTarget is the tree a MPT state is converted into.
+type conversionTarget interface {
+	UpdateAccount(addr common.Address, acc *types.StateAccount, codeLen int) error
+	UpdateContractCode(addr common.Address, codeHash common.Hash, code []byte) error
+	UpdateStorage(addr common.Address, key, value []byte) error
+	Commit(collectLeaf bool) (common.Hash, *trienode.NodeSet)
+}
+
+// namespaceNodeDB serves the nodes of a converted tree from its namespace, in
+// which they are stored by path.
+type namespaceNodeDB struct {
+	db ethdb.KeyValueReader
+}
+
+// NodeReader implements database.NodeDatabase.
+func (db *namespaceNodeDB) NodeReader(root common.Hash) (database.NodeReader, error) {
+	return db, nil
+}
+
+// Node implements database.NodeReader.
+func (db *namespaceNodeDB) Node(owner common.Hash, path []byte, hash common.Hash) ([]byte, error) {
+	return rawdb.ReadAccountTrieNode(db.db, path), nil
+}
+
+func convertVerkle(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return errors.New("too many arguments")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, false)
+	defer chaindb.Close()
+
+	var (
+		source common.Hash
+		err    error
+	)
+	if ctx.NArg() == 1 {
+		if source, err = parseRoot(ctx.Args().First()); err != nil {
+			log.Error("Failed to resolve state root", "err", err)
+			return err
+		}
+	} else {
+		headBlock := rawdb.ReadHeadBlock(chaindb)
+		if headBlock == nil {
+			return errors.New("no head block")
+		}
+		source = headBlock.Root()
+	}
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false, false)
+	defer triedb.Close()
+
+	var (
+		namespace  = rawdb.NewTable(chaindb, string(rawdb.VerklePrefix))
+		binaryTrie = ctx.Bool(verkleBinaryFlag.Name)
+		open       = func(root common.Hash) (conversionTarget, error) {
+			return trie.NewVerkleTrie(root, &namespaceNodeDB{namespace}, trieutils.NewPointCache(4096))
+		}
+	)
+	if binaryTrie {
+		open = func(root common.Hash) (conversionTarget, error) {
+			return trie.NewBinaryTrie(root, &namespaceNodeDB{namespace})
+		}
+	}
+	root, err := convertState(chaindb, triedb, namespace, source, ctx.Bool(verkleResetFlag.Name), binaryTrie, open)
+	if err != nil {
+		return err
+	}
+	return compareConvertedRoot(ctx, root)
+}
+
+// compareConvertedRoot checks the root of a converted tree against the expected
+// one, if any was given.
+func compareConvertedRoot(ctx *cli.Context, root common.Hash) error {
+	if !ctx.IsSet(verkleReferenceFlag.Name) {
+		return nil
+	}
+	reference, err := parseRoot(ctx.String(verkleReferenceFlag.Name))
+	if err != nil {
+		return err
+	}
+	if root != reference {
+		log.Error("Converted root mismatch", "root", root, "reference", reference)
+		return fmt.Errorf("converted root mismatch: have %x, want %x", root, reference)
+	}
+	log.Info("Converted root matches the reference", "root", root)
+	return nil
+}
+
+// convertState inserts all the accounts, codes and storage slots of the given
+// MPT state into a tree, resuming from the progress persisted in the namespace
+// of the tree, if any, unless reset is set. The binaryTrie flag tells whether
+// the tree opened is a binary trie rather than a verkle one. The root of the
+// converted tree is returned.
+func convertState(chaindb ethdb.Database, triedb *triedb.Database, namespace ethdb.Database, source common.Hash, reset bool, binaryTrie bool, open func(root common.Hash) (conversionTarget, error)) (common.Hash, error) {
+	progress := new(conversionProgress)
+	if blob, _ := namespace.Get(verkleConversionKey); len(blob) > 0 {
+		if err := rlp.DecodeBytes(blob, progress); err != nil {
+			return common.Hash{}, fmt.Errorf("invalid conversion progress: %v", err)
+		}
+		switch {
+		case reset:
+			log.Info("Discarding previous conversion", "source", progress.Source, "accounts", progress.Accounts, "slots", progress.Slots)
+			progress = new(conversionProgress)
+		case progress.Source != source:
+			return common.Hash{}, fmt.Errorf("conversion of state %x in progress, use --%s to discard it", progress.Source, verkleResetFlag.Name)
+		case progress.Binary != binaryTrie:
+			return common.Hash{}, fmt.Errorf("conversion into another tree type in progress (binary: %v), use --%s to discard it", progress.Binary, verkleResetFlag.Name)
+		case progress.Done:
+			log.Info("State already converted", "source", source, "root", progress.Root)
+			return progress.Root, nil
+		case len(progress.Marker) < common.HashLength:
+			return common.Hash{}, errors.New("invalid conversion progress marker")
+		default:
+			log.Info("Resuming state conversion", "source", source, "root", progress.Root, "accounts", progress.Accounts, "slots", progress.Slots, "at", common.BytesToHash(progress.Marker[:common.HashLength]))
+		}
+	}
+	progress.Source, progress.Binary = source, binaryTrie
+
+	snapConfig := snapshot.Config{
+		CacheSize:  256,
+		Recovery:   false,
+		NoBuild:    true,
+		AsyncBuild: false,
+	}
+	snaptree, err := snapshot.New(snapConfig, chaindb, triedb, source)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tree, err := open(progress.Root)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var (
+		start   = time.Now()
+		logged  = time.Now()
+		pending int
+	)
+	// checkpoint flushes the converted tree along with the conversion progress,
+	// and reopens the tree to release the memory held by the flushed nodes.
+	checkpoint := func(marker []byte, done bool) error {
+		root, nodes := tree.Commit(false)
+
+		progress.Root, progress.Marker, progress.Done = root, marker, done
+		blob, err := rlp.EncodeToBytes(progress)
+		if err != nil {
+			return err
+		}
+		batch := namespace.NewBatch()
+		for path, node := range nodes.Nodes {
+			if node.IsDeleted() {
+				rawdb.DeleteAccountTrieNode(batch, []byte(path))
+			} else {
+				rawdb.WriteAccountTrieNode(batch, []byte(path), node.Blob)
+			}
+		}
+		if err := batch.Put(verkleConversionKey, blob); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		pending = 0
+		tree, err = open(root)
+		return err
+	}
+	report := func(hash common.Hash) {
+		if time.Since(logged) < 8*time.Second {
+			return
+		}
+		done := float64(binary.BigEndian.Uint64(hash[:8])) / math.MaxUint64 * 100
+		log.Info("Converting state", "accounts", progress.Accounts, "slots", progress.Slots, "codes", progress.Codes,
+			"progress", fmt.Sprintf("%.2f%%", done), "elapsed", common.PrettyDuration(time.Since(start)))
+		logged = time.Now()
+	}
+	var seek common.Hash
+	if len(progress.Marker) >= common.HashLength {
+		seek = common.BytesToHash(progress.Marker[:common.HashLength])
+	}
+	accIt, err := snaptree.AccountIterator(source, seek)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer accIt.Release()
+
+	for accIt.Next() {
+		var (
+			hash    = accIt.Hash()
+			resumed bool        // Whether the account was partially converted
+			from    common.Hash // Last storage slot converted for the account
+		)
+		if len(progress.Marker) >= common.HashLength && hash == seek {
+			if len(progress.Marker) == common.HashLength {
+				continue
+			}
+			resumed, from = true, common.BytesToHash(progress.Marker[common.HashLength:])
+		}
+		account, err := types.FullAccount(accIt.Account())
+		if err != nil {
+			return common.Hash{}, err
+		}
+		preimage := rawdb.ReadPreimage(chaindb, hash)
+		if len(preimage) != common.AddressLength {
+			return common.Hash{}, fmt.Errorf("missing preimage of account %x", hash)
+		}
+		addr := common.BytesToAddress(preimage)
+
+		if account.Balance.ByteLen() > 16 {
+			return common.Hash{}, fmt.Errorf("balance of account %x exceeds 128 bits", addr)
+		}
+		if !resumed {
+			var code []byte
+			if codeHash := common.BytesToHash(account.CodeHash); codeHash != types.EmptyCodeHash {
+				if code = rawdb.ReadCode(chaindb, codeHash); len(code) == 0 {
+					return common.Hash{}, fmt.Errorf("missing code %x of account %x", codeHash, addr)
+				}
+			}
+			if err := tree.UpdateAccount(addr, account, len(code)); err != nil {
+				return common.Hash{}, err
+			}
+			if len(code) > 0 {
+				if err := tree.UpdateContractCode(addr, common.BytesToHash(account.CodeHash), code); err != nil {
+					return common.Hash{}, err
+				}
+				progress.Codes++
+				pending++
+			}
+			progress.Accounts++
+			pending++
+		}
+		if account.Root != types.EmptyRootHash {
+			stIt, err := snaptree.StorageIterator(source, hash, from)
+			if err != nil {
+				return common.Hash{}, err
+			}
+			for stIt.Next() {
+				slot := stIt.Hash()
+				if resumed && slot == from {
+					continue
+				}
+				key := rawdb.ReadPreimage(chaindb, slot)
+				if len(key) != common.HashLength {
+					stIt.Release()
+					return common.Hash{}, fmt.Errorf("missing preimage of slot %x of account %x", slot, addr)
+				}
+				_, value, _, err := rlp.Split(stIt.Slot())
+				if err != nil {
+					stIt.Release()
+					return common.Hash{}, err
+				}
+				if err := tree.UpdateStorage(addr, key, value); err != nil {
+					stIt.Release()
+					return common.Hash{}, err
+				}
+				progress.Slots++
+				pending++
+
+				if pending >= conversionCheckpoint {
+					if err := checkpoint(append(hash.Bytes(), slot.Bytes()...), false); err != nil {
+						stIt.Release()
+						return common.Hash{}, err
+					}
+				}
+				report(hash)
+			}
+			err = stIt.Error()
+			stIt.Release()
+			if err != nil {
+				return common.Hash{}, err
+			}
+		}
+		if pending >= conversionCheckpoint {
+			if err := checkpoint(hash.Bytes(), false); err != nil {
+				return common.Hash{}, err
+			}
+		}
+		report(hash)
+	}
+	if err := accIt.Error(); err != nil {
+		return common.Hash{}, err
+	}
+	if err := checkpoint(nil, true); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Converted state", "source", source, "root", progress.Root, "accounts", progress.Accounts,
+		"slots", progress.Slots, "codes", progress.Codes, "elapsed", common.PrettyDuration(time.Since(start)))
+	return progress.Root, nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	trieutils "github.com/ethereum/go-ethereum/trie/utils"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+	"github.com/urfave/cli/v2"
+)
+
+// makeConversionSource creates a state with a snapshot and the preimages of
+// all its keys, ready to be converted.
+func makeConversionSource(t *testing.T) (ethdb.Database, *triedb.Database, common.Hash) {
+	var (
+		db        = rawdb.NewMemoryDatabase()
+		tdb       = triedb.NewDatabase(db, triedb.HashDefaults)
+		statedb   = state.NewDatabase(tdb, nil)
+		preimages = make(map[common.Hash][]byte)
+	)
+	st, _ := state.New(types.EmptyRootHash, statedb)
+	for i := 0; i < 32; i++ {
+		addr := common.BigToAddress(uint256.NewInt(uint64(i + 1)).ToBig())
+		preimages[crypto.Keccak256Hash(addr.Bytes())] = addr.Bytes()
+
+		st.SetBalance(addr, uint256.NewInt(uint64(i+1)*1000), tracing.BalanceChangeUnspecified)
+		st.SetNonce(addr, uint64(i), tracing.NonceChangeUnspecified)
+		if i%4 == 0 {
+			st.SetCode(addr, []byte{byte(i), 0x60, 0x00})
+			for j := 0; j < 8; j++ {
+				key := common.BigToHash(uint256.NewInt(uint64(j)).ToBig())
+				preimages[crypto.Keccak256Hash(key.Bytes())] = key.Bytes()
+				st.SetState(addr, key, common.BigToHash(uint256.NewInt(uint64(i*100+j+1)).ToBig()))
+			}
+		}
+	}
+	root, err := st.Commit(0, false, false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := tdb.Commit(root, false); err != nil {
+		t.Fatalf("failed to flush state: %v", err)
+	}
+	rawdb.WritePreimages(db, preimages)
+
+	snaps, err := snapshot.New(snapshot.Config{CacheSize: 16}, db, tdb, root)
+	if err != nil {
+		t.Fatalf("failed to generate snapshot: %v", err)
+	}
+	if _, err := snaps.Journal(root); err != nil {
+		t.Fatalf("failed to journal snapshot: %v", err)
+	}
+	snaps.Release()
+	return db, tdb, root
+}
+
+// verkleOpener returns the function opening the converted tree stored in the
+// given namespace.
+func verkleOpener(namespace ethdb.Database) func(root common.Hash) (conversionTarget, error) {
+	return func(root common.Hash) (conversionTarget, error) {
+		return trie.NewVerkleTrie(root, &namespaceNodeDB{namespace}, trieutils.NewPointCache(1024))
+	}
+}
+
+// binaryOpener returns the function opening the converted binary trie stored
+// in the given namespace.
+func binaryOpener(namespace ethdb.Database) func(root common.Hash) (conversionTarget, error) {
+	return func(root common.Hash) (conversionTarget, error) {
+		return trie.NewBinaryTrie(root, &namespaceNodeDB{namespace})
+	}
+}
+
+// Tests that a conversion interrupted midway resumes from its last checkpoint
+// and converges on the same root as an uninterrupted one.
+func TestConvertVerkleResume(t *testing.T) { testConvertResume(t, false, verkleOpener) }
+func TestConvertBinaryResume(t *testing.T) { testConvertResume(t, true, binaryOpener) }
+
+func testConvertResume(t *testing.T, binaryTrie bool, opener func(ethdb.Database) func(common.Hash) (conversionTarget, error)) {
+	db, tdb, source := makeConversionSource(t)
+	defer tdb.Close()
+
+	// Convert the state in one go for the reference root
+	reference, err := convertState(db, tdb, rawdb.NewTable(db, "reference-"), source, false, binaryTrie, opener(rawdb.NewTable(db, "reference-")))
+	if err != nil {
+		t.Fatalf("failed to convert state: %v", err)
+	}
+	// Convert the state again with frequent checkpoints, failing midway
+	defer func(old int) { conversionCheckpoint = old }(conversionCheckpoint)
+	conversionCheckpoint = 5
+
+	var (
+		namespace = rawdb.NewTable(db, "resumed-")
+		open      = opener(namespace)
+		opened    int
+		errCrash  = errors.New("crash")
+	)
+	_, err = convertState(db, tdb, namespace, source, false, binaryTrie, func(root common.Hash) (conversionTarget, error) {
+		if opened++; opened > 4 {
+			return nil, errCrash
+		}
+		return open(root)
+	})
+	if !errors.Is(err, errCrash) {
+		t.Fatalf("conversion error mismatch: have %v, want %v", err, errCrash)
+	}
+	blob, err := namespace.Get(verkleConversionKey)
+	if err != nil {
+		t.Fatalf("conversion progress not persisted: %v", err)
+	}
+	progress := new(conversionProgress)
+	if err := rlp.DecodeBytes(blob, progress); err != nil {
+		t.Fatalf("failed to decode conversion progress: %v", err)
+	}
+	if progress.Done || len(progress.Marker) == 0 {
+		t.Fatalf("conversion not interrupted midway: done %v, marker %x", progress.Done, progress.Marker)
+	}
+	// Resume the conversion and check it against the reference root
+	root, err := convertState(db, tdb, namespace, source, false, binaryTrie, open)
+	if err != nil {
+		t.Fatalf("failed to resume conversion: %v", err)
+	}
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	verkleReferenceFlag.Apply(set)
+	if err := set.Set(verkleReferenceFlag.Name, reference.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	ctx := cli.NewContext(nil, set, nil)
+	if err := compareConvertedRoot(ctx, root); err != nil {
+		t.Fatalf("resumed conversion mismatch: %v", err)
+	}
+	if err := compareConvertedRoot(ctx, common.Hash{0x01}); err == nil {
+		t.Fatal("mismatching root accepted")
+	}
+	// Converting the same state again returns the persisted root
+	if again, err := convertState(db, tdb, namespace, source, false, binaryTrie, open); err != nil || again != root {
+		t.Fatalf("repeated conversion mismatch: have %x, %v, want %x", again, err, root)
+	}
+	// Converting into the other tree type requires the progress to be reset
+	if _, err := convertState(db, tdb, namespace, source, false, !binaryTrie, open); err == nil {
+		t.Fatal("conversion into another tree type accepted")
+	}
+}
+
+// Tests that the binary conversion produces a different tree than the verkle
+// one, and that the converted accounts can be read back from it.
+func TestConvertBinary(t *testing.T) {
+	db, tdb, source := makeConversionSource(t)
+	defer tdb.Close()
+
+	verkleRoot, err := convertState(db, tdb, rawdb.NewTable(db, "verkle-"), source, false, false, verkleOpener(rawdb.NewTable(db, "verkle-")))
+	if err != nil {
+		t.Fatalf("failed to convert state into verkle: %v", err)
+	}
+	namespace := rawdb.NewTable(db, "binary-")
+	binaryRoot, err := convertState(db, tdb, namespace, source, false, true, binaryOpener(namespace))
+	if err != nil {
+		t.Fatalf("failed to convert state into binary trie: %v", err)
+	}
+	if binaryRoot == verkleRoot {
+		t.Fatalf("binary root matches the verkle root %x", verkleRoot)
+	}
+	bt, err := trie.NewBinaryTrie(binaryRoot, &namespaceNodeDB{namespace})
+	if err != nil {
+		t.Fatalf("failed to open converted binary trie: %v", err)
+	}
+	addr := common.BigToAddress(uint256.NewInt(5).ToBig())
+	acc, err := bt.GetAccount(addr)
+	if err != nil {
+		t.Fatalf("failed to read account: %v", err)
+	}
+	if acc == nil || acc.Nonce != 4 || acc.Balance.Uint64() != 5000 {
+		t.Fatalf("account mismatch: have %+v, want nonce 4 and balance 5000", acc)
+	}
+}
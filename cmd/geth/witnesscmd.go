@@ -0,0 +1,182 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	witnessCommand = &cli.Command{
+		Name:  "witness",
+		Usage: "A set of commands for stateless block execution",
+		Description: `
+The witness commands produce and consume execution witnesses: the minimal set
+of trie nodes and contract codes a block accesses, which is sufficient to
+execute it without access to the full state. A witness exported from a synced
+node can be executed anywhere, e.g. to cross-check a block's state transition
+or to debug a stateless client.
+`,
+		Subcommands: []*cli.Command{
+			{
+				Name:      "export",
+				Usage:     "Export a block along with its execution witness",
+				ArgsUsage: "<number|hash> <filename>",
+				Action:    exportWitness,
+				Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth witness export <number|hash> <filename>
+re-executes the given block on top of its parent state, and writes the block,
+the witness of the state it accesses and the chain configuration into a JSON
+file. The parent state must be available in the database.
+`,
+			},
+			{
+				Name:      "execute",
+				Usage:     "Execute a block statelessly from an exported witness",
+				ArgsUsage: "<filename>",
+				Action:    executeWitness,
+				Description: `
+geth witness execute <filename>
+executes the block of a file produced by 'geth witness export' purely from its
+witness, without any database, and reports the computed post-state root and
+receipts root. The command fails if they differ from the ones in the header.
+`,
+			},
+		},
+	}
+)
+
+// witnessFile is the content of the files produced by 'geth witness export'.
+// The block and witness are RLP encoded.
+type witnessFile struct {
+	Config  *params.ChainConfig `json:"config"`
+	Block   hexutil.Bytes       `json:"block"`
+	Witness hexutil.Bytes       `json:"witness"`
+}
+
+func exportWitness(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("usage: %s", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, true)
+	defer db.Close()
+
+	var (
+		arg   = ctx.Args().First()
+		block *types.Block
+	)
+	if hashish(arg) {
+		block = chain.GetBlockByHash(common.HexToHash(arg))
+	} else {
+		number, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return err
+		}
+		block = chain.GetBlockByNumber(number)
+	}
+	if block == nil {
+		return fmt.Errorf("block %s not found", arg)
+	}
+	witness, err := chain.BuildWitness(block)
+	if err != nil {
+		return fmt.Errorf("failed to build witness for block %d: %v", block.NumberU64(), err)
+	}
+	blob, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return err
+	}
+	wit, err := rlp.EncodeToBytes(witness)
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(&witnessFile{Config: chain.Config(), Block: blob, Witness: wit}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(ctx.Args().Get(1), out, 0644); err != nil {
+		return err
+	}
+	log.Info("Exported block witness", "number", block.NumberU64(), "hash", block.Hash(), "size", common.StorageSize(len(wit)), "file", ctx.Args().Get(1))
+	return nil
+}
+
+func executeWitness(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("usage: %s", ctx.Command.ArgsUsage)
+	}
+	data, err := os.ReadFile(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	var file witnessFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+	if file.Config == nil {
+		return errors.New("missing chain config")
+	}
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(file.Block, block); err != nil {
+		return fmt.Errorf("invalid block: %v", err)
+	}
+	witness := new(stateless.Witness)
+	if err := rlp.DecodeBytes(file.Witness, witness); err != nil {
+		return fmt.Errorf("invalid witness: %v", err)
+	}
+	// Remove the computed fields from the block to force their recalculation
+	header := block.Header()
+	header.Root = common.Hash{}
+	header.ReceiptHash = common.Hash{}
+	task := types.NewBlockWithHeader(header).WithBody(*block.Body())
+
+	stateRoot, receiptRoot, err := core.ExecuteStateless(file.Config, vm.Config{}, task, witness)
+	if err != nil {
+		return fmt.Errorf("stateless execution failed: %v", err)
+	}
+	fmt.Printf("Block:        %d (%x)\n", block.NumberU64(), block.Hash())
+	fmt.Printf("State root:   %x\n", stateRoot)
+	fmt.Printf("Receipt root: %x\n", receiptRoot)
+
+	if stateRoot != block.Root() {
+		return fmt.Errorf("state root mismatch: computed %x, header %x", stateRoot, block.Root())
+	}
+	if receiptRoot != block.ReceiptHash() {
+		return fmt.Errorf("receipt root mismatch: computed %x, header %x", receiptRoot, block.ReceiptHash())
+	}
+	return nil
+}
@@ -44,6 +44,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/debug"
 	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/erae"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
@@ -245,15 +246,44 @@ func readList(filename string) ([]string, error) {
 	return strings.Split(string(b), "\n"), nil
 }
 
-// ImportHistory imports Era1 files containing historical block information,
-// starting from genesis.
+// historyIterator is the common interface of the Era1 and post-merge archive
+// iterators.
+type historyIterator interface {
+	Next() bool
+	Number() uint64
+	Error() error
+	Block() (*types.Block, error)
+	Receipts() (types.Receipts, error)
+}
+
+// readHistoryDir returns the Era1 archives of a directory followed by the
+// post-merge archives, ensuring they cover consecutive epochs.
+func readHistoryDir(dir, network string) ([]string, error) {
+	entries, err := era.ReadDir(dir, network)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", dir, err)
+	}
+	postMerge, err := erae.ReadDir(dir, network)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", dir, err)
+	}
+	if len(postMerge) > 0 {
+		if epoch := strings.Split(postMerge[0], "-")[1]; epoch != fmt.Sprintf("%05d", len(entries)) {
+			return nil, fmt.Errorf("missing epoch %d", len(entries))
+		}
+	}
+	return append(entries, postMerge...), nil
+}
+
+// ImportHistory imports Era1 and post-merge era files containing historical
+// block information, starting from genesis.
 func ImportHistory(chain *core.BlockChain, db ethdb.Database, dir string, network string) error {
 	if chain.CurrentSnapBlock().Number.BitLen() != 0 {
 		return errors.New("history import only supported when starting from genesis")
 	}
-	entries, err := era.ReadDir(dir, network)
+	entries, err := readHistoryDir(dir, network)
 	if err != nil {
-		return fmt.Errorf("error reading %s: %w", dir, err)
+		return err
 	}
 	checksums, err := readList(filepath.Join(dir, "checksums.txt"))
 	if err != nil {
@@ -287,14 +317,24 @@ func ImportHistory(chain *core.BlockChain, db ethdb.Database, dir string, networ
 			h.Reset()
 			buf.Reset()
 
-			// Import all block data from Era1.
-			e, err := era.From(f)
-			if err != nil {
-				return fmt.Errorf("error opening era: %w", err)
-			}
-			it, err := era.NewIterator(e)
-			if err != nil {
-				return fmt.Errorf("error making era reader: %w", err)
+			// Import all block data from the Era1 or post-merge archive.
+			var it historyIterator
+			if filepath.Ext(filename) == ".erae" {
+				e, err := erae.From(f)
+				if err != nil {
+					return fmt.Errorf("error opening era: %w", err)
+				}
+				if it, err = erae.NewIterator(e); err != nil {
+					return fmt.Errorf("error making era reader: %w", err)
+				}
+			} else {
+				e, err := era.From(f)
+				if err != nil {
+					return fmt.Errorf("error opening era: %w", err)
+				}
+				if it, err = era.NewIterator(e); err != nil {
+					return fmt.Errorf("error making era reader: %w", err)
+				}
 			}
 			for it.Next() {
 				block, err := it.Block()
@@ -405,7 +445,8 @@ func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, las
 }
 
 // ExportHistory exports blockchain history into the specified directory,
-// following the Era format.
+// following the Era1 format for pre-merge blocks and the post-merge era format
+// for batches made only of post-merge blocks.
 func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) error {
 	log.Info("Exporting blockchain history", "dir", dir)
 	if head := bc.CurrentBlock().Number.Uint64(); head < last {
@@ -432,14 +473,26 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 	}
 	for i := first; i <= last; i += step {
 		err := func() error {
-			filename := filepath.Join(dir, era.Filename(network, int(i/step), common.Hash{}))
+			// Archives made only of post-merge blocks don't carry difficulties
+			// and use the post-merge format, the others use Era1.
+			var (
+				postMerge = bc.GetHeaderByNumber(i).Difficulty.Sign() == 0
+				name      = era.Filename
+			)
+			if postMerge {
+				name = erae.Filename
+			}
+			filename := filepath.Join(dir, name(network, int(i/step), common.Hash{}))
 			f, err := os.Create(filename)
 			if err != nil {
 				return fmt.Errorf("could not create era file: %w", err)
 			}
 			defer f.Close()
 
-			w := era.NewBuilder(f)
+			var (
+				w  = era.NewBuilder(f)
+				we = erae.NewBuilder(f)
+			)
 			for j := uint64(0); j < step && j <= last-i; j++ {
 				var (
 					n     = i + j
@@ -452,17 +505,27 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 				if receipts == nil {
 					return fmt.Errorf("export failed on #%d: receipts not found", n)
 				}
+				if postMerge {
+					if err := we.Add(block, receipts, nil); err != nil {
+						return err
+					}
+					continue
+				}
 				td.Add(td, block.Difficulty())
 				if err := w.Add(block, receipts, new(big.Int).Set(td)); err != nil {
 					return err
 				}
 			}
-			root, err := w.Finalize()
+			finalize := w.Finalize
+			if postMerge {
+				finalize = we.Finalize
+			}
+			root, err := finalize()
 			if err != nil {
 				return fmt.Errorf("export failed to finalize %d: %w", step/i, err)
 			}
 			// Set correct filename with root.
-			os.Rename(filename, filepath.Join(dir, era.Filename(network, int(i/step), root)))
+			os.Rename(filename, filepath.Join(dir, name(network, int(i/step), root)))
 
 			// Compute checksum of entire Era1.
 			if _, err := f.Seek(0, io.SeekStart); err != nil {
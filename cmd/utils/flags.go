@@ -31,6 +31,7 @@ import (
 	"os"
 	"path/filepath"
 	godebug "runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -111,6 +112,11 @@ var (
 		Usage:    "Root directory for ancient data (default = inside chaindata)",
 		Category: flags.EthCategory,
 	}
+	AncientTierFlag = &flags.DirectoryFlag{
+		Name:     "datadir.ancient.tier",
+		Usage:    "Directory of a secondary storage tier the sealed ancient data files are offloaded to (e.g. a mounted object store bucket)",
+		Category: flags.EthCategory,
+	}
 	MinFreeDiskSpaceFlag = &flags.DirectoryFlag{
 		Name:     "datadir.minfreedisk",
 		Usage:    "Minimum free disk space in MB, once reached triggers auto shut down (default = --cache.gc converted to MB, 0 = disabled)",
@@ -266,6 +272,11 @@ var (
 		Value:    ethconfig.Defaults.StateHistory,
 		Category: flags.StateCategory,
 	}
+	StateScrubFlag = &cli.BoolFlag{
+		Name:     "state.scrub",
+		Usage:    "Verify the integrity of the persisted trie nodes in the background, only relevant in state.scheme=path",
+		Category: flags.StateCategory,
+	}
 	TransactionHistoryFlag = &cli.Uint64Flag{
 		Name:     "history.transactions",
 		Usage:    "Number of recent blocks to maintain transactions index for (default = about one year, 0 = entire chain)",
@@ -274,10 +285,21 @@ var (
 	}
 	ChainHistoryFlag = &cli.StringFlag{
 		Name:     "history.chain",
-		Usage:    `Blockchain history retention ("all" or "postmerge")`,
+		Usage:    `Blockchain history retention ("all", "postmerge" or "rolling")`,
 		Value:    ethconfig.Defaults.HistoryMode.String(),
 		Category: flags.StateCategory,
 	}
+	HistoryRetentionFlag = &cli.Uint64Flag{
+		Name:     "history.retention",
+		Usage:    "Number of recent blocks to keep bodies and receipts for in rolling history mode (default = about one year)",
+		Value:    ethconfig.Defaults.HistoryRetention,
+		Category: flags.StateCategory,
+	}
+	HistoryEraFlag = &flags.DirectoryFlag{
+		Name:     "history.era",
+		Usage:    "Directory of Era1 and post-merge era files to serve the chain history missing from the freezer",
+		Category: flags.StateCategory,
+	}
 	// Beacon client light sync settings
 	BeaconApiFlag = &cli.StringSliceFlag{
 		Name:     "beacon.api",
@@ -395,6 +417,17 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Lifetime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolPrivateLifetimeFlag = &cli.DurationFlag{
+		Name:     "txpool.privatelifetime",
+		Usage:    "Maximum amount of time private transactions are withheld from the network",
+		Value:    ethconfig.Defaults.TxPool.PrivateLifetime,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolPrivatePublishFlag = &cli.BoolFlag{
+		Name:     "txpool.privatepublish",
+		Usage:    "Publish private transactions to the network on expiry instead of dropping them",
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -450,6 +483,11 @@ var (
 		Usage:    "Disable heuristic state prefetch during block import (less CPU and disk IO, more time waiting for data)",
 		Category: flags.PerfCategory,
 	}
+	CacheHintPrefetchFlag = &cli.BoolFlag{
+		Name:     "cache.hintprefetch",
+		Usage:    "Prefetch the state hinted by transaction and block access lists before block import",
+		Category: flags.PerfCategory,
+	}
 	CachePreimagesFlag = &cli.BoolFlag{
 		Name:     "cache.preimages",
 		Usage:    "Enable recording the SHA3/keccak preimages of trie keys",
@@ -502,6 +540,22 @@ var (
 		Usage:    "0x prefixed public address for the pending block producer (not used for actual block production)",
 		Category: flags.MinerCategory,
 	}
+	MinerStrategiesFlag = &cli.StringFlag{
+		Name:     "miner.strategies",
+		Usage:    "Comma separated block building strategies competing for each payload (greedy, bundle, gascap, allowlist)",
+		Value:    miner.BundleStrategyName,
+		Category: flags.MinerCategory,
+	}
+	MinerStrategyGasCapFlag = &cli.Uint64Flag{
+		Name:     "miner.strategy.gascap",
+		Usage:    "Gas limit of the blocks built by the gascap strategy",
+		Category: flags.MinerCategory,
+	}
+	MinerStrategyAllowlistFlag = &cli.StringFlag{
+		Name:     "miner.strategy.allowlist",
+		Usage:    "Comma separated senders whose transactions are included by the allowlist strategy",
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	PasswordFileFlag = &cli.PathFlag{
@@ -527,6 +581,21 @@ var (
 		Usage:    "Name of tracer which should record internal VM operations (costly)",
 		Category: flags.VMCategory,
 	}
+	VMBlockAccessListFlag = &cli.BoolFlag{
+		Name:     "vm.blockaccesslist",
+		Usage:    "Record the EIP-7928 access list of imported blocks (experimental)",
+		Category: flags.VMCategory,
+	}
+	VMParallelFlag = &cli.BoolFlag{
+		Name:     "vm.parallel",
+		Usage:    "Execute the transactions of a block optimistically in parallel (experimental)",
+		Category: flags.VMCategory,
+	}
+	VMParallelVerifyFlag = &cli.BoolFlag{
+		Name:     "vm.parallel.verify",
+		Usage:    "Cross-check the parallel transaction execution against the sequential one (costly)",
+		Category: flags.VMCategory,
+	}
 	VMTraceJsonConfigFlag = &cli.StringFlag{
 		Name:     "vmtrace.jsonconfig",
 		Usage:    "Tracer configuration (JSON)",
@@ -949,6 +1018,7 @@ var (
 	DatabaseFlags = []cli.Flag{
 		DataDirFlag,
 		AncientFlag,
+		AncientTierFlag,
 		RemoteDBFlag,
 		DBEngineFlag,
 		StateSchemeFlag,
@@ -1372,6 +1442,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 		log.Info(fmt.Sprintf("Using %s as db engine", dbEngine))
 		cfg.DBEngine = dbEngine
 	}
+	if ctx.IsSet(AncientTierFlag.Name) {
+		cfg.AncientTier = ctx.String(AncientTierFlag.Name)
+	}
 	// deprecation notice for log debug flags (TODO: find a more appropriate place to put these?)
 	if ctx.IsSet(LogBacktraceAtFlag.Name) {
 		log.Warn("log.backtrace flag is deprecated")
@@ -1470,6 +1543,12 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.IsSet(TxPoolPrivateLifetimeFlag.Name) {
+		cfg.PrivateLifetime = ctx.Duration(TxPoolPrivateLifetimeFlag.Name)
+	}
+	if ctx.IsSet(TxPoolPrivatePublishFlag.Name) {
+		cfg.PrivatePublish = ctx.Bool(TxPoolPrivatePublishFlag.Name)
+	}
 }
 
 func setBlobPool(ctx *cli.Context, cfg *blobpool.Config) {
@@ -1504,6 +1583,23 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 		log.Warn("The flag --miner.newpayload-timeout is deprecated and will be removed, please use --miner.recommit")
 		cfg.Recommit = ctx.Duration(MinerNewPayloadTimeoutFlag.Name)
 	}
+	if ctx.IsSet(MinerStrategiesFlag.Name) {
+		cfg.Strategies = SplitAndTrim(ctx.String(MinerStrategiesFlag.Name))
+	}
+	if ctx.IsSet(MinerStrategyGasCapFlag.Name) {
+		cfg.StrategyGasCap = ctx.Uint64(MinerStrategyGasCapFlag.Name)
+	}
+	if slices.Contains(cfg.Strategies, miner.GasCapStrategyName) && cfg.StrategyGasCap == 0 {
+		Fatalf("The %s block building strategy requires --%s", miner.GasCapStrategyName, MinerStrategyGasCapFlag.Name)
+	}
+	if ctx.IsSet(MinerStrategyAllowlistFlag.Name) {
+		for _, addr := range SplitAndTrim(ctx.String(MinerStrategyAllowlistFlag.Name)) {
+			if !common.IsHexAddress(addr) {
+				Fatalf("Invalid allowlisted sender %q", addr)
+			}
+			cfg.StrategyAllowlist = append(cfg.StrategyAllowlist, common.HexToAddress(addr))
+		}
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1584,6 +1680,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 			Fatalf("--%s: %v", ChainHistoryFlag.Name, err)
 		}
 	}
+	if ctx.IsSet(HistoryRetentionFlag.Name) {
+		cfg.HistoryRetention = ctx.Uint64(HistoryRetentionFlag.Name)
+	}
+	if ctx.IsSet(HistoryEraFlag.Name) {
+		cfg.HistoryEraDir = ctx.String(HistoryEraFlag.Name)
+	}
 
 	if ctx.IsSet(NetworkIdFlag.Name) {
 		cfg.NetworkId = ctx.Uint64(NetworkIdFlag.Name)
@@ -1605,6 +1707,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(CacheNoPrefetchFlag.Name) {
 		cfg.NoPrefetch = ctx.Bool(CacheNoPrefetchFlag.Name)
 	}
+	if ctx.IsSet(CacheHintPrefetchFlag.Name) {
+		cfg.HintPrefetch = ctx.Bool(CacheHintPrefetchFlag.Name)
+	}
 	// Read the value from the flag no matter if it's set or not.
 	cfg.Preimages = ctx.Bool(CachePreimagesFlag.Name)
 	if cfg.NoPruning && !cfg.Preimages {
@@ -1614,6 +1719,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(StateHistoryFlag.Name) {
 		cfg.StateHistory = ctx.Uint64(StateHistoryFlag.Name)
 	}
+	if ctx.IsSet(StateScrubFlag.Name) {
+		cfg.StateScrub = ctx.Bool(StateScrubFlag.Name)
+	}
 	if ctx.IsSet(StateSchemeFlag.Name) {
 		cfg.StateScheme = ctx.String(StateSchemeFlag.Name)
 	}
@@ -1667,6 +1775,15 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		// TODO(fjl): force-enable this in --dev mode
 		cfg.EnablePreimageRecording = ctx.Bool(VMEnableDebugFlag.Name)
 	}
+	if ctx.IsSet(VMBlockAccessListFlag.Name) {
+		cfg.BlockAccessLists = ctx.Bool(VMBlockAccessListFlag.Name)
+	}
+	if ctx.IsSet(VMParallelFlag.Name) {
+		cfg.ParallelExecution = ctx.Bool(VMParallelFlag.Name)
+	}
+	if ctx.IsSet(VMParallelVerifyFlag.Name) {
+		cfg.ParallelExecutionVerify = ctx.Bool(VMParallelVerifyFlag.Name)
+	}
 
 	if ctx.IsSet(RPCGlobalGasCapFlag.Name) {
 		cfg.RPCGasCap = ctx.Uint64(RPCGlobalGasCapFlag.Name)
@@ -2119,6 +2236,7 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readonly bool) (*core.BlockCh
 	cache := &core.CacheConfig{
 		TrieCleanLimit:      ethconfig.Defaults.TrieCleanCache,
 		TrieCleanNoPrefetch: ctx.Bool(CacheNoPrefetchFlag.Name),
+		HintPrefetch:        ctx.Bool(CacheHintPrefetchFlag.Name),
 		TrieDirtyLimit:      ethconfig.Defaults.TrieDirtyCache,
 		TrieDirtyDisabled:   ctx.String(GCModeFlag.Name) == "archive",
 		TrieTimeLimit:       ethconfig.Defaults.TrieTimeout,
@@ -2145,6 +2263,10 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readonly bool) (*core.BlockCh
 	if ctx.IsSet(CacheFlag.Name) || ctx.IsSet(CacheGCFlag.Name) {
 		cache.TrieDirtyLimit = ctx.Int(CacheFlag.Name) * ctx.Int(CacheGCFlag.Name) / 100
 	}
+	cache.BlockAccessLists = ctx.Bool(VMBlockAccessListFlag.Name)
+	cache.ParallelExecution = ctx.Bool(VMParallelFlag.Name)
+	cache.ParallelVerify = ctx.Bool(VMParallelVerifyFlag.Name)
+
 	vmcfg := vm.Config{
 		EnablePreimageRecording: ctx.Bool(VMEnableDebugFlag.Name),
 	}
@@ -2184,10 +2306,11 @@ func MakeConsolePreloads(ctx *cli.Context) []string {
 }
 
 // MakeTrieDatabase constructs a trie database based on the configured scheme.
-func MakeTrieDatabase(ctx *cli.Context, disk ethdb.Database, preimage bool, readOnly bool, isVerkle bool) *triedb.Database {
+func MakeTrieDatabase(ctx *cli.Context, disk ethdb.Database, preimage bool, readOnly bool, isVerkle bool, isBinary bool) *triedb.Database {
 	config := &triedb.Config{
 		Preimages: preimage,
 		IsVerkle:  isVerkle,
+		IsBinary:  isBinary,
 	}
 	scheme, err := rawdb.ParseStateScheme(ctx.String(StateSchemeFlag.Name), disk)
 	if err != nil {
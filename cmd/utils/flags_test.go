@@ -18,8 +18,16 @@
 package utils
 
 import (
+	"flag"
+	"math/big"
+	"os"
 	"reflect"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/urfave/cli/v2"
 )
 
 func Test_SplitTagsFlag(t *testing.T) {
@@ -64,3 +72,52 @@ func Test_SplitTagsFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestAncientTierFlag(t *testing.T) {
+	var (
+		datadir = t.TempDir()
+		set     = flag.NewFlagSet("test", flag.ContinueOnError)
+	)
+	DataDirFlag.Apply(set)
+	AncientTierFlag.Apply(set)
+	if err := set.Parse([]string{"--datadir", datadir, "--datadir.ancient.tier", "tier"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := cli.NewContext(nil, set, nil)
+
+	cfg := node.DefaultConfig
+	SetNodeConfig(ctx, &cfg)
+	if cfg.AncientTier != "tier" {
+		t.Fatalf("Wrong ancient tier: have %q, want %q", cfg.AncientTier, "tier")
+	}
+	stack, err := node.New(&cfg)
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer stack.Close()
+
+	tier := stack.ResolveAncientTier()
+	if want := stack.ResolvePath("tier"); tier != want {
+		t.Fatalf("Wrong ancient tier path: have %s, want %s", tier, want)
+	}
+	// Write a block into the freezer of the chain database, the tier of the
+	// freezer must be set up.
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1)})
+	db := MakeChainDatabase(ctx, stack, false)
+	if _, err := rawdb.WriteAncientBlocks(db, []*types.Block{block}, []types.Receipts{nil}); err != nil {
+		t.Fatalf("Failed to write ancient block: %v", err)
+	}
+	db.Close()
+
+	if info, err := os.Stat(tier); err != nil || !info.IsDir() {
+		t.Fatalf("Ancient tier not created: %v", err)
+	}
+	// Reopen the chain database read-only through the tier, the block must
+	// remain readable.
+	db = MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	if header := rawdb.ReadHeader(db, block.Hash(), 0); header == nil || header.Hash() != block.Hash() {
+		t.Fatalf("Ancient block not readable: have %v", header)
+	}
+}
@@ -27,6 +27,8 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -34,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/erae"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/triedb"
@@ -45,18 +48,29 @@ var (
 )
 
 func TestHistoryImportAndExport(t *testing.T) {
+	testHistoryImportAndExport(t, params.TestChainConfig, func() consensus.Engine { return ethash.NewFaker() }, 0)
+}
+
+// Tests the export of a merged chain, whose genesis is the only pre-merge block,
+// the later batches being exported in the post-merge format.
+func TestPostMergeHistoryImportAndExport(t *testing.T) {
+	testHistoryImportAndExport(t, params.MergedTestChainConfig, func() consensus.Engine { return beacon.New(ethash.NewFaker()) }, count/step)
+}
+
+func testHistoryImportAndExport(t *testing.T, config *params.ChainConfig, engine func() consensus.Engine, postMerge uint64) {
 	var (
 		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
 		address = crypto.PubkeyToAddress(key.PublicKey)
 		genesis = &core.Genesis{
-			Config: params.TestChainConfig,
-			Alloc:  types.GenesisAlloc{address: {Balance: big.NewInt(1000000000000000000)}},
+			Config:     config,
+			Alloc:      types.GenesisAlloc{address: {Balance: big.NewInt(1000000000000000000)}},
+			Difficulty: params.GenesisDifficulty,
 		}
 		signer = types.LatestSigner(genesis.Config)
 	)
 
 	// Generate chain.
-	db, blocks, _ := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), int(count), func(i int, g *core.BlockGen) {
+	db, blocks, _ := core.GenerateChainWithGenesis(genesis, engine(), int(count), func(i int, g *core.BlockGen) {
 		if i == 0 {
 			return
 		}
@@ -78,7 +92,7 @@ func TestHistoryImportAndExport(t *testing.T) {
 	})
 
 	// Initialize BlockChain.
-	chain, err := core.NewBlockChain(db, nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil)
+	chain, err := core.NewBlockChain(db, nil, genesis, nil, engine(), vm.Config{}, nil)
 	if err != nil {
 		t.Fatalf("unable to initialize chain: %v", err)
 	}
@@ -102,7 +116,13 @@ func TestHistoryImportAndExport(t *testing.T) {
 	checksums := strings.Split(string(b), "\n")
 
 	// Verify each Era.
-	entries, _ := era.ReadDir(dir, "mainnet")
+	entries, err := readHistoryDir(dir, "mainnet")
+	if err != nil {
+		t.Fatalf("error reading era files: %v", err)
+	}
+	if have, _ := erae.ReadDir(dir, "mainnet"); uint64(len(have)) != postMerge {
+		t.Fatalf("post-merge era file count mismatch: have %d, want %d", len(have), postMerge)
+	}
 	for i, filename := range entries {
 		func() {
 			f, err := os.Open(filepath.Join(dir, filename))
@@ -119,24 +139,39 @@ func TestHistoryImportAndExport(t *testing.T) {
 			if got, want := common.BytesToHash(h.Sum(buf.Bytes()[:])).Hex(), checksums[i]; got != want {
 				t.Fatalf("checksum %d does not match: got %s, want %s", i, got, want)
 			}
-			e, err := era.From(f)
-			if err != nil {
-				t.Fatalf("error opening era: %v", err)
-			}
-			defer e.Close()
-			it, err := era.NewIterator(e)
-			if err != nil {
-				t.Fatalf("error making era reader: %v", err)
+			var it historyIterator
+			if filepath.Ext(filename) == ".erae" {
+				e, err := erae.From(f)
+				if err != nil {
+					t.Fatalf("error opening era: %v", err)
+				}
+				defer e.Close()
+				if it, err = erae.NewIterator(e); err != nil {
+					t.Fatalf("error making era reader: %v", err)
+				}
+			} else {
+				e, err := era.From(f)
+				if err != nil {
+					t.Fatalf("error opening era: %v", err)
+				}
+				defer e.Close()
+				if it, err = era.NewIterator(e); err != nil {
+					t.Fatalf("error making era reader: %v", err)
+				}
 			}
 			for j := 0; it.Next(); j++ {
 				n := i*int(step) + j
 				if it.Error() != nil {
 					t.Fatalf("error reading block entry %d: %v", n, it.Error())
 				}
-				block, receipts, err := it.BlockAndReceipts()
+				block, err := it.Block()
 				if err != nil {
 					t.Fatalf("error reading block entry %d: %v", n, err)
 				}
+				receipts, err := it.Receipts()
+				if err != nil {
+					t.Fatalf("error reading receipts entry %d: %v", n, err)
+				}
 				want := chain.GetBlockByNumber(uint64(n))
 				if want, got := uint64(n), block.NumberU64(); want != got {
 					t.Fatalf("blocks out of order: want %d, got %d", want, got)
@@ -167,7 +202,7 @@ func TestHistoryImportAndExport(t *testing.T) {
 	})
 
 	genesis.MustCommit(db2, triedb.NewDatabase(db2, triedb.HashDefaults))
-	imported, err := core.NewBlockChain(db2, nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil)
+	imported, err := core.NewBlockChain(db2, nil, genesis, nil, engine(), vm.Config{}, nil)
 	if err != nil {
 		t.Fatalf("unable to initialize chain: %v", err)
 	}
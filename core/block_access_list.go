@@ -0,0 +1,222 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"maps"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+// accessListRecorder assembles the EIP-7928 access list of a block while it is
+// being executed.
+//
+// The state hooks only signal which locations are modified by a transaction,
+// since they are also emitted for modifications reverted later on. The values
+// after a transaction are read back from the state once it's finished, and only
+// the ones which differ from the values before it are recorded. The locations
+// which are read are tracked by the state itself.
+type accessListRecorder struct {
+	statedb *state.StateDB
+	txs     int    // Number of transactions started so far
+	index   uint64 // Index the current modifications are attributed to
+
+	// Locations modified in the scope of the current index, along with their
+	// values prior to it
+	balances map[common.Address]*uint256.Int
+	nonces   map[common.Address]uint64
+	codes    map[common.Address]common.Hash
+	slots    map[common.Address]map[common.Hash]common.Hash
+
+	accounts map[common.Address]*types.AccountAccess
+	changes  map[common.Address]map[common.Hash][]types.StorageChange
+}
+
+// newAccessListRecorder creates a recorder for a block executed on top of the
+// given state, enabling the read tracking of the latter.
+func newAccessListRecorder(statedb *state.StateDB) *accessListRecorder {
+	statedb.TrackReads()
+	return &accessListRecorder{
+		statedb:  statedb,
+		balances: make(map[common.Address]*uint256.Int),
+		nonces:   make(map[common.Address]uint64),
+		codes:    make(map[common.Address]common.Hash),
+		slots:    make(map[common.Address]map[common.Hash]common.Hash),
+		accounts: make(map[common.Address]*types.AccountAccess),
+		changes:  make(map[common.Address]map[common.Hash][]types.StorageChange),
+	}
+}
+
+// hooks returns the tracing hooks feeding the recorder, chained after the given
+// ones, if any.
+func (r *accessListRecorder) hooks(inner *tracing.Hooks) *tracing.Hooks {
+	hooks := new(tracing.Hooks)
+	if inner != nil {
+		*hooks = *inner
+	}
+	hooks.OnTxStart = func(vm *tracing.VMContext, tx *types.Transaction, from common.Address) {
+		if inner != nil && inner.OnTxStart != nil {
+			inner.OnTxStart(vm, tx, from)
+		}
+		r.flush()
+		r.txs++
+		r.index = uint64(r.txs)
+	}
+	hooks.OnTxEnd = func(receipt *types.Receipt, err error) {
+		if inner != nil && inner.OnTxEnd != nil {
+			inner.OnTxEnd(receipt, err)
+		}
+		r.flush()
+		r.index = uint64(r.txs + 1)
+	}
+	hooks.OnSystemCallEnd = func() {
+		if inner != nil && inner.OnSystemCallEnd != nil {
+			inner.OnSystemCallEnd()
+		}
+		r.flush()
+	}
+	hooks.OnBalanceChange = func(addr common.Address, prev, new *big.Int, reason tracing.BalanceChangeReason) {
+		if inner != nil && inner.OnBalanceChange != nil {
+			inner.OnBalanceChange(addr, prev, new, reason)
+		}
+		if _, ok := r.balances[addr]; !ok {
+			r.balances[addr] = uint256.MustFromBig(prev)
+		}
+	}
+	hooks.OnNonceChange = nil
+	hooks.OnNonceChangeV2 = func(addr common.Address, prev, new uint64, reason tracing.NonceChangeReason) {
+		if inner != nil {
+			if inner.OnNonceChangeV2 != nil {
+				inner.OnNonceChangeV2(addr, prev, new, reason)
+			} else if inner.OnNonceChange != nil {
+				inner.OnNonceChange(addr, prev, new)
+			}
+		}
+		if _, ok := r.nonces[addr]; !ok {
+			r.nonces[addr] = prev
+		}
+	}
+	hooks.OnCodeChange = func(addr common.Address, prevCodeHash common.Hash, prevCode []byte, codeHash common.Hash, code []byte) {
+		if inner != nil && inner.OnCodeChange != nil {
+			inner.OnCodeChange(addr, prevCodeHash, prevCode, codeHash, code)
+		}
+		if _, ok := r.codes[addr]; !ok {
+			r.codes[addr] = prevCodeHash
+		}
+	}
+	hooks.OnStorageChange = func(addr common.Address, slot common.Hash, prev, new common.Hash) {
+		if inner != nil && inner.OnStorageChange != nil {
+			inner.OnStorageChange(addr, slot, prev, new)
+		}
+		slots, ok := r.slots[addr]
+		if !ok {
+			slots = make(map[common.Hash]common.Hash)
+			r.slots[addr] = slots
+		}
+		if _, ok := slots[slot]; !ok {
+			slots[slot] = prev
+		}
+	}
+	return hooks
+}
+
+// account returns the access record of the given account, creating it if needed.
+func (r *accessListRecorder) account(addr common.Address) *types.AccountAccess {
+	acc, ok := r.accounts[addr]
+	if !ok {
+		acc = &types.AccountAccess{Address: addr}
+		r.accounts[addr] = acc
+	}
+	return acc
+}
+
+// flush records the values of the locations modified in the scope of the
+// current index, if they differ from the ones prior to it.
+func (r *accessListRecorder) flush() {
+	index := hexutil.Uint64(r.index)
+	for addr, prev := range r.balances {
+		acc := r.account(addr)
+		if balance := r.statedb.GetBalance(addr); !balance.Eq(prev) {
+			acc.BalanceChanges = append(acc.BalanceChanges, types.BalanceChange{TxIndex: index, Balance: balance.Clone()})
+		}
+	}
+	for addr, prev := range r.nonces {
+		acc := r.account(addr)
+		if nonce := r.statedb.GetNonce(addr); nonce != prev {
+			acc.NonceChanges = append(acc.NonceChanges, types.NonceChange{TxIndex: index, Nonce: hexutil.Uint64(nonce)})
+		}
+	}
+	for addr, prev := range r.codes {
+		acc := r.account(addr)
+		if hash := r.statedb.GetCodeHash(addr); hash != prev {
+			acc.CodeChanges = append(acc.CodeChanges, types.CodeChange{TxIndex: index, Code: r.statedb.GetCode(addr)})
+		}
+	}
+	for addr, slots := range r.slots {
+		r.account(addr)
+		for slot, prev := range slots {
+			if value := r.statedb.GetState(addr, slot); value != prev {
+				changes, ok := r.changes[addr]
+				if !ok {
+					changes = make(map[common.Hash][]types.StorageChange)
+					r.changes[addr] = changes
+				}
+				changes[slot] = append(changes[slot], types.StorageChange{TxIndex: index, Value: value})
+			}
+		}
+	}
+	clear(r.balances)
+	clear(r.nonces)
+	clear(r.codes)
+	clear(r.slots)
+}
+
+// finish records the modifications of the post-execution operations and returns
+// the access list of the block.
+func (r *accessListRecorder) finish() *types.BlockAccessList {
+	r.flush()
+
+	reads := r.statedb.Reads()
+	for _, addr := range reads.Accounts() {
+		r.account(addr)
+	}
+	for addr := range r.accounts {
+		acc := r.accounts[addr]
+		for _, slot := range reads.Slots(addr) {
+			if _, ok := r.changes[addr][slot]; !ok {
+				acc.StorageReads = append(acc.StorageReads, slot)
+			}
+		}
+		slices.SortFunc(acc.StorageReads, common.Hash.Cmp)
+
+		for _, slot := range slices.SortedFunc(maps.Keys(r.changes[addr]), common.Hash.Cmp) {
+			acc.StorageChanges = append(acc.StorageChanges, types.SlotChanges{Slot: slot, Changes: r.changes[addr][slot]})
+		}
+	}
+	bal := new(types.BlockAccessList)
+	for _, addr := range slices.SortedFunc(maps.Keys(r.accounts), common.Address.Cmp) {
+		bal.Accounts = append(bal.Accounts, *r.accounts[addr])
+	}
+	return bal
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the access lists of imported blocks are recorded and validated
+// against the header extension.
+func TestBlockAccessList(t *testing.T) {
+	var (
+		config   = params.MergedTestChainConfig
+		engine   = beacon.New(ethash.NewFaker())
+		key, _   = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		coinbase = common.Address{0xcb}
+		counter  = common.HexToAddress("0xc0")
+		reverter = common.HexToAddress("0xc1")
+		gspec    = &Genesis{
+			Config: config,
+			Alloc: types.GenesisAlloc{
+				sender:                    {Balance: big.NewInt(params.Ether)},
+				params.BeaconRootsAddress: {Code: params.BeaconRootsCode},
+				// slot0 += 1
+				counter: {Code: []byte{byte(vm.PUSH1), 0, byte(vm.SLOAD), byte(vm.PUSH1), 1, byte(vm.ADD), byte(vm.PUSH1), 0, byte(vm.SSTORE), byte(vm.STOP)}},
+				// slot1 = 1, then revert
+				reverter: {Code: []byte{byte(vm.PUSH1), 1, byte(vm.PUSH1), 1, byte(vm.SSTORE), byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.REVERT)}},
+			},
+		}
+		signer = types.LatestSigner(config)
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 2, func(i int, b *BlockGen) {
+		b.SetCoinbase(coinbase)
+		b.SetParentBeaconRoot(common.Hash{byte(i + 1)})
+		for _, to := range []*common.Address{&counter, &reverter, nil} {
+			tx := types.MustSignNewTx(key, signer, &types.LegacyTx{
+				Nonce:    b.TxNonce(sender),
+				To:       to,
+				Gas:      100000,
+				GasPrice: new(big.Int).Add(b.header.BaseFee, big.NewInt(params.GWei)),
+				Data:     []byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.RETURN)},
+			})
+			b.AddTx(tx)
+		}
+	})
+	db := rawdb.NewMemoryDatabase()
+	cache := DefaultCacheConfigWithScheme(rawdb.HashScheme)
+	cache.BlockAccessLists = true
+	chain, err := NewBlockChain(db, cache, gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Failed to import blocks: %v", err)
+	}
+	bal := rawdb.ReadBlockAccessList(db, blocks[1].Hash(), 2)
+	if bal == nil {
+		t.Fatal("Block access list not recorded")
+	}
+	accounts := make(map[common.Address]types.AccountAccess)
+	for _, acc := range bal.Accounts {
+		accounts[acc.Address] = acc
+	}
+	// The sender pays for all the transactions
+	if changes := accounts[sender].NonceChanges; len(changes) != 3 || changes[0].TxIndex != 1 || changes[2].Nonce != 6 {
+		t.Errorf("Sender nonce changes mismatch: %v", changes)
+	}
+	if changes := accounts[coinbase].BalanceChanges; len(changes) != 3 {
+		t.Errorf("Coinbase balance changes mismatch: %v", changes)
+	}
+	// The counter is modified by the first transaction
+	if changes := accounts[counter].StorageChanges; len(changes) != 1 || changes[0].Changes[0].TxIndex != 1 || changes[0].Changes[0].Value != (common.Hash{31: 2}) {
+		t.Errorf("Counter storage changes mismatch: %v", changes)
+	}
+	// The reverted modification is only a read
+	if acc := accounts[reverter]; len(acc.StorageChanges) != 0 || len(acc.StorageReads) != 1 || acc.StorageReads[0] != (common.Hash{31: 1}) {
+		t.Errorf("Reverter storage access mismatch: changes %v, reads %v", acc.StorageChanges, acc.StorageReads)
+	}
+	// The contract is created by the last transaction
+	created := crypto.CreateAddress(sender, 5)
+	if changes := accounts[created].NonceChanges; len(changes) != 1 || changes[0].TxIndex != 3 {
+		t.Errorf("Created contract nonce changes mismatch: %v", changes)
+	}
+	// The beacon root is stored by the pre-execution system call
+	if changes := accounts[params.BeaconRootsAddress].StorageChanges; len(changes) != 2 || changes[0].Changes[0].TxIndex != 0 {
+		t.Errorf("Beacon root storage changes mismatch: %v", changes)
+	}
+	// Commit to the access list in the header of the last block, and ensure it's
+	// validated even if the recording is not enabled.
+	commit := func(hash common.Hash) []*types.Block {
+		header := blocks[1].Header()
+		header.BlockAccessListHash = &hash
+		return []*types.Block{blocks[0], types.NewBlockWithHeader(header).WithBody(*blocks[1].Body())}
+	}
+	for _, test := range []struct {
+		hash common.Hash
+		fail bool
+	}{
+		{bal.Hash(), false},
+		{common.Hash{0x01}, true},
+	} {
+		db := rawdb.NewMemoryDatabase()
+		chain, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil)
+		if err != nil {
+			t.Fatalf("Failed to create chain: %v", err)
+		}
+		blocks := commit(test.hash)
+		_, err = chain.InsertChain(blocks)
+		if test.fail && err == nil {
+			t.Error("Block with invalid access list hash imported")
+		}
+		if !test.fail {
+			if err != nil {
+				t.Errorf("Failed to import block with valid access list hash: %v", err)
+			} else if have := rawdb.ReadBlockAccessList(db, blocks[1].Hash(), 2); have == nil || have.Hash() != test.hash {
+				t.Error("Validated block access list not stored")
+			}
+		}
+		chain.Stop()
+	}
+}
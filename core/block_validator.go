@@ -156,6 +156,15 @@ func (v *BlockValidator) ValidateState(block *types.Block, statedb *state.StateD
 	} else if res.Requests != nil {
 		return errors.New("block has requests before prague fork")
 	}
+	// Validate the recorded access list against the header extension, if any.
+	if header.BlockAccessListHash != nil {
+		if res.AccessList == nil {
+			return errors.New("block access list not recorded")
+		}
+		if hash := res.AccessList.Hash(); hash != *header.BlockAccessListHash {
+			return fmt.Errorf("invalid block access list hash (remote: %x local: %x)", *header.BlockAccessListHash, hash)
+		}
+	}
 	// Validate the state root against the received state root and throw
 	// an error if they don't match.
 	if root := statedb.IntermediateRoot(v.config.IsEIP158(header.Number)); header.Root != root {
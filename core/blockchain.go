@@ -150,6 +150,7 @@ type CacheConfig struct {
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
 	Preimages           bool          // Whether to store preimage of trie key to the disk
 	StateHistory        uint64        // Number of blocks from head whose state histories are reserved.
+	StateScrub          bool          // Whether to verify the persisted trie nodes in the background
 	StateScheme         string        // Scheme used to store ethereum states and merkle tree nodes on top
 
 	SnapshotNoBuild bool // Whether the background generation is allowed
@@ -158,13 +159,23 @@ type CacheConfig struct {
 	// This defines the cutoff block for history expiry.
 	// Blocks before this number may be unavailable in the chain database.
 	HistoryPruningCutoff uint64
+
+	// HistoryRetention is the number of recent blocks whose bodies and receipts
+	// are kept, older history being pruned continuously. Zero disables it.
+	HistoryRetention uint64
+
+	BlockAccessLists  bool // Whether to record the EIP-7928 access list of imported blocks
+	ParallelExecution bool // Whether to execute the transactions of a block optimistically in parallel
+	ParallelVerify    bool // Whether to cross-check the parallel execution against the sequential one
+	HintPrefetch      bool // Whether to prefetch the state hinted by access lists before executing blocks
 }
 
 // triedbConfig derives the configures for trie database.
-func (c *CacheConfig) triedbConfig(isVerkle bool) *triedb.Config {
+func (c *CacheConfig) triedbConfig(isVerkle bool, isBinary bool) *triedb.Config {
 	config := &triedb.Config{
 		Preimages: c.Preimages,
 		IsVerkle:  isVerkle,
+		IsBinary:  isBinary,
 	}
 	if c.StateScheme == rawdb.HashScheme {
 		config.HashDB = &hashdb.Config{
@@ -176,6 +187,7 @@ func (c *CacheConfig) triedbConfig(isVerkle bool) *triedb.Config {
 			StateHistory:    c.StateHistory,
 			CleanCacheSize:  c.TrieCleanLimit * 1024 * 1024,
 			WriteBufferSize: c.TrieDirtyLimit * 1024 * 1024,
+			Scrub:           c.StateScrub,
 		}
 	}
 	return config
@@ -234,6 +246,7 @@ type BlockChain struct {
 	triedb        *triedb.Database                 // The database handler for maintaining trie nodes.
 	statedb       *state.CachingDB                 // State database to reuse between imports (contains state cache)
 	txIndexer     *txIndexer                       // Transaction indexer, might be nil if not enabled
+	expirer       *historyExpirer                  // Rolling history pruner, might be nil if not enabled
 
 	hc               *HeaderChain
 	rmLogsFeed       event.Feed
@@ -270,7 +283,8 @@ type BlockChain struct {
 	engine     consensus.Engine
 	validator  Validator // Block and state validator interface
 	prefetcher Prefetcher
-	processor  Processor // Block transaction processor interface
+	hints      *hintPrefetcher // Access list based state prefetcher
+	processor  Processor       // Block transaction processor interface
 	vmConfig   vm.Config
 	logger     *tracing.Hooks
 }
@@ -287,7 +301,11 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 	if err != nil {
 		return nil, err
 	}
-	triedb := triedb.NewDatabase(db, cacheConfig.triedbConfig(enableVerkle))
+	enableBinary, err := EnableBinaryAtGenesis(db, genesis)
+	if err != nil {
+		return nil, err
+	}
+	triedb := triedb.NewDatabase(db, cacheConfig.triedbConfig(enableVerkle, enableBinary))
 
 	// Write the supplied genesis to the database if it has not been initialized
 	// yet. The corresponding chain config will be returned, either from the
@@ -330,7 +348,12 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 	bc.statedb = state.NewDatabase(bc.triedb, nil)
 	bc.validator = NewBlockValidator(chainConfig, bc)
 	bc.prefetcher = newStatePrefetcher(chainConfig, bc.hc)
-	bc.processor = NewStateProcessor(chainConfig, bc.hc)
+	bc.hints = newHintPrefetcher(chainConfig)
+	if cacheConfig.ParallelExecution {
+		bc.processor = NewParallelStateProcessor(chainConfig, bc.hc, cacheConfig.ParallelVerify)
+	} else {
+		bc.processor = NewStateProcessor(chainConfig, bc.hc)
+	}
 
 	bc.genesisBlock = bc.GetBlockByNumber(0)
 	if bc.genesisBlock == nil {
@@ -484,6 +507,10 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 	if txLookupLimit != nil {
 		bc.txIndexer = newTxIndexer(*txLookupLimit, bc)
 	}
+	// Start rolling history expiry if it's enabled.
+	if cacheConfig.HistoryRetention > 0 {
+		bc.expirer = newHistoryExpirer(cacheConfig.HistoryRetention, bc)
+	}
 	return bc, nil
 }
 
@@ -1095,6 +1122,10 @@ func (bc *BlockChain) stopWithoutSaving() {
 	if bc.txIndexer != nil {
 		bc.txIndexer.close()
 	}
+	// Signal shutdown history expirer.
+	if bc.expirer != nil {
+		bc.expirer.close()
+	}
 	// Unsubscribe all subscriptions registered from blockchain.
 	bc.scope.Close()
 
@@ -1453,7 +1484,7 @@ func (bc *BlockChain) writeKnownBlock(block *types.Block) error {
 
 // writeBlockWithState writes block, metadata and corresponding state data to the
 // database.
-func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.Receipt, statedb *state.StateDB) error {
+func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.Receipt, accessList *types.BlockAccessList, statedb *state.StateDB) error {
 	if !bc.HasHeader(block.ParentHash(), block.NumberU64()-1) {
 		return consensus.ErrUnknownAncestor
 	}
@@ -1464,6 +1495,9 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	blockBatch := bc.db.NewBatch()
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
+	if accessList != nil {
+		rawdb.WriteBlockAccessList(blockBatch, block.Hash(), block.NumberU64(), accessList)
+	}
 	rawdb.WritePreimages(blockBatch, statedb.Preimages())
 	if err := blockBatch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
@@ -1535,8 +1569,8 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 
 // writeBlockAndSetHead is the internal implementation of WriteBlockAndSetHead.
 // This function expects the chain mutex to be held.
-func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, emitHeadEvent bool) (status WriteStatus, err error) {
-	if err := bc.writeBlockWithState(block, receipts, state); err != nil {
+func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types.Receipt, accessList *types.BlockAccessList, logs []*types.Log, state *state.StateDB, emitHeadEvent bool) (status WriteStatus, err error) {
+	if err := bc.writeBlockWithState(block, receipts, accessList, state); err != nil {
 		return NonStatTy, err
 	}
 	currentBlock := bc.CurrentBlock()
@@ -1721,6 +1755,13 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, makeWitness
 	// Track the singleton witness from this chain insertion (if any)
 	var witness *stateless.Witness
 
+	// Track the locations loaded by the execution prefetcher for the followup
+	// block, so they can be compared with the ones actually accessed by it.
+	var (
+		prefetchHash  common.Hash
+		prefetchReads chan *state.AccessSet
+	)
+
 	for ; block != nil && err == nil || errors.Is(err, ErrKnownBlock); block, err = it.next() {
 		// If the chain is terminating, stop processing blocks
 		if bc.insertStopped() {
@@ -1800,6 +1841,30 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, makeWitness
 		}
 		activeState = statedb
 
+		// Measure the accuracy of the prefetchers if hint prefetching and metrics
+		// are enabled. Tracking the reads is not free, so it's not done unless
+		// the comparison was opted into. Under parallel execution the reads are
+		// done on speculative copies, so the accessed state is not known.
+		measure := bc.cacheConfig.HintPrefetch && metrics.Enabled() && !bc.cacheConfig.ParallelExecution
+
+		// Schedule the state hinted by the available access lists for loading
+		// ahead of the execution.
+		var hints *state.AccessSet
+		if bc.cacheConfig.HintPrefetch && bc.chainConfig.IsByzantium(block.Number()) {
+			hints = bc.hints.Prefetch(block, statedb)
+		}
+		if measure {
+			statedb.TrackReads()
+		}
+
+		// Pick up the locations loaded for this block while the previous one was
+		// executing, before the prefetcher is reused for the followup.
+		var execReads chan *state.AccessSet
+		if prefetchReads != nil && prefetchHash == block.Hash() {
+			execReads = prefetchReads
+		}
+		prefetchReads = nil
+
 		// If we have a followup block, run that against the current state to pre-cache
 		// transactions and probabilistically some of the account/storage trie nodes.
 		var followupInterrupt atomic.Bool
@@ -1807,6 +1872,11 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, makeWitness
 			if followup, err := it.peek(); followup != nil && err == nil {
 				throwaway, _ := state.New(parent.Root, bc.statedb)
 
+				var reads chan *state.AccessSet
+				if measure {
+					throwaway.TrackReads()
+					reads = make(chan *state.AccessSet, 1)
+				}
 				go func(start time.Time, followup *types.Block, throwaway *state.StateDB) {
 					// Disable tracing for prefetcher executions.
 					vmCfg := bc.vmConfig
@@ -1817,7 +1887,12 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, makeWitness
 					if followupInterrupt.Load() {
 						blockPrefetchInterruptMeter.Mark(1)
 					}
+					if reads != nil {
+						reads <- throwaway.Reads()
+					}
 				}(time.Now(), followup, throwaway)
+
+				prefetchHash, prefetchReads = followup.Hash(), reads
 			}
 		}
 
@@ -1827,6 +1902,14 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, makeWitness
 		if err != nil {
 			return nil, it.index, err
 		}
+		if measure {
+			if hints != nil {
+				reportPrefetch(hints, statedb.Reads(), hintPrefetchHitMeter, hintPrefetchWasteMeter, hintPrefetchMissMeter)
+			}
+			if execReads != nil {
+				reportPrefetch(<-execReads, statedb.Reads(), execPrefetchHitMeter, execPrefetchWasteMeter, execPrefetchMissMeter)
+			}
+		}
 		// Report the import stats before returning the various results
 		stats.processed++
 		stats.usedGas += res.usedGas
@@ -1899,13 +1982,33 @@ func (bc *BlockChain) processBlock(block *types.Block, statedb *state.StateDB, s
 		}()
 	}
 
+	// Record the access list of the block if requested, or if it's committed
+	// to by the header and thus needs to be validated.
+	var (
+		vmConfig = bc.vmConfig
+		recorder *accessListRecorder
+		accesses *witnessAccessRecorder
+	)
+	if bc.cacheConfig.BlockAccessLists || block.Header().BlockAccessListHash != nil {
+		recorder = newAccessListRecorder(statedb)
+		vmConfig.Tracer = recorder.hooks(vmConfig.Tracer)
+	}
+	// Split the accesses by transaction if the witness is collected and its
+	// composition reported.
+	if statedb.Witness() != nil && metrics.Enabled() {
+		accesses = newWitnessAccessRecorder(statedb)
+		vmConfig.Tracer = accesses.hooks(vmConfig.Tracer)
+	}
 	// Process block using the parent state as reference point
 	pstart := time.Now()
-	res, err := bc.processor.Process(block, statedb, bc.vmConfig)
+	res, err := bc.processor.Process(block, statedb, vmConfig)
 	if err != nil {
 		bc.reportBlock(block, res, err)
 		return nil, err
 	}
+	if recorder != nil {
+		res.AccessList = recorder.finish()
+	}
 	ptime := time.Since(pstart)
 
 	vstart := time.Now()
@@ -1915,6 +2018,14 @@ func (bc *BlockChain) processBlock(block *types.Block, statedb *state.StateDB, s
 	}
 	vtime := time.Since(vstart)
 
+	// Analyzing the witness is expensive, do it in the background on a copy so
+	// the import is not held up and the witness can be further extended.
+	if accesses != nil {
+		go func(witness *stateless.Witness, accesses []*stateless.Accesses) {
+			reportWitnessStats(witness.Stats(accesses))
+		}(statedb.Witness().Copy(), accesses.finish())
+	}
+
 	// If witnesses was generated and stateless self-validation requested, do
 	// that now. Self validation should *never* run in production, it's more of
 	// a tight integration to enable running *all* consensus tests through the
@@ -1971,9 +2082,9 @@ func (bc *BlockChain) processBlock(block *types.Block, statedb *state.StateDB, s
 	)
 	if !setHead {
 		// Don't set the head, only insert the block
-		err = bc.writeBlockWithState(block, res.Receipts, statedb)
+		err = bc.writeBlockWithState(block, res.Receipts, res.AccessList, statedb)
 	} else {
-		status, err = bc.writeBlockAndSetHead(block, res.Receipts, res.Logs, statedb, false)
+		status, err = bc.writeBlockAndSetHead(block, res.Receipts, res.AccessList, res.Logs, statedb, false)
 	}
 	if err != nil {
 		return nil, err
@@ -2533,8 +2644,13 @@ func (bc *BlockChain) GetTrieFlushInterval() time.Duration {
 	return time.Duration(bc.flushInterval.Load())
 }
 
-// HistoryPruningCutoff returns the configured history pruning point.
+// HistoryPruningCutoff returns the history pruning point, which is either the
+// configured cutoff or the tail of the continuously expired history.
 // Blocks before this might not be available in the database.
 func (bc *BlockChain) HistoryPruningCutoff() uint64 {
-	return bc.cacheConfig.HistoryPruningCutoff
+	cutoff := bc.cacheConfig.HistoryPruningCutoff
+	if tail, err := bc.db.Tail(); err == nil && tail > cutoff {
+		cutoff = tail
+	}
+	return cutoff
 }
@@ -29,14 +29,19 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
 	"github.com/ethereum/go-ethereum/ethdb/pebble"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/triedb"
 )
 
 // snapshotTestBasic wraps the common testing fields in the snapshot tests.
@@ -712,3 +717,76 @@ func TestRecoverSnapshotFromWipingCrash(t *testing.T) {
 		test.teardown()
 	}
 }
+
+// Tests that a chain can be resumed from an imported snapshot export, with the
+// history below the imported block pruned.
+func TestSnapshotExportImport(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		gspec  = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 20, func(i int, b *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(addr), common.Address{0x01}, big.NewInt(1000), params.TxGas, b.header.BaseFee, nil), signer, key)
+		b.AddTx(tx)
+	})
+	// Export the state of the middle block from a node which synced up to it
+	source, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create source chain: %v", err)
+	}
+	defer source.Stop()
+
+	if _, err := source.InsertChain(blocks[:10]); err != nil {
+		t.Fatalf("failed to insert source chain: %v", err)
+	}
+	var export bytes.Buffer
+	if err := snapshot.Export(&export, source.Snapshots(), blocks[9], source.db); err != nil {
+		t.Fatalf("failed to export snapshot: %v", err)
+	}
+	// Import the snapshot into a new node and continue the chain on top of it
+	db, err := rawdb.NewDatabaseWithFreezer(memorydb.New(), "", "", false)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	if _, err := snapshot.Import(&export, db, rawdb.HashScheme); err != nil {
+		t.Fatalf("failed to import snapshot: %v", err)
+	}
+	chain, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create imported chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if head := chain.CurrentBlock(); head.Hash() != blocks[9].Hash() {
+		t.Fatalf("head block mismatch: have %d %x, want %d %x", head.Number, head.Hash(), 10, blocks[9].Hash())
+	}
+	if cutoff := chain.HistoryPruningCutoff(); cutoff != 10 {
+		t.Fatalf("history cutoff mismatch: have %d, want %d", cutoff, 10)
+	}
+	if header := chain.GetHeaderByNumber(5); header == nil || header.Hash() != blocks[4].Hash() {
+		t.Fatalf("pruned header mismatch: have %v, want %x", header, blocks[4].Hash())
+	}
+	if receipts := chain.GetReceiptsByHash(blocks[9].Hash()); len(receipts) != 1 {
+		t.Fatalf("imported block receipts mismatch: have %d, want %d", len(receipts), 1)
+	}
+	if _, err := chain.InsertChain(blocks[10:]); err != nil {
+		t.Fatalf("failed to extend imported chain: %v", err)
+	}
+	if head := chain.CurrentBlock(); head.Hash() != blocks[19].Hash() {
+		t.Fatalf("extended head mismatch: have %d %x, want %d %x", head.Number, head.Hash(), 20, blocks[19].Hash())
+	}
+	state, err := chain.State()
+	if err != nil {
+		t.Fatalf("failed to open head state: %v", err)
+	}
+	if balance := state.GetBalance(common.Address{0x01}); balance.Uint64() != 20*1000 {
+		t.Fatalf("balance mismatch: have %d, want %d", balance, 20*1000)
+	}
+}
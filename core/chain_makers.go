@@ -523,8 +523,11 @@ func GenerateVerkleChain(config *params.ChainConfig, parent *types.Block, engine
 			panic(fmt.Sprintf("trie write error: %v", err))
 		}
 
-		proofs = append(proofs, block.ExecutionWitness().VerkleProof)
-		keyvals = append(keyvals, block.ExecutionWitness().StateDiff)
+		// The binary tree has no execution witness attached to the blocks
+		if witness := block.ExecutionWitness(); witness != nil {
+			proofs = append(proofs, witness.VerkleProof)
+			keyvals = append(keyvals, witness.StateDiff)
+		}
 
 		return block, b.receipts
 	}
@@ -570,7 +573,7 @@ func GenerateVerkleChainWithGenesis(genesis *Genesis, engine consensus.Engine, n
 	db := rawdb.NewMemoryDatabase()
 	cacheConfig := DefaultCacheConfigWithScheme(rawdb.PathScheme)
 	cacheConfig.SnapshotLimit = 0
-	triedb := triedb.NewDatabase(db, cacheConfig.triedbConfig(true))
+	triedb := triedb.NewDatabase(db, cacheConfig.triedbConfig(true, genesis.IsBinary()))
 	defer triedb.Close()
 	genesisBlock, err := genesis.Commit(db, triedb)
 	if err != nil {
@@ -30,6 +30,10 @@ var (
 	ErrNoGenesis = errors.New("genesis not found in chain")
 
 	errSideChainReceipts = errors.New("side blocks can't be accepted as ancient chain data")
+
+	// ErrInclusionListUnsatisfied is returned when a block omits a transaction
+	// of the inclusion list which could have been appended to it (EIP-7805).
+	ErrInclusionListUnsatisfied = errors.New("inclusion list unsatisfied")
 )
 
 // List of evm-call-message pre-checking errors. All state transition messages will
@@ -127,7 +127,7 @@ func ReadGenesis(db ethdb.Database) (*Genesis, error) {
 }
 
 // hashAlloc computes the state root according to the genesis specification.
-func hashAlloc(ga *types.GenesisAlloc, isVerkle bool) (common.Hash, error) {
+func hashAlloc(ga *types.GenesisAlloc, isVerkle bool, isBinary bool) (common.Hash, error) {
 	// If a genesis-time verkle trie is requested, create a trie config
 	// with the verkle trie enabled so that the tree can be initialized
 	// as such.
@@ -136,6 +136,7 @@ func hashAlloc(ga *types.GenesisAlloc, isVerkle bool) (common.Hash, error) {
 		config = &triedb.Config{
 			PathDB:   pathdb.Defaults,
 			IsVerkle: true,
+			IsBinary: isBinary,
 		}
 	}
 	// Create an ephemeral in-memory database for computing hash,
@@ -442,9 +443,15 @@ func (g *Genesis) IsVerkle() bool {
 	return g.Config.IsVerkleGenesis()
 }
 
+// IsBinary indicates whether the state is stored in a binary merkle tree
+// instead of a verkle tree at genesis time.
+func (g *Genesis) IsBinary() bool {
+	return g.Config.IsBinaryGenesis()
+}
+
 // ToBlock returns the genesis block according to genesis specification.
 func (g *Genesis) ToBlock() *types.Block {
-	root, err := hashAlloc(&g.Alloc, g.IsVerkle())
+	root, err := hashAlloc(&g.Alloc, g.IsVerkle(), g.IsBinary())
 	if err != nil {
 		panic(err)
 	}
@@ -573,19 +580,37 @@ func (g *Genesis) MustCommit(db ethdb.Database, triedb *triedb.Database) *types.
 // In production networks (mainnet and public testnets), verkle activation always
 // occurs after the genesis block, making this function irrelevant in those cases.
 func EnableVerkleAtGenesis(db ethdb.Database, genesis *Genesis) (bool, error) {
+	config, err := genesisChainConfig(db, genesis)
+	if config == nil {
+		return false, err
+	}
+	return config.IsVerkleGenesis(), nil
+}
+
+// EnableBinaryAtGenesis indicates whether the state of a verkle-at-genesis
+// network is stored in the binary merkle tree of EIP-7864. It's a temporary
+// solution only for devnets testing the proposed tree transition.
+func EnableBinaryAtGenesis(db ethdb.Database, genesis *Genesis) (bool, error) {
+	config, err := genesisChainConfig(db, genesis)
+	if config == nil {
+		return false, err
+	}
+	return config.IsBinaryGenesis(), nil
+}
+
+// genesisChainConfig returns the chain config of the given genesis, or of the
+// one already stored in the database if none is given.
+func genesisChainConfig(db ethdb.Database, genesis *Genesis) (*params.ChainConfig, error) {
 	if genesis != nil {
 		if genesis.Config == nil {
-			return false, errGenesisNoConfig
+			return nil, errGenesisNoConfig
 		}
-		return genesis.Config.EnableVerkleAtGenesis, nil
+		return genesis.Config, nil
 	}
 	if ghash := rawdb.ReadCanonicalHash(db, 0); ghash != (common.Hash{}) {
-		chainCfg := rawdb.ReadChainConfig(db, ghash)
-		if chainCfg != nil {
-			return chainCfg.EnableVerkleAtGenesis, nil
-		}
+		return rawdb.ReadChainConfig(db, ghash), nil
 	}
-	return false, nil
+	return nil, nil
 }
 
 // DefaultGenesisBlock returns the Ethereum main net genesis block.
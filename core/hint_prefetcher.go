@@ -0,0 +1,130 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	hintPrefetchLocationMeter = metrics.NewRegisteredMeter("chain/prefetch/hints/locations", nil)
+	hintPrefetchHitMeter      = metrics.NewRegisteredMeter("chain/prefetch/hints/hits", nil)
+	hintPrefetchWasteMeter    = metrics.NewRegisteredMeter("chain/prefetch/hints/waste", nil)
+	hintPrefetchMissMeter     = metrics.NewRegisteredMeter("chain/prefetch/hints/misses", nil)
+
+	execPrefetchHitMeter   = metrics.NewRegisteredMeter("chain/prefetch/executes/hits", nil)
+	execPrefetchWasteMeter = metrics.NewRegisteredMeter("chain/prefetch/executes/waste", nil)
+	execPrefetchMissMeter  = metrics.NewRegisteredMeter("chain/prefetch/executes/misses", nil)
+)
+
+// hintPrefetcher schedules the state a block is expected to access for loading
+// by the trie prefetcher of the block's state, before the block is executed.
+// Contrary to the statePrefetcher, nothing is executed: the accounts and slots
+// are taken from the access lists at hand, namely the ones of the transactions
+// and the block access list delivered along with the block, if any. All the
+// hinted locations are loaded concurrently by the storage trie subfetchers.
+//
+// Recorded execution witnesses are not used as a hint source: they carry the
+// trie nodes and bytecodes touched by a block, but not the account addresses
+// and slot keys leading to them, and they are not persisted on import either.
+type hintPrefetcher struct {
+	config *params.ChainConfig // Chain configuration options
+}
+
+// newHintPrefetcher initialises a new hintPrefetcher.
+func newHintPrefetcher(config *params.ChainConfig) *hintPrefetcher {
+	return &hintPrefetcher{
+		config: config,
+	}
+}
+
+// Prefetch schedules the state locations hinted for the block into the trie
+// prefetcher of the given state, returning them.
+func (p *hintPrefetcher) Prefetch(block *types.Block, statedb *state.StateDB) *state.AccessSet {
+	hints := p.hints(block)
+	statedb.Prefetch(hints)
+
+	hintPrefetchLocationMeter.Mark(int64(hints.Len()))
+	return hints
+}
+
+// hints collects the state locations the block is expected to access.
+func (p *hintPrefetcher) hints(block *types.Block) *state.AccessSet {
+	var (
+		hints  = state.NewAccessSet()
+		signer = types.MakeSigner(p.config, block.Number(), block.Time())
+	)
+	hints.AddAccount(block.Coinbase())
+	for _, tx := range block.Transactions() {
+		// The senders were already recovered in the background by the importer
+		if from, err := types.Sender(signer, tx); err == nil {
+			hints.AddAccount(from)
+		}
+		if to := tx.To(); to != nil {
+			hints.AddAccount(*to)
+		}
+		for _, tuple := range tx.AccessList() {
+			hints.AddAccount(tuple.Address)
+			for _, key := range tuple.StorageKeys {
+				hints.AddSlot(tuple.Address, key)
+			}
+		}
+		for _, auth := range tx.SetCodeAuthorizations() {
+			if authority, err := auth.Authority(); err == nil {
+				hints.AddAccount(authority)
+			}
+			hints.AddAccount(auth.Address)
+		}
+	}
+	for _, w := range block.Withdrawals() {
+		hints.AddAccount(w.Address)
+	}
+	// The block access list is only a hint, but one not matching the header
+	// commitment is certainly bogus.
+	if bal := block.BlockAccessList(); bal != nil {
+		if hash := block.Header().BlockAccessListHash; hash == nil || *hash == bal.Hash() {
+			addBlockAccessListHints(hints, bal)
+		}
+	}
+	return hints
+}
+
+// addBlockAccessListHints inserts all the locations of a block access list into
+// the hint set.
+func addBlockAccessListHints(hints *state.AccessSet, bal *types.BlockAccessList) {
+	for _, acc := range bal.Accounts {
+		hints.AddAccount(acc.Address)
+		for _, slot := range acc.StorageChanges {
+			hints.AddSlot(acc.Address, slot.Slot)
+		}
+		for _, slot := range acc.StorageReads {
+			hints.AddSlot(acc.Address, slot)
+		}
+	}
+}
+
+// reportPrefetch compares the locations loaded by a prefetcher with the ones
+// actually accessed by the block.
+func reportPrefetch(loaded, accessed *state.AccessSet, hits, waste, misses *metrics.Meter) {
+	hit, wasted, missed := loaded.Compare(accessed)
+	hits.Mark(int64(hit))
+	waste.Mark(int64(wasted))
+	misses.Mark(int64(missed))
+}
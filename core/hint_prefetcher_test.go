@@ -0,0 +1,142 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the state hinted by the transaction access lists and the block
+// access lists delivered along with the blocks is collected, and that blocks
+// are imported correctly with the hints prefetched.
+func TestHintPrefetcher(t *testing.T) {
+	var (
+		config  = params.MergedTestChainConfig
+		engine  = beacon.New(ethash.NewFaker())
+		key, _  = crypto.GenerateKey()
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+		counter = common.HexToAddress("0xc0")
+		gspec   = &Genesis{
+			Config: config,
+			Alloc: types.GenesisAlloc{
+				sender:                    {Balance: big.NewInt(params.Ether)},
+				params.BeaconRootsAddress: {Code: params.BeaconRootsCode},
+				// slot0 += 1
+				counter: {Code: []byte{byte(vm.PUSH1), 0, byte(vm.SLOAD), byte(vm.PUSH1), 1, byte(vm.ADD), byte(vm.PUSH1), 0, byte(vm.SSTORE), byte(vm.STOP)}, Storage: map[common.Hash]common.Hash{{}: {31: 1}}},
+			},
+		}
+		signer = types.LatestSigner(config)
+		listed = common.Hash{0x01}
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 2, func(i int, b *BlockGen) {
+		b.SetCoinbase(common.Address{0xcb})
+		b.SetParentBeaconRoot(common.Hash{byte(i + 1)})
+		b.AddTx(types.MustSignNewTx(key, signer, &types.AccessListTx{
+			Nonce:      b.TxNonce(sender),
+			To:         &counter,
+			Gas:        100000,
+			GasPrice:   b.header.BaseFee,
+			AccessList: types.AccessList{{Address: counter, StorageKeys: []common.Hash{listed}}},
+		}))
+	})
+	// Record the block access lists on a first import, as the builder of the
+	// blocks would
+	recorded := rawdb.NewMemoryDatabase()
+	cache := DefaultCacheConfigWithScheme(rawdb.HashScheme)
+	cache.BlockAccessLists = true
+	chain, err := NewBlockChain(recorded, cache, gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Failed to import blocks: %v", err)
+	}
+	chain.Stop()
+
+	delivered := make([]*types.Block, len(blocks))
+	for i, block := range blocks {
+		bal := rawdb.ReadBlockAccessList(recorded, block.Hash(), block.NumberU64())
+		if bal == nil {
+			t.Fatalf("Block access list %d not recorded", i)
+		}
+		delivered[i] = block.WithBlockAccessList(bal)
+	}
+	// Without a block access list, only the transaction ones are hinted
+	prefetcher := newHintPrefetcher(config)
+	hints := prefetcher.hints(blocks[0])
+	for _, addr := range []common.Address{sender, counter, blocks[0].Coinbase()} {
+		if !hints.Intersects(accountSet(addr)) {
+			t.Errorf("Account %x not hinted", addr)
+		}
+	}
+	if slots := hints.Slots(counter); len(slots) != 1 || slots[0] != listed {
+		t.Errorf("Hinted slots mismatch: have %v, want [%x]", slots, listed)
+	}
+	if hints.Intersects(accountSet(params.BeaconRootsAddress)) {
+		t.Error("Beacon roots contract hinted without block access list")
+	}
+	// With the delivered block access list, the system contracts and accessed
+	// slots are hinted too
+	hints = prefetcher.hints(delivered[0])
+	if !hints.Intersects(accountSet(params.BeaconRootsAddress)) {
+		t.Error("Beacon roots contract not hinted from block access list")
+	}
+	if slots := hints.Slots(counter); len(slots) != 2 {
+		t.Errorf("Hinted slots mismatch: have %v, want 2 slots", slots)
+	}
+	// A block access list not matching the header commitment is ignored
+	header := blocks[0].Header()
+	header.BlockAccessListHash = &common.Hash{0x01}
+	bogus := types.NewBlockWithHeader(header).WithBody(*blocks[0].Body()).WithBlockAccessList(delivered[0].BlockAccessList())
+	if prefetcher.hints(bogus).Intersects(accountSet(params.BeaconRootsAddress)) {
+		t.Error("Beacon roots contract hinted from mismatching block access list")
+	}
+	// Import the delivered blocks into an empty database with the hints
+	// prefetched, and ensure the result matches the one without
+	cache = DefaultCacheConfigWithScheme(rawdb.HashScheme)
+	cache.HintPrefetch = true
+	chain, err = NewBlockChain(rawdb.NewMemoryDatabase(), cache, gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(delivered); err != nil {
+		t.Fatalf("Failed to import blocks with hint prefetching: %v", err)
+	}
+	if have, want := chain.CurrentBlock().Root, blocks[1].Root(); have != want {
+		t.Fatalf("State root mismatch: have %x, want %x", have, want)
+	}
+}
+
+// accountSet creates an access set made of a single account.
+func accountSet(addr common.Address) *state.AccessSet {
+	set := state.NewAccessSet()
+	set.AddAccount(addr)
+	return set
+}
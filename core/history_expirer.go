@@ -0,0 +1,130 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// historyExpiryBatch is the minimum number of blocks pruned at once, avoiding
+// rewriting the freezer index files on every new chain head.
+const historyExpiryBatch = 1024
+
+// historyExpirer is the module responsible for continuously pruning the block
+// bodies and receipts falling out of the configured retention window. Headers
+// are retained, and pruning happens only on the frozen chain segment.
+type historyExpirer struct {
+	// retention is the number of recent blocks [HEAD-N+1, HEAD] whose
+	// bodies and receipts are reserved.
+	retention uint64
+	db        ethdb.Database
+	term      chan chan struct{}
+	closed    chan struct{}
+}
+
+// newHistoryExpirer initializes the history expirer.
+func newHistoryExpirer(retention uint64, chain *BlockChain) *historyExpirer {
+	expirer := &historyExpirer{
+		retention: retention,
+		db:        chain.db,
+		term:      make(chan chan struct{}),
+		closed:    make(chan struct{}),
+	}
+	go expirer.loop(chain)
+
+	log.Info("Initialized rolling history expiry", "range", retention)
+	return expirer
+}
+
+// target returns the first block whose history should be retained, given the
+// current chain head.
+func (expirer *historyExpirer) target(head uint64) uint64 {
+	if head+1 <= expirer.retention {
+		return 0
+	}
+	target := head + 1 - expirer.retention
+
+	// Transaction unindexing needs the block bodies, never prune history
+	// which still has transactions indexed.
+	if tail := rawdb.ReadTxIndexTail(expirer.db); tail != nil && *tail < target {
+		target = *tail
+	}
+	return target
+}
+
+// expire truncates the chain history below the retention window of the given
+// chain head, once enough blocks have accumulated.
+func (expirer *historyExpirer) expire(head uint64) {
+	target := expirer.target(head)
+
+	frozen, err := expirer.db.Ancients()
+	if err != nil {
+		return // no freezer, nothing to prune
+	}
+	if target > frozen {
+		target = frozen
+	}
+	tail, err := expirer.db.Tail()
+	if err != nil {
+		log.Error("Failed to retrieve history tail", "err", err)
+		return
+	}
+	if target < tail+historyExpiryBatch {
+		return
+	}
+	if _, err := expirer.db.TruncateTail(target); err != nil {
+		log.Error("Failed to prune chain history", "target", target, "err", err)
+		return
+	}
+	log.Info("Pruned chain history", "from", tail, "to", target, "head", head)
+}
+
+// loop is the scheduler of the expirer, pruning the history depending on the
+// received chain event.
+func (expirer *historyExpirer) loop(chain *BlockChain) {
+	defer close(expirer.closed)
+
+	headCh := make(chan ChainHeadEvent)
+	sub := chain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	// Prune the stale history left over from the previous run.
+	if head := rawdb.ReadHeadBlock(expirer.db); head != nil {
+		expirer.expire(head.NumberU64())
+	}
+	for {
+		select {
+		case head := <-headCh:
+			expirer.expire(head.Header.Number.Uint64())
+		case ch := <-expirer.term:
+			close(ch)
+			return
+		}
+	}
+}
+
+// close shutdown the expirer. Safe to be called for multiple times.
+func (expirer *historyExpirer) close() {
+	ch := make(chan struct{})
+	select {
+	case expirer.term <- ch:
+		<-ch
+	case <-expirer.closed:
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestHistoryExpirer tests the rolling pruning of the chain history.
+func TestHistoryExpirer(t *testing.T) {
+	var (
+		gspec     = &Genesis{Config: params.TestChainConfig}
+		chainHead = uint64(2560)
+	)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, ethash.NewFaker(), int(chainHead), nil)
+
+	var cases = []struct {
+		retention uint64
+		txTail    uint64 // transaction index tail, zero if not indexed
+		frozen    uint64 // number of blocks in the freezer
+		tail      uint64 // expected history tail
+	}{
+		// Blocks [2049, 2560] are retained
+		{retention: 512, frozen: chainHead + 1, tail: 2049},
+
+		// Blocks still having transactions indexed are retained
+		{retention: 512, txTail: 1100, frozen: chainHead + 1, tail: 1100},
+
+		// Only the frozen blocks are pruned
+		{retention: 512, frozen: 1500, tail: 1500},
+
+		// Too few blocks to prune
+		{retention: 2048, frozen: chainHead + 1, tail: 0},
+	}
+	for i, c := range cases {
+		db, _ := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+		all := append([]*types.Block{gspec.ToBlock()}, blocks...)
+		rawdb.WriteAncientBlocks(db, all[:c.frozen], append([]types.Receipts{{}}, receipts...)[:c.frozen])
+		if c.txTail != 0 {
+			rawdb.WriteTxIndexTail(db, c.txTail)
+		}
+		expirer := &historyExpirer{retention: c.retention, db: db}
+		expirer.expire(chainHead)
+
+		if tail, _ := db.Tail(); tail != c.tail {
+			t.Fatalf("case %d: unexpected history tail, want %d, got %d", i, c.tail, tail)
+		}
+		for number := uint64(0); number < c.frozen; number++ {
+			hash := all[number].Hash()
+			if rawdb.ReadHeader(db, hash, number) == nil {
+				t.Fatalf("case %d: missing header %d", i, number)
+			}
+			if have := rawdb.ReadBody(db, hash, number) != nil; have != (number >= c.tail) {
+				t.Fatalf("case %d: unexpected body %d availability, want %t, got %t", i, number, number >= c.tail, have)
+			}
+		}
+		db.Close()
+	}
+}
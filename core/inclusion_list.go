@@ -0,0 +1,69 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// VerifyInclusionList checks whether a block satisfies the given inclusion list
+// as defined by EIP-7805. Every transaction of the list must either be part of
+// the block, or be impossible to append at its end: not enough gas left in the
+// block, or an invalid nonce, fee or balance against the post-state.
+//
+// Blob transactions are not eligible for inclusion lists and are ignored, the
+// same as transactions with an invalid signature.
+func VerifyInclusionList(config *params.ChainConfig, block *types.Block, statedb *state.StateDB, list types.Transactions) error {
+	included := make(map[common.Hash]struct{}, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		included[tx.Hash()] = struct{}{}
+	}
+	var (
+		signer  = types.MakeSigner(config, block.Number(), block.Time())
+		gasLeft = block.GasLimit() - block.GasUsed()
+	)
+	for _, tx := range list {
+		if _, ok := included[tx.Hash()]; ok {
+			continue
+		}
+		if tx.Type() == types.BlobTxType || tx.Gas() > gasLeft {
+			continue
+		}
+		if block.BaseFee() != nil && tx.GasFeeCap().Cmp(block.BaseFee()) < 0 {
+			continue
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			continue
+		}
+		if statedb.GetNonce(from) != tx.Nonce() {
+			continue
+		}
+		cost, overflow := uint256.FromBig(tx.Cost())
+		if overflow || statedb.GetBalance(from).Cmp(cost) < 0 {
+			continue
+		}
+		return fmt.Errorf("%w: transaction %x could be appended", ErrInclusionListUnsatisfied, tx.Hash())
+	}
+	return nil
+}
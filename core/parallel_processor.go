@@ -0,0 +1,308 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	parallelTxMeter       = metrics.NewRegisteredMeter("chain/parallel/txs", nil)
+	parallelReexecMeter   = metrics.NewRegisteredMeter("chain/parallel/reexecs", nil)
+	parallelMismatchMeter = metrics.NewRegisteredMeter("chain/parallel/mismatches", nil)
+
+	// errParallelMismatch is returned in verification mode if the parallel
+	// execution diverged from the sequential one.
+	errParallelMismatch = errors.New("parallel execution mismatch")
+)
+
+// ParallelStateProcessor is a Processor executing the transactions of a block
+// optimistically in parallel. The workflow is:
+//
+//   - every transaction is executed speculatively on its own copy of the state
+//     at the beginning of the block, recording the state locations it reads and
+//     collecting the modifications it makes from the journal
+//   - the transactions are then committed in order: if none of the locations
+//     read by a transaction was modified by its predecessors, the speculative
+//     execution is valid and its modifications are replayed onto the block
+//     state, otherwise the transaction is re-executed on top of it
+//
+// The transaction fees credited to the coinbase are deferred while executing
+// speculatively, otherwise every transaction would conflict with all the ones
+// before it. The result is identical to the one of the sequential execution.
+//
+// ParallelStateProcessor implements Processor.
+type ParallelStateProcessor struct {
+	config     *params.ChainConfig // Chain configuration options
+	chain      *HeaderChain        // Canonical header chain
+	workers    int                 // Number of transactions executed concurrently
+	verify     bool                // Whether to cross-check against the sequential execution
+	sequential *StateProcessor     // Fallback for blocks which can't be parallelized
+}
+
+// NewParallelStateProcessor initialises a new ParallelStateProcessor. If verify
+// is set, every block is additionally executed sequentially and any divergence
+// is reported as an error.
+func NewParallelStateProcessor(config *params.ChainConfig, chain *HeaderChain, verify bool) *ParallelStateProcessor {
+	return &ParallelStateProcessor{
+		config:     config,
+		chain:      chain,
+		workers:    runtime.NumCPU(),
+		verify:     verify,
+		sequential: NewStateProcessor(config, chain),
+	}
+}
+
+// speculation is the outcome of executing a transaction on top of the state at
+// the beginning of the block.
+type speculation struct {
+	evm    *vm.EVM
+	result *ExecutionResult
+	reads  *state.AccessSet
+	writes *state.TxWrites
+	err    error
+}
+
+// Process processes the state changes according to the Ethereum rules by running
+// the transaction messages using the statedb and applying any rewards to both
+// the processor (coinbase) and any included uncles.
+func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (*ProcessResult, error) {
+	// Tracing and witness collection observe the execution order, and the
+	// access events of verkle can't be replayed, fall back to the sequential
+	// execution for these. The replayed writes also lack the change reasons
+	// tracers expect, see StateDB.ApplyWrites.
+	if cfg.Tracer != nil || statedb.Witness() != nil || p.config.IsVerkle(block.Number(), block.Time()) || len(block.Transactions()) < 2 {
+		return p.sequential.Process(block, statedb, cfg)
+	}
+	if p.config.DAOForkSupport && p.config.DAOForkBlock != nil && p.config.DAOForkBlock.Cmp(block.Number()) == 0 {
+		return p.sequential.Process(block, statedb, cfg)
+	}
+	if !p.verify {
+		return p.process(block, statedb, cfg)
+	}
+	pre := statedb.Copy()
+	res, err := p.process(block, statedb, cfg)
+	if verr := p.crossCheck(block, res, err, statedb, pre, cfg); verr != nil {
+		parallelMismatchMeter.Mark(1)
+		log.Error("Parallel execution diverged", "number", block.Number(), "hash", block.Hash(), "err", verr)
+		return nil, fmt.Errorf("%w: %v", errParallelMismatch, verr)
+	}
+	return res, err
+}
+
+// process executes the block with the parallel strategy.
+func (p *ParallelStateProcessor) process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (*ProcessResult, error) {
+	var (
+		receipts    types.Receipts
+		usedGas     = new(uint64)
+		header      = block.Header()
+		blockHash   = block.Hash()
+		blockNumber = block.Number()
+		allLogs     []*types.Log
+		gp          = new(GasPool).AddGas(block.GasLimit())
+		signer      = types.MakeSigner(p.config, header.Number, header.Time)
+		txs         = block.Transactions()
+	)
+	// Apply pre-execution system calls.
+	evm := vm.NewEVM(NewEVMBlockContext(header, p.chain, nil), statedb, p.config, cfg)
+
+	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
+		ProcessBeaconBlockRoot(*beaconRoot, evm)
+	}
+	if p.config.IsPrague(block.Number(), block.Time()) {
+		ProcessParentBlockHash(block.ParentHash(), evm)
+	}
+	msgs := make([]*Message, len(txs))
+	for i, tx := range txs {
+		msg, err := TransactionToMessage(tx, signer, header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		msgs[i] = msg
+	}
+	specs := p.speculate(block, statedb, msgs, cfg)
+
+	// Commit the transactions in order, re-executing the ones which read state
+	// modified by their predecessors.
+	var (
+		written = state.NewAccessSet()
+		reexecs int
+	)
+	for i, tx := range txs {
+		var (
+			spec    = specs[i]
+			writes  *state.TxWrites
+			receipt *types.Receipt
+		)
+		statedb.SetTxContext(tx.Hash(), i)
+
+		if spec.err == nil && gp.Gas() >= msgs[i].GasLimit && !written.Intersects(spec.reads) {
+			statedb.ApplyWrites(spec.writes)
+			if err := gp.SubGas(spec.result.UsedGas); err != nil {
+				return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+			}
+			writes = spec.writes
+			receipt = p.finalise(spec.evm, spec.result, statedb, blockNumber, blockHash, tx, usedGas)
+		} else {
+			reexecs++
+			result, err := ApplyMessage(evm, msgs[i], gp)
+			if err != nil {
+				return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+			}
+			writes = statedb.Writes()
+			receipt = p.finalise(evm, result, statedb, blockNumber, blockHash, tx, usedGas)
+		}
+		written.Merge(writes.Keys())
+
+		receipts = append(receipts, receipt)
+		allLogs = append(allLogs, receipt.Logs...)
+	}
+	parallelTxMeter.Mark(int64(len(txs)))
+	parallelReexecMeter.Mark(int64(reexecs))
+	log.Debug("Executed block in parallel", "number", blockNumber, "txs", len(txs), "reexecs", reexecs)
+
+	// Read requests if Prague is enabled.
+	var requests [][]byte
+	if p.config.IsPrague(block.Number(), block.Time()) {
+		requests = [][]byte{}
+		// EIP-6110
+		if err := ParseDepositLogs(&requests, allLogs, p.config); err != nil {
+			return nil, err
+		}
+		// EIP-7002
+		ProcessWithdrawalQueue(&requests, evm)
+		// EIP-7251
+		ProcessConsolidationQueue(&requests, evm)
+	}
+
+	// Finalize the block, applying any consensus engine specific extras (e.g. block rewards)
+	p.chain.engine.Finalize(p.chain, header, statedb, block.Body())
+
+	return &ProcessResult{
+		Receipts: receipts,
+		Requests: requests,
+		Logs:     allLogs,
+		GasUsed:  *usedGas,
+	}, nil
+}
+
+// speculate executes all the transactions concurrently, each one on its own
+// copy of the given state.
+func (p *ParallelStateProcessor) speculate(block *types.Block, statedb *state.StateDB, msgs []*Message, cfg vm.Config) []*speculation {
+	var (
+		txs   = block.Transactions()
+		specs = make([]*speculation, len(txs))
+		tasks = make(chan int, len(txs))
+		lock  sync.Mutex // Serializes copying the shared base state
+		wg    sync.WaitGroup
+	)
+	for i := range txs {
+		tasks <- i
+	}
+	close(tasks)
+
+	for range min(p.workers, len(txs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range tasks {
+				lock.Lock()
+				spec := statedb.Copy()
+				lock.Unlock()
+
+				spec.TrackAccess(block.Coinbase())
+				spec.SetTxContext(txs[i].Hash(), i)
+
+				// The block context caches block hashes, so it can't be shared
+				evm := vm.NewEVM(NewEVMBlockContext(block.Header(), p.chain, nil), spec, p.config, cfg)
+				result, err := ApplyMessage(evm, msgs[i], new(GasPool).AddGas(block.GasLimit()))
+				if err != nil {
+					specs[i] = &speculation{err: err}
+					continue
+				}
+				specs[i] = &speculation{
+					evm:    evm,
+					result: result,
+					reads:  spec.Reads(),
+					writes: spec.Writes(),
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return specs
+}
+
+// finalise finishes a transaction applied to the block state, assembling its
+// receipt, as ApplyTransactionWithEVM does.
+func (p *ParallelStateProcessor) finalise(evm *vm.EVM, result *ExecutionResult, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *types.Transaction, usedGas *uint64) *types.Receipt {
+	var root []byte
+	if p.config.IsByzantium(blockNumber) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(p.config.IsEIP158(blockNumber)).Bytes()
+	}
+	*usedGas += result.UsedGas
+	return MakeReceipt(evm, result, statedb, blockNumber, blockHash, tx, *usedGas, root)
+}
+
+// crossCheck executes the block sequentially on top of the pre-state and ensures
+// the outcome matches the one of the parallel execution.
+func (p *ParallelStateProcessor) crossCheck(block *types.Block, res *ProcessResult, err error, statedb *state.StateDB, pre *state.StateDB, cfg vm.Config) error {
+	want, wantErr := p.sequential.Process(block, pre, cfg)
+	if (err == nil) != (wantErr == nil) {
+		return fmt.Errorf("error mismatch: have %v, want %v", err, wantErr)
+	}
+	if err != nil {
+		return nil
+	}
+	if res.GasUsed != want.GasUsed {
+		return fmt.Errorf("gas used mismatch: have %d, want %d", res.GasUsed, want.GasUsed)
+	}
+	if len(res.Receipts) != len(want.Receipts) {
+		return fmt.Errorf("receipt count mismatch: have %d, want %d", len(res.Receipts), len(want.Receipts))
+	}
+	for i := range want.Receipts {
+		if !reflect.DeepEqual(res.Receipts[i], want.Receipts[i]) {
+			return fmt.Errorf("receipt %d mismatch", i)
+		}
+	}
+	if !slices.EqualFunc(res.Requests, want.Requests, bytes.Equal) {
+		return errors.New("requests mismatch")
+	}
+	deleteEmpty := p.config.IsEIP158(block.Number())
+	if have, want := statedb.IntermediateRoot(deleteEmpty), pre.IntermediateRoot(deleteEmpty); have != want {
+		return fmt.Errorf("state root mismatch: have %x, want %x", have, want)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the parallel execution of blocks with both independent and
+// conflicting transactions yields the same result as the sequential one.
+func TestParallelStateProcessor(t *testing.T) {
+	t.Run("legacy", func(t *testing.T) { testParallelStateProcessor(t, params.TestChainConfig, ethash.NewFaker()) })
+	t.Run("merged", func(t *testing.T) {
+		testParallelStateProcessor(t, params.MergedTestChainConfig, beacon.New(ethash.NewFaker()))
+	})
+}
+
+func testParallelStateProcessor(t *testing.T, config *params.ChainConfig, engine consensus.Engine) {
+	var (
+		counter   = common.HexToAddress("0xc0")
+		logger    = common.HexToAddress("0xc1")
+		destroyer = common.HexToAddress("0xc2")
+		keys      []*ecdsa.PrivateKey
+		gspec     = &Genesis{
+			Config: config,
+			Alloc: types.GenesisAlloc{
+				// slot0 += 1
+				counter: {Code: []byte{byte(vm.PUSH1), 0, byte(vm.SLOAD), byte(vm.PUSH1), 1, byte(vm.ADD), byte(vm.PUSH1), 0, byte(vm.SSTORE), byte(vm.STOP)}},
+				// emits an empty log
+				logger: {Code: []byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.LOG0), byte(vm.STOP)}},
+				// sends its balance to the caller
+				destroyer: {Code: []byte{byte(vm.CALLER), byte(vm.SELFDESTRUCT)}, Balance: big.NewInt(params.Ether)},
+			},
+		}
+		signer = types.LatestSigner(config)
+	)
+	for i := 0; i < 8; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+		gspec.Alloc[crypto.PubkeyToAddress(key.PublicKey)] = types.Account{Balance: big.NewInt(params.Ether)}
+	}
+	send := func(b *BlockGen, key *ecdsa.PrivateKey, to *common.Address, value int64, data []byte) {
+		from := crypto.PubkeyToAddress(key.PublicKey)
+		tx := types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    b.TxNonce(from),
+			To:       to,
+			Value:    big.NewInt(value),
+			Gas:      100000,
+			GasPrice: b.header.BaseFee,
+			Data:     data,
+		})
+		b.AddTx(tx)
+	}
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 4, func(i int, b *BlockGen) {
+		b.SetCoinbase(common.Address{0xcb})
+		for j, key := range keys {
+			switch j % 4 {
+			case 0:
+				// Independent transfers to fresh accounts
+				to := common.Address{byte(i), byte(j)}
+				send(b, key, &to, 1000, nil)
+			case 1:
+				// Conflicting storage updates
+				send(b, key, &counter, 0, nil)
+			case 2:
+				// Transfers to the next sender, conflicting with its own transaction
+				to := crypto.PubkeyToAddress(keys[(j+1)%len(keys)].PublicKey)
+				send(b, key, &to, 1000, nil)
+				send(b, key, &logger, 0, nil)
+			case 3:
+				// Contract creation and destruction
+				send(b, key, nil, 0, []byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.RETURN)})
+				send(b, key, &destroyer, 0, nil)
+			}
+		}
+		// Read the coinbase, conflicting with all the fees credited to it
+		coinbase := common.Address{0xcb}
+		send(b, keys[0], &coinbase, 1, nil)
+	})
+	sequential, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create sequential chain: %v", err)
+	}
+	defer sequential.Stop()
+
+	cache := DefaultCacheConfigWithScheme(rawdb.HashScheme)
+	cache.ParallelExecution, cache.ParallelVerify = true, true
+	parallel, err := NewBlockChain(rawdb.NewMemoryDatabase(), cache, gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create parallel chain: %v", err)
+	}
+	defer parallel.Stop()
+
+	if _, err := sequential.InsertChain(blocks); err != nil {
+		t.Fatalf("Failed to import blocks sequentially: %v", err)
+	}
+	if _, err := parallel.InsertChain(blocks); err != nil {
+		t.Fatalf("Failed to import blocks in parallel: %v", err)
+	}
+	if have, want := parallel.CurrentBlock().Root, sequential.CurrentBlock().Root; have != want {
+		t.Fatalf("State root mismatch: have %x, want %x", have, want)
+	}
+	for _, block := range blocks {
+		have := parallel.GetReceiptsByHash(block.Hash())
+		want := sequential.GetReceiptsByHash(block.Hash())
+		if !reflect.DeepEqual(have, want) {
+			t.Fatalf("Block %d: receipts mismatch", block.NumberU64())
+		}
+	}
+}
@@ -105,6 +105,36 @@ func TestBodyStorage(t *testing.T) {
 	}
 }
 
+// Tests block access list storage and retrieval operations, and that the access
+// lists are kept apart from the other block data.
+func TestBlockAccessListStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	hash := common.Hash{0x01}
+	bal := &types.BlockAccessList{Accounts: []types.AccountAccess{{Address: common.Address{0x02}}}}
+
+	if entry := ReadBlockAccessList(db, hash, 1); entry != nil {
+		t.Fatalf("Non existent access list returned: %v", entry)
+	}
+	WriteBlockAccessList(db, hash, 1, bal)
+	if entry := ReadBlockAccessList(db, hash, 1); entry == nil {
+		t.Fatalf("Stored access list not found")
+	} else if entry.Hash() != bal.Hash() {
+		t.Fatalf("Retrieved access list mismatch: have %v, want %v", entry, bal)
+	}
+	// Ensure the access list doesn't show up as a block body
+	it := db.NewIterator(blockBodyPrefix, nil)
+	for it.Next() {
+		t.Fatalf("Access list stored under the block body prefix: %x", it.Key())
+	}
+	it.Release()
+
+	DeleteBlockAccessList(db, hash, 1)
+	if entry := ReadBlockAccessList(db, hash, 1); entry != nil {
+		t.Fatalf("Deleted access list returned: %v", entry)
+	}
+}
+
 // Tests block storage and retrieval operations.
 func TestBlockStorage(t *testing.T) {
 	db := NewMemoryDatabase()
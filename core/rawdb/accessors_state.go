@@ -164,6 +164,29 @@ func DeleteTrieJournal(db ethdb.KeyValueWriter) {
 	}
 }
 
+// ReadTrieScrubReport retrieves the serialized report of the trie node integrity
+// scrubber.
+func ReadTrieScrubReport(db ethdb.KeyValueReader) []byte {
+	data, _ := db.Get(trieScrubReportKey)
+	return data
+}
+
+// WriteTrieScrubReport stores the serialized report of the trie node integrity
+// scrubber.
+func WriteTrieScrubReport(db ethdb.KeyValueWriter, report []byte) {
+	if err := db.Put(trieScrubReportKey, report); err != nil {
+		log.Crit("Failed to store trie scrub report", "err", err)
+	}
+}
+
+// DeleteTrieScrubReport deletes the serialized report of the trie node integrity
+// scrubber.
+func DeleteTrieScrubReport(db ethdb.KeyValueWriter) {
+	if err := db.Delete(trieScrubReportKey); err != nil {
+		log.Crit("Failed to remove trie scrub report", "err", err)
+	}
+}
+
 // ReadStateHistoryMeta retrieves the metadata corresponding to the specified
 // state history. Compute the position of state history in freezer by minus
 // one since the id of first state history starts from one(zero for initial
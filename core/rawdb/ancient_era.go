@@ -0,0 +1,337 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/erae"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// maxOpenEraFiles is the maximum number of era files kept open at once.
+const maxOpenEraFiles = 16
+
+var (
+	// errEraMissing is returned if a block is not covered by any era file.
+	errEraMissing = errors.New("block not found in era files")
+
+	// errEraMismatch is returned if a block of an era file does not match the
+	// chain in the freezer.
+	errEraMismatch = errors.New("era block mismatch")
+)
+
+// eraArchive is the common interface of the Era1 and post-merge era readers.
+type eraArchive interface {
+	GetBlockByNumber(num uint64) (*types.Block, error)
+	GetReceiptsByNumber(num uint64) (types.Receipts, error)
+	Close() error
+}
+
+// eraFile is the block range covered by an era file of the directory.
+type eraFile struct {
+	path  string
+	start uint64
+	count uint64
+}
+
+// eraStore is a read-only ancient store mapping the chain segment onto the
+// block ranges of a directory of era files.
+type eraStore struct {
+	files []eraFile                     // Era files sorted by block range
+	open  lru.BasicLRU[int, eraArchive] // Recently used era files, keyed by index
+	lock  sync.Mutex                    // Lock protecting the open files
+}
+
+// newEraStore indexes the Era1 and post-merge era files of the network in the
+// directory, which must cover disjoint block ranges. The files are named as
+// <network>-<epoch>-<hexroot>.<ext>, the ones of other networks are ignored.
+func newEraStore(dir string, network string) (*eraStore, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading era directory %s: %w", dir, err)
+	}
+	store := &eraStore{open: lru.NewBasicLRU[int, eraArchive](maxOpenEraFiles)}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".era1" && ext != ".erae") {
+			continue
+		}
+		if parts := strings.Split(entry.Name(), "-"); len(parts) != 3 || parts[0] != network {
+			log.Warn("Ignoring era file of another network", "file", entry.Name(), "network", network)
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		e, err := openEra(path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening era file %s: %w", path, err)
+		}
+		file := eraFile{path: path}
+		switch e := e.(type) {
+		case *era.Era:
+			file.start, file.count = e.Start(), e.Count()
+		case *erae.Era:
+			file.start, file.count = e.Start(), e.Count()
+		}
+		e.Close()
+		store.files = append(store.files, file)
+	}
+	sort.Slice(store.files, func(i, j int) bool {
+		return store.files[i].start < store.files[j].start
+	})
+	for i := 1; i < len(store.files); i++ {
+		prev := store.files[i-1]
+		if prev.start+prev.count > store.files[i].start {
+			return nil, fmt.Errorf("overlapping era files %s and %s", prev.path, store.files[i].path)
+		}
+	}
+	return store, nil
+}
+
+// openEra opens an Era1 or a post-merge era file depending on its extension.
+func openEra(path string) (eraArchive, error) {
+	if filepath.Ext(path) == ".erae" {
+		return erae.Open(path)
+	}
+	return era.Open(path)
+}
+
+// ancient retrieves the chain freezer item of the given kind from the era file
+// covering the block, in the encoding of the chain freezer. The block in the era
+// file must have the given hash.
+func (s *eraStore) ancient(kind string, number uint64, hash common.Hash) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	e, err := s.archive(number)
+	if err != nil {
+		return nil, err
+	}
+	block, err := e.GetBlockByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+	if block.Hash() != hash {
+		return nil, fmt.Errorf("%w: block %d has hash %x, want %x", errEraMismatch, number, block.Hash(), hash)
+	}
+	switch kind {
+	case ChainFreezerBodiesTable:
+		return rlp.EncodeToBytes(block.Body())
+
+	case ChainFreezerReceiptTable:
+		receipts, err := e.GetReceiptsByNumber(number)
+		if err != nil {
+			return nil, err
+		}
+		stored := make([]*types.ReceiptForStorage, len(receipts))
+		for i, receipt := range receipts {
+			stored[i] = (*types.ReceiptForStorage)(receipt)
+		}
+		return rlp.EncodeToBytes(stored)
+
+	default:
+		return nil, errUnknownTable
+	}
+}
+
+// archive returns the era file covering the block, opening it if needed. The
+// caller must hold the lock.
+func (s *eraStore) archive(number uint64) (eraArchive, error) {
+	index := sort.Search(len(s.files), func(i int) bool {
+		return s.files[i].start+s.files[i].count > number
+	})
+	if index == len(s.files) || s.files[index].start > number {
+		return nil, errEraMissing
+	}
+	if e, ok := s.open.Get(index); ok {
+		return e, nil
+	}
+	e, err := openEra(s.files[index].path)
+	if err != nil {
+		return nil, err
+	}
+	if s.open.Len() >= maxOpenEraFiles {
+		if _, old, ok := s.open.RemoveOldest(); ok {
+			old.Close()
+		}
+	}
+	s.open.Add(index, e)
+	return e, nil
+}
+
+// close closes all the open era files.
+func (s *eraStore) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, index := range s.open.Keys() {
+		e, _ := s.open.Peek(index)
+		e.Close()
+	}
+	s.open.Purge()
+}
+
+// eraDatabase is a database serving the chain segment below the tail of the
+// chain freezer from a directory of era files.
+type eraDatabase struct {
+	ethdb.Database
+	era *eraStore
+}
+
+// NewDatabaseWithEra wraps the database to serve the chain history which is no
+// longer present in the chain freezer, i.e. below its tail, from the era files
+// of the network in the given directory. The blocks served are checked against
+// the hashes retained by the chain freezer.
+func NewDatabaseWithEra(db ethdb.Database, dir string, network string) (ethdb.Database, error) {
+	store, err := newEraStore(dir, network)
+	if err != nil {
+		return nil, err
+	}
+	if len(store.files) > 0 {
+		first, last := store.files[0], store.files[len(store.files)-1]
+		log.Info("Serving chain history from era files", "dir", dir, "files", len(store.files), "first", first.start, "last", last.start+last.count-1)
+	}
+	return &eraDatabase{Database: db, era: store}, nil
+}
+
+// HasAncient returns an indicator whether the specified ancient data exists.
+func (db *eraDatabase) HasAncient(kind string, number uint64) (bool, error) {
+	return eraReader{db.Database, db.era}.HasAncient(kind, number)
+}
+
+// Ancient retrieves an ancient binary blob, from the era files if it's below
+// the tail of the chain freezer.
+func (db *eraDatabase) Ancient(kind string, number uint64) ([]byte, error) {
+	return eraReader{db.Database, db.era}.Ancient(kind, number)
+}
+
+// AncientRange retrieves multiple items in sequence, starting from the index
+// 'start', from the era files as long as they are below the tail of the chain
+// freezer.
+func (db *eraDatabase) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	return eraReader{db.Database, db.era}.AncientRange(kind, start, count, maxBytes)
+}
+
+// ReadAncients runs the given read operation, serving the items below the tail
+// of the chain freezer from the era files.
+func (db *eraDatabase) ReadAncients(fn func(ethdb.AncientReaderOp) error) error {
+	return db.Database.ReadAncients(func(op ethdb.AncientReaderOp) error {
+		return fn(eraReader{op, db.era})
+	})
+}
+
+// Close closes the era files and the underlying database.
+func (db *eraDatabase) Close() error {
+	db.era.close()
+	return db.Database.Close()
+}
+
+// eraReader is an ancient reader falling back to the era files for the items
+// below the tail of the chain freezer.
+type eraReader struct {
+	ethdb.AncientReaderOp
+	era *eraStore
+}
+
+// pruned reports whether the item is below the tail of the chain freezer and
+// thus missing from it. Headers and hashes are kept by the freezer when the
+// history is pruned, and are never served from the era files.
+func (r eraReader) pruned(kind string, number uint64) bool {
+	config, ok := chainFreezerTableConfigs[kind]
+	if !ok || !config.prunable {
+		return false
+	}
+	tail, err := r.AncientReaderOp.Tail()
+	return err == nil && number < tail
+}
+
+// ancient retrieves a pruned item from the era files, checking the block it
+// belongs to against the hash retained by the chain freezer.
+func (r eraReader) ancient(kind string, number uint64) ([]byte, error) {
+	hash, err := r.AncientReaderOp.Ancient(ChainFreezerHashTable, number)
+	if err != nil {
+		return nil, fmt.Errorf("missing hash of era block %d: %w", number, err)
+	}
+	return r.era.ancient(kind, number, common.BytesToHash(hash))
+}
+
+func (r eraReader) HasAncient(kind string, number uint64) (bool, error) {
+	if !r.pruned(kind, number) {
+		return r.AncientReaderOp.HasAncient(kind, number)
+	}
+	r.era.lock.Lock()
+	defer r.era.lock.Unlock()
+
+	_, err := r.era.archive(number)
+	if errors.Is(err, errEraMissing) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r eraReader) Ancient(kind string, number uint64) ([]byte, error) {
+	if !r.pruned(kind, number) {
+		return r.AncientReaderOp.Ancient(kind, number)
+	}
+	return r.ancient(kind, number)
+}
+
+func (r eraReader) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	if !r.pruned(kind, start) {
+		return r.AncientReaderOp.AncientRange(kind, start, count, maxBytes)
+	}
+	var (
+		items [][]byte
+		size  uint64
+	)
+	for number := start; number < start+count && r.pruned(kind, number); number++ {
+		item, err := r.ancient(kind, number)
+		if err != nil {
+			return nil, err
+		}
+		if maxBytes != 0 && len(items) > 0 && size+uint64(len(item)) > maxBytes {
+			return items, nil
+		}
+		items = append(items, item)
+		size += uint64(len(item))
+	}
+	// Continue with the items of the chain freezer, if any are requested
+	next := start + uint64(len(items))
+	if next == start+count || (maxBytes != 0 && size >= maxBytes) {
+		return items, nil
+	}
+	if maxBytes != 0 {
+		maxBytes -= size
+	}
+	rest, err := r.AncientReaderOp.AncientRange(kind, next, start+count-next, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	return append(items, rest...), nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/erae"
+)
+
+// writeEraFiles exports the blocks into Era1 files of the given size, the last
+// file being a post-merge era file.
+func writeEraFiles(t *testing.T, dir string, blocks []*types.Block, receipts []types.Receipts, size int) {
+	td := new(big.Int)
+	for i := 0; i < len(blocks); i += size {
+		var (
+			last = i+size >= len(blocks)
+			name = era.Filename("test", i/size, common.Hash{})
+		)
+		if last {
+			name = erae.Filename("test", i/size, common.Hash{})
+		}
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Failed to create era file: %v", err)
+		}
+		var (
+			b1 = era.NewBuilder(f)
+			be = erae.NewBuilder(f)
+		)
+		for j := i; j < i+size && j < len(blocks); j++ {
+			if last {
+				err = be.Add(blocks[j], receipts[j], nil)
+			} else {
+				td.Add(td, blocks[j].Difficulty())
+				err = b1.Add(blocks[j], receipts[j], new(big.Int).Set(td))
+			}
+			if err != nil {
+				t.Fatalf("Failed to add block %d: %v", j, err)
+			}
+		}
+		if last {
+			_, err = be.Finalize()
+		} else {
+			_, err = b1.Finalize()
+		}
+		if err != nil {
+			t.Fatalf("Failed to finalize era file: %v", err)
+		}
+		f.Close()
+	}
+}
+
+func TestEraDatabase(t *testing.T) {
+	var (
+		blocks   []*types.Block
+		receipts = makeTestReceipts(100, 2)
+		parent   common.Hash
+	)
+	for i, block := range makeTestBlocks(100, 2) {
+		header := block.Header()
+		header.ParentHash = parent
+		header.Difficulty = big.NewInt(int64(i))
+		block = types.NewBlockWithHeader(header).WithBody(*block.Body())
+		blocks = append(blocks, block)
+		parent = block.Hash()
+	}
+	dir := t.TempDir()
+	writeEraFiles(t, dir, blocks[:80], receipts[:80], 32)
+
+	db, err := NewDatabaseWithFreezer(NewMemoryDatabase(), "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	if _, err := WriteAncientBlocks(db, blocks, receipts); err != nil {
+		t.Fatalf("Failed to write ancient blocks: %v", err)
+	}
+	if _, err := db.TruncateTail(70); err != nil {
+		t.Fatalf("Failed to truncate tail: %v", err)
+	}
+	if ReadBlock(db, blocks[10].Hash(), 10) != nil {
+		t.Fatal("Pruned block still available")
+	}
+	edb, err := NewDatabaseWithEra(db, dir, "test")
+	if err != nil {
+		t.Fatalf("Failed to open era database: %v", err)
+	}
+	defer edb.Close()
+
+	// Pruned blocks are served from the era files, the others from the freezer
+	for i, want := range blocks {
+		number := uint64(i)
+		if hash := ReadCanonicalHash(edb, number); hash != want.Hash() {
+			t.Fatalf("Canonical hash %d mismatch: have %x, want %x", i, hash, want.Hash())
+		}
+		block := ReadBlock(edb, want.Hash(), number)
+		if block == nil || block.Hash() != want.Hash() || len(block.Transactions()) != len(want.Transactions()) {
+			t.Fatalf("Block %d mismatch", i)
+		}
+		have := ReadRawReceipts(edb, want.Hash(), number)
+		if len(have) != len(receipts[i]) || have[0].CumulativeGasUsed != receipts[i][0].CumulativeGasUsed || len(have[0].Logs) != len(receipts[i][0].Logs) {
+			t.Fatalf("Receipts %d mismatch", i)
+		}
+	}
+	// Ranges spanning over the tail of the freezer are stitched together
+	bodies, err := edb.AncientRange(ChainFreezerBodiesTable, 60, 20, 0)
+	if err != nil || len(bodies) != 20 {
+		t.Fatalf("Unexpected body range: %d items, %v", len(bodies), err)
+	}
+	for i, body := range bodies {
+		if want := ReadCanonicalBodyRLP(db, uint64(60+i)); want != nil && string(want) != string(body) {
+			t.Fatalf("Body %d mismatch", 60+i)
+		}
+	}
+	if ok, _ := edb.HasAncient(ChainFreezerBodiesTable, 5); !ok {
+		t.Fatal("Pruned body not available")
+	}
+	// Blocks not covered by the era files remain unavailable
+	if _, err := NewDatabaseWithEra(db, filepath.Join(dir, "missing"), "test"); err == nil {
+		t.Fatal("Missing era directory accepted")
+	}
+	db.TruncateTail(90)
+	if ReadBlock(edb, blocks[85].Hash(), 85) != nil {
+		t.Fatal("Block beyond era files available")
+	}
+}
+
+func TestEraDatabaseVerification(t *testing.T) {
+	var (
+		blocks   = makeTestBlocks(40, 1)
+		receipts = makeTestReceipts(40, 1)
+		forked   []*types.Block
+	)
+	for _, block := range blocks {
+		header := block.Header()
+		header.Extra = []byte("fork")
+		forked = append(forked, types.NewBlockWithHeader(header).WithBody(*block.Body()))
+	}
+	// Era files of a different chain, and of another network
+	dir := t.TempDir()
+	writeEraFiles(t, dir, forked[:32], receipts[:32], 16)
+
+	other := t.TempDir()
+	writeEraFiles(t, other, blocks[:32], receipts[:32], 16)
+	entries, _ := os.ReadDir(other)
+	for _, entry := range entries {
+		if err := os.Rename(filepath.Join(other, entry.Name()), filepath.Join(dir, "other"+entry.Name()[len("test"):])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db, err := NewDatabaseWithFreezer(NewMemoryDatabase(), "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	if _, err := WriteAncientBlocks(db, blocks, receipts); err != nil {
+		t.Fatalf("Failed to write ancient blocks: %v", err)
+	}
+	if _, err := db.TruncateTail(20); err != nil {
+		t.Fatalf("Failed to truncate tail: %v", err)
+	}
+	edb, err := NewDatabaseWithEra(db, dir, "test")
+	if err != nil {
+		t.Fatalf("Failed to open era database: %v", err)
+	}
+	defer edb.Close()
+
+	// Blocks not matching the retained hashes are rejected
+	if _, err := edb.Ancient(ChainFreezerBodiesTable, 5); !errors.Is(err, errEraMismatch) {
+		t.Fatalf("Mismatching body error: have %v, want %v", err, errEraMismatch)
+	}
+	if _, err := edb.Ancient(ChainFreezerReceiptTable, 5); !errors.Is(err, errEraMismatch) {
+		t.Fatalf("Mismatching receipts error: have %v, want %v", err, errEraMismatch)
+	}
+	if ReadBlock(edb, blocks[5].Hash(), 5) != nil {
+		t.Fatal("Mismatching block served")
+	}
+	// Range errors are propagated instead of cutting the range short
+	if items, err := edb.AncientRange(ChainFreezerBodiesTable, 10, 20, 0); !errors.Is(err, errEraMismatch) {
+		t.Fatalf("Mismatching range error: have %d items, %v, want %v", len(items), err, errEraMismatch)
+	}
+	// The headers and hashes retained by the freezer are not overridden
+	if hash := ReadCanonicalHash(edb, 5); hash != blocks[5].Hash() {
+		t.Fatalf("Canonical hash mismatch: have %x, want %x", hash, blocks[5].Hash())
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
@@ -71,14 +72,28 @@ type Freezer struct {
 	tables       map[string]*freezerTable // Data tables for storing everything
 	instanceLock *flock.Flock             // File-system lock to prevent double opens
 	closeOnce    sync.Once
+
+	offloadLock sync.Mutex     // Lock serializing the offloading of sealed data files
+	quit        chan struct{}  // Channel terminating the offloader
+	wg          sync.WaitGroup // Tracks the offloader
 }
 
 // NewFreezer creates a freezer instance for maintaining immutable ordered
 // data according to the given parameters.
 //
-// The 'tables' argument defines the data tables. If the value of a map
-// entry is true, snappy compression is disabled for the table.
-func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize uint32, tables map[string]bool) (*Freezer, error) {
+// The 'tables' argument defines the data tables along with their settings.
+func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize uint32, tables map[string]freezerTableConfig) (*Freezer, error) {
+	return NewTieredFreezer(datadir, "", namespace, readonly, maxTableSize, tables)
+}
+
+// NewTieredFreezer creates a freezer instance whose sealed data files are moved
+// to the secondary storage tier at the given directory in the background, e.g.
+// a slower and cheaper storage such as a mounted object store bucket. The tier
+// is disabled if the directory is empty.
+//
+// The offloaded data files are tracked in the tier index of the freezer and
+// reopened on demand, reading the freezer remains unaffected.
+func NewTieredFreezer(datadir string, tierdir string, namespace string, readonly bool, maxTableSize uint32, tables map[string]freezerTableConfig) (*Freezer, error) {
 	// Create the initial freezer object
 	var (
 		readMeter  = metrics.NewRegisteredMeter(namespace+"ancient/read", nil)
@@ -112,17 +127,24 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 	} else if !locked {
 		return nil, errors.New("locking failed")
 	}
+	tier, err := newFreezerTier(datadir, tierdir, readonly)
+	if err != nil {
+		lock.Unlock()
+		return nil, err
+	}
 	// Open all the supported data tables
 	freezer := &Freezer{
 		datadir:      datadir,
 		readonly:     readonly,
 		tables:       make(map[string]*freezerTable),
 		instanceLock: lock,
+		quit:         make(chan struct{}),
 	}
 
 	// Create the tables.
-	for name, disableSnappy := range tables {
-		table, err := newTable(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, disableSnappy, readonly)
+	for name, config := range tables {
+		config.tier = tier
+		table, err := newTable(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, config, readonly)
 		if err != nil {
 			for _, table := range freezer.tables {
 				table.Close()
@@ -132,7 +154,6 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 		}
 		freezer.tables[name] = table
 	}
-	var err error
 	if freezer.readonly {
 		// In readonly mode only validate, don't truncate.
 		// validate also sets `freezer.frozen`.
@@ -152,6 +173,12 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 	// Create the write batch.
 	freezer.writeBatch = newFreezerBatch(freezer)
 
+	// Start offloading the sealed data files if a storage tier is configured.
+	if tier != nil && !readonly {
+		log.Info("Offloading sealed ancient data", "database", datadir, "tier", tierdir)
+		freezer.wg.Add(1)
+		go freezer.offloadLoop()
+	}
 	log.Info("Opened ancient database", "database", datadir, "readonly", readonly)
 	return freezer, nil
 }
@@ -163,6 +190,9 @@ func (f *Freezer) Close() error {
 
 	var errs []error
 	f.closeOnce.Do(func() {
+		close(f.quit)
+		f.wg.Wait()
+
 		for _, table := range f.tables {
 			if err := table.Close(); err != nil {
 				errs = append(errs, err)
@@ -314,6 +344,9 @@ func (f *Freezer) TruncateTail(tail uint64) (uint64, error) {
 		return old, nil
 	}
 	for _, table := range f.tables {
+		if !table.config.prunable {
+			continue
+		}
 		if err := table.truncateTail(tail); err != nil {
 			return 0, err
 		}
@@ -343,24 +376,28 @@ func (f *Freezer) validate() error {
 		return nil
 	}
 	var (
-		head uint64
-		tail uint64
-		name string
+		head     uint64
+		tail     uint64
+		name     string
+		prunable string
 	)
-	// Hack to get boundary of any table
+	// Hack to get boundary of any table, the tail being the one of the
+	// prunable tables
 	for kind, table := range f.tables {
 		head = table.items.Load()
-		tail = table.itemHidden.Load()
 		name = kind
-		break
+		if table.config.prunable {
+			tail = table.itemHidden.Load()
+			prunable = kind
+		}
 	}
 	// Now check every table against those boundaries.
 	for kind, table := range f.tables {
 		if head != table.items.Load() {
 			return fmt.Errorf("freezer tables %s and %s have differing head: %d != %d", kind, name, table.items.Load(), head)
 		}
-		if tail != table.itemHidden.Load() {
-			return fmt.Errorf("freezer tables %s and %s have differing tail: %d != %d", kind, name, table.itemHidden.Load(), tail)
+		if table.config.prunable && tail != table.itemHidden.Load() {
+			return fmt.Errorf("freezer tables %s and %s have differing tail: %d != %d", kind, prunable, table.itemHidden.Load(), tail)
 		}
 	}
 	f.frozen.Store(head)
@@ -379,6 +416,9 @@ func (f *Freezer) repair() error {
 		if head > items {
 			head = items
 		}
+		if !table.config.prunable {
+			continue
+		}
 		hidden := table.itemHidden.Load()
 		if hidden > tail {
 			tail = hidden
@@ -388,6 +428,9 @@ func (f *Freezer) repair() error {
 		if err := table.truncateHead(head); err != nil {
 			return err
 		}
+		if !table.config.prunable {
+			continue
+		}
 		if err := table.truncateTail(tail); err != nil {
 			return err
 		}
@@ -396,3 +439,37 @@ func (f *Freezer) repair() error {
 	f.tail.Store(tail)
 	return nil
 }
+
+// offloadLoop periodically moves the sealed data files of the tables to the
+// storage tier, until the freezer is closed.
+func (f *Freezer) offloadLoop() {
+	defer f.wg.Done()
+
+	for {
+		f.offload()
+
+		select {
+		case <-f.quit:
+			return
+		case <-time.After(offloadInterval):
+		}
+	}
+}
+
+// offload moves the sealed data files of all tables, which are not offloaded
+// yet, to the storage tier.
+func (f *Freezer) offload() {
+	f.offloadLock.Lock()
+	defer f.offloadLock.Unlock()
+
+	for name, table := range f.tables {
+		if err := table.offload(f.quit); err != nil {
+			select {
+			case <-f.quit:
+				return
+			default:
+				log.Error("Failed to offload freezer table", "table", name, "err", err)
+			}
+		}
+	}
+}
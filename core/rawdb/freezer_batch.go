@@ -86,6 +86,7 @@ type freezerTableBatch struct {
 	t *freezerTable
 
 	sb          *snappyBuffer
+	zb          []byte // buffer for zstd-compressed items
 	encBuffer   writeBuffer
 	dataBuffer  []byte
 	indexBuffer []byte
@@ -96,7 +97,7 @@ type freezerTableBatch struct {
 // newBatch creates a new batch for the freezer table.
 func (t *freezerTable) newBatch() *freezerTableBatch {
 	batch := &freezerTableBatch{t: t}
-	if !t.noCompression {
+	if !t.config.noSnappy {
 		batch.sb = new(snappyBuffer)
 	}
 	batch.reset()
@@ -124,11 +125,7 @@ func (batch *freezerTableBatch) Append(item uint64, data interface{}) error {
 	if err := rlp.Encode(&batch.encBuffer, data); err != nil {
 		return err
 	}
-	encItem := batch.encBuffer.data
-	if batch.sb != nil {
-		encItem = batch.sb.compress(encItem)
-	}
-	return batch.appendItem(encItem)
+	return batch.appendItem(batch.compress(batch.encBuffer.data))
 }
 
 // AppendRaw injects a binary blob at the end of the freezer table. The item number is a
@@ -139,11 +136,20 @@ func (batch *freezerTableBatch) AppendRaw(item uint64, blob []byte) error {
 		return fmt.Errorf("%w: have %d want %d", errOutOrderInsertion, item, batch.curItem)
 	}
 
-	encItem := blob
-	if batch.sb != nil {
-		encItem = batch.sb.compress(blob)
+	return batch.appendItem(batch.compress(blob))
+}
+
+// compress compresses an item with zstd if the table switched to it, snappy
+// otherwise, unless compression is disabled.
+func (batch *freezerTableBatch) compress(data []byte) []byte {
+	if batch.sb == nil {
+		return data
 	}
-	return batch.appendItem(encItem)
+	if t := batch.t; t.zstd != nil && batch.curItem >= t.metadata.zstdFrom {
+		batch.zb = t.zstd.compress(batch.zb, data)
+		return batch.zb
+	}
+	return batch.sb.compress(data)
 }
 
 func (batch *freezerTableBatch) appendItem(data []byte) error {
@@ -207,6 +213,10 @@ func (batch *freezerTableBatch) commit() error {
 	batch.t.sizeGauge.Inc(dataSize + indexSize)
 	batch.t.writeMeter.Mark(dataSize + indexSize)
 
+	// Switch the table to zstd once it holds enough items to train on.
+	if err := batch.t.maybeEnableZstd(); err != nil {
+		return err
+	}
 	// Periodically sync the table, todo (rjl493456442) make it configurable?
 	if time.Since(batch.t.lastSync) > 30*time.Second {
 		batch.t.lastSync = time.Now()
@@ -29,7 +29,8 @@ import (
 const (
 	freezerTableV1 = 1              // Initial version of metadata struct
 	freezerTableV2 = 2              // Add field: 'flushOffset'
-	freezerVersion = freezerTableV2 // The current used version
+	freezerTableV3 = 3              // Add fields: 'zstdFrom', 'zstdDict'
+	freezerVersion = freezerTableV2 // The current used version, v3 only for tables with a zstd dictionary
 )
 
 // freezerTableMeta is a collection of additional properties that describe the
@@ -65,6 +66,13 @@ type freezerTableMeta struct {
 	// The offset could be moved forward by applying sync operation, or be moved
 	// backward in cases of head/tail truncation, etc.
 	flushOffset int64
+
+	// zstdDict is the raw content dictionary used to compress the items with
+	// zstd, empty if the table doesn't use zstd (yet). The items stored before
+	// the dictionary was created are compressed with snappy, zstdFrom marks the
+	// first item compressed with zstd.
+	zstdFrom uint64
+	zstdDict []byte
 }
 
 // decodeV1 attempts to decode the metadata structure in v1 format. If fails or
@@ -123,6 +131,41 @@ func decodeV2(file *os.File) *freezerTableMeta {
 	}
 }
 
+// decodeV3 attempts to decode the metadata structure in v3 format. If fails or
+// the result is incompatible, nil is returned.
+func decodeV3(file *os.File) *freezerTableMeta {
+	_, err := file.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil
+	}
+	type obj struct {
+		Version  uint16
+		Tail     uint64
+		Offset   uint64
+		ZstdFrom uint64
+		ZstdDict []byte
+	}
+	var o obj
+	if err := rlp.Decode(file, &o); err != nil {
+		return nil
+	}
+	if o.Version != freezerTableV3 {
+		return nil
+	}
+	if o.Offset > math.MaxInt64 {
+		log.Error("Invalid flushOffset %d in freezer metadata", o.Offset, "file", file.Name())
+		return nil
+	}
+	return &freezerTableMeta{
+		file:        file,
+		version:     freezerTableV3,
+		virtualTail: o.Tail,
+		flushOffset: int64(o.Offset),
+		zstdFrom:    o.ZstdFrom,
+		zstdDict:    o.ZstdDict,
+	}
+}
+
 // newMetadata initializes the metadata object, either by loading it from the file
 // or by constructing a new one from scratch.
 func newMetadata(file *os.File) (*freezerTableMeta, error) {
@@ -133,7 +176,7 @@ func newMetadata(file *os.File) (*freezerTableMeta, error) {
 	if stat.Size() == 0 {
 		m := &freezerTableMeta{
 			file:        file,
-			version:     freezerTableV2,
+			version:     freezerVersion,
 			virtualTail: 0,
 			flushOffset: 0,
 		}
@@ -142,6 +185,9 @@ func newMetadata(file *os.File) (*freezerTableMeta, error) {
 		}
 		return m, nil
 	}
+	if m := decodeV3(file); m != nil {
+		return m, nil
+	}
 	if m := decodeV2(file); m != nil {
 		return m, nil
 	}
@@ -163,23 +209,51 @@ func (m *freezerTableMeta) setFlushOffset(offset int64, sync bool) error {
 	return m.write(sync)
 }
 
+// setZstd sets the zstd dictionary along with the first item compressed with
+// it, and flushes the metadata.
+func (m *freezerTableMeta) setZstd(from uint64, dict []byte) error {
+	m.zstdFrom = from
+	m.zstdDict = dict
+	return m.write(true)
+}
+
 // write flushes the content of metadata into file and performs a fsync if required.
+// The v3 format is only used by the tables having a zstd dictionary, keeping the
+// others readable by the releases predating it.
 func (m *freezerTableMeta) write(sync bool) error {
-	type obj struct {
+	type objV2 struct {
 		Version uint16
 		Tail    uint64
 		Offset  uint64
 	}
-	var o obj
-	o.Version = freezerVersion // forcibly use the current version
-	o.Tail = m.virtualTail
-	o.Offset = uint64(m.flushOffset)
-
+	type objV3 struct {
+		Version  uint16
+		Tail     uint64
+		Offset   uint64
+		ZstdFrom uint64
+		ZstdDict []byte
+	}
+	var o any
+	if len(m.zstdDict) == 0 {
+		o = &objV2{
+			Version: freezerVersion, // forcibly use the current version
+			Tail:    m.virtualTail,
+			Offset:  uint64(m.flushOffset),
+		}
+	} else {
+		o = &objV3{
+			Version:  freezerTableV3,
+			Tail:     m.virtualTail,
+			Offset:   uint64(m.flushOffset),
+			ZstdFrom: m.zstdFrom,
+			ZstdDict: m.zstdDict,
+		}
+	}
 	_, err := m.file.Seek(0, io.SeekStart)
 	if err != nil {
 		return err
 	}
-	if err := rlp.Encode(m.file, &o); err != nil {
+	if err := rlp.Encode(m.file, o); err != nil {
 		return err
 	}
 	if !sync {
@@ -100,6 +100,52 @@ func TestUpgradeMetadata(t *testing.T) {
 	}
 }
 
+func TestUpgradeMetadataV2(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "*")
+	if err != nil {
+		t.Fatalf("Failed to create file %v", err)
+	}
+	defer f.Close()
+
+	// Write v2 metadata into file
+	type obj struct {
+		Version uint16
+		Tail    uint64
+		Offset  uint64
+	}
+	o := obj{Version: freezerTableV2, Tail: 100, Offset: 200}
+	if err := rlp.Encode(f, &o); err != nil {
+		t.Fatalf("Failed to encode %v", err)
+	}
+	meta, err := newMetadata(f)
+	if err != nil {
+		t.Fatalf("Failed to read metadata %v", err)
+	}
+	if meta.version != freezerTableV2 {
+		t.Fatal("Unexpected version field")
+	}
+	if len(meta.zstdDict) != 0 {
+		t.Fatal("Unexpected zstd dictionary")
+	}
+	// Enable zstd, a silent upgrade is expected
+	if err := meta.setZstd(300, []byte("dictionary")); err != nil {
+		t.Fatalf("Failed to write metadata %v", err)
+	}
+	meta, err = newMetadata(f)
+	if err != nil {
+		t.Fatalf("Failed to read metadata %v", err)
+	}
+	if meta.version != freezerTableV3 {
+		t.Fatal("Unexpected version field")
+	}
+	if meta.virtualTail != 100 || meta.flushOffset != 200 {
+		t.Fatalf("Unexpected fields: tail %d, offset %d", meta.virtualTail, meta.flushOffset)
+	}
+	if meta.zstdFrom != 300 || string(meta.zstdDict) != "dictionary" {
+		t.Fatalf("Unexpected zstd fields: from %d, dict %q", meta.zstdFrom, meta.zstdDict)
+	}
+}
+
 func TestInvalidMetadata(t *testing.T) {
 	f, err := os.CreateTemp(t.TempDir(), "*")
 	if err != nil {
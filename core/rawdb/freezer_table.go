@@ -100,11 +100,11 @@ type freezerTable struct {
 	// should never be lower than itemOffset.
 	itemHidden atomic.Uint64
 
-	noCompression bool // if true, disables snappy compression. Note: does not work retroactively
-	readonly      bool
-	maxFileSize   uint32 // Max file size for data-files
-	name          string
-	path          string
+	config      freezerTableConfig // if noSnappy is set, disables snappy compression. Note: does not work retroactively
+	readonly    bool
+	maxFileSize uint32 // Max file size for data-files
+	name        string
+	path        string
 
 	head   *os.File            // File descriptor for the data head of the table
 	index  *os.File            // File descriptor for the indexEntry file of the table
@@ -112,8 +112,14 @@ type freezerTable struct {
 	headId uint32              // number of the currently active head file
 	tailId uint32              // number of the earliest file
 
-	metadata *freezerTableMeta // metadata of the table
-	lastSync time.Time         // Timestamp when the last sync was performed
+	tierFiles map[uint32]*os.File // opened data files offloaded to the storage tier
+	tierLock  sync.Mutex          // Mutex protecting the offloaded file descriptors
+	rewinds   uint64              // Number of head truncations into sealed data files
+
+	metadata  *freezerTableMeta // metadata of the table
+	zstd      *zstdCodec        // zstd codec of the items from metadata.zstdFrom on, nil if snappy only
+	zstdRetry uint64            // Number of items before retrying a failed dictionary training
+	lastSync  time.Time         // Timestamp when the last sync was performed
 
 	headBytes  int64          // Number of bytes written to the head file
 	readMeter  *metrics.Meter // Meter for measuring the effective amount of data read
@@ -126,19 +132,20 @@ type freezerTable struct {
 
 // newFreezerTable opens the given path as a freezer table.
 func newFreezerTable(path, name string, disableSnappy, readonly bool) (*freezerTable, error) {
-	return newTable(path, name, metrics.NewInactiveMeter(), metrics.NewInactiveMeter(), metrics.NewGauge(), freezerTableSize, disableSnappy, readonly)
+	config := freezerTableConfig{noSnappy: disableSnappy, prunable: true}
+	return newTable(path, name, metrics.NewInactiveMeter(), metrics.NewInactiveMeter(), metrics.NewGauge(), freezerTableSize, config, readonly)
 }
 
 // newTable opens a freezer table, creating the data and index files if they are
 // non-existent. Both files are truncated to the shortest common length to ensure
 // they don't go out of sync.
-func newTable(path string, name string, readMeter, writeMeter *metrics.Meter, sizeGauge *metrics.Gauge, maxFilesize uint32, noCompression, readonly bool) (*freezerTable, error) {
+func newTable(path string, name string, readMeter, writeMeter *metrics.Meter, sizeGauge *metrics.Gauge, maxFilesize uint32, config freezerTableConfig, readonly bool) (*freezerTable, error) {
 	// Ensure the containing directory exists and open the indexEntry file
 	if err := os.MkdirAll(path, 0755); err != nil {
 		return nil, err
 	}
 	var idxName string
-	if noCompression {
+	if config.noSnappy {
 		idxName = fmt.Sprintf("%s.ridx", name) // raw index file
 	} else {
 		idxName = fmt.Sprintf("%s.cidx", name) // compressed index file
@@ -176,19 +183,26 @@ func newTable(path string, name string, readMeter, writeMeter *metrics.Meter, si
 	}
 	// Create the table and repair any past inconsistency
 	tab := &freezerTable{
-		index:         index,
-		metadata:      metadata,
-		lastSync:      time.Now(),
-		files:         make(map[uint32]*os.File),
-		readMeter:     readMeter,
-		writeMeter:    writeMeter,
-		sizeGauge:     sizeGauge,
-		name:          name,
-		path:          path,
-		logger:        log.New("database", path, "table", name),
-		noCompression: noCompression,
-		readonly:      readonly,
-		maxFileSize:   maxFilesize,
+		index:       index,
+		metadata:    metadata,
+		lastSync:    time.Now(),
+		files:       make(map[uint32]*os.File),
+		tierFiles:   make(map[uint32]*os.File),
+		readMeter:   readMeter,
+		writeMeter:  writeMeter,
+		sizeGauge:   sizeGauge,
+		name:        name,
+		path:        path,
+		logger:      log.New("database", path, "table", name),
+		config:      config,
+		readonly:    readonly,
+		maxFileSize: maxFilesize,
+	}
+	if len(metadata.zstdDict) > 0 {
+		if tab.zstd, err = newZstdCodec(metadata.zstdDict); err != nil {
+			tab.Close()
+			return nil, err
+		}
 	}
 	if err := tab.repair(); err != nil {
 		tab.Close()
@@ -276,12 +290,7 @@ func (t *freezerTable) repair() error {
 		t.index.ReadAt(buffer, offsetsSize-indexEntrySize)
 		lastIndex.unmarshalBinary(buffer)
 	}
-	if t.readonly {
-		t.head, err = t.openFile(lastIndex.filenum, openFreezerFileForReadOnly)
-	} else {
-		t.head, err = t.openFile(lastIndex.filenum, openFreezerFileForAppend)
-	}
-	if err != nil {
+	if t.head, err = t.openHead(lastIndex.filenum); err != nil {
 		return err
 	}
 	if stat, err = t.head.Stat(); err != nil {
@@ -338,7 +347,7 @@ func (t *freezerTable) repair() error {
 			if newLastIndex.filenum != lastIndex.filenum {
 				// Release earlier opened file
 				t.releaseFile(lastIndex.filenum)
-				if t.head, err = t.openFile(newLastIndex.filenum, openFreezerFileForAppend); err != nil {
+				if t.head, err = t.openHead(newLastIndex.filenum); err != nil {
 					return err
 				}
 				if stat, err = t.head.Stat(); err != nil {
@@ -376,6 +385,16 @@ func (t *freezerTable) repair() error {
 	// Delete the leftover files because of tail deletion
 	t.releaseFilesBefore(t.tailId, true)
 
+	// Forget the offloaded files dropped by either deletion
+	if !t.readonly {
+		err := t.dropOffloaded(func(num uint32) bool {
+			return num < t.tailId || num >= t.headId
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	// Close opened files and preopen all files
 	if err := t.preopen(); err != nil {
 		return err
@@ -581,18 +600,20 @@ func (t *freezerTable) preopen() (err error) {
 	// The repair might have already opened (some) files
 	t.releaseFilesAfter(0, false)
 
-	// Open all except head in RDONLY
+	// Open all except head in RDONLY, the offloaded ones are opened on demand
 	for i := t.tailId; i < t.headId; i++ {
+		if hash, ok := t.offloaded(i); ok {
+			if err = t.checkOffloaded(i, hash); err != nil {
+				return err
+			}
+			continue
+		}
 		if _, err = t.openFile(i, openFreezerFileForReadOnly); err != nil {
 			return err
 		}
 	}
-	if t.readonly {
-		t.head, err = t.openFile(t.headId, openFreezerFileForReadOnly)
-	} else {
-		// Open head in read/write
-		t.head, err = t.openFile(t.headId, openFreezerFileForAppend)
-	}
+	// Open head in read/write
+	t.head, err = t.openHead(t.headId)
 	return err
 }
 
@@ -641,6 +662,13 @@ func (t *freezerTable) truncateHead(items uint64) error {
 			return err
 		}
 	}
+	// If the snappy items preceding the zstd ones are truncated, the items
+	// appended from now on are zstd-compressed.
+	if t.zstd != nil && t.metadata.zstdFrom > items {
+		if err := t.metadata.setZstd(items, t.metadata.zstdDict); err != nil {
+			return err
+		}
+	}
 	// Calculate the new expected size of the data file and truncate it
 	var expected indexEntry
 	if length == 0 {
@@ -654,15 +682,20 @@ func (t *freezerTable) truncateHead(items uint64) error {
 	}
 	// We might need to truncate back to older files
 	if expected.filenum != t.headId {
+		t.rewinds++
+
 		// If already open for reading, force-reopen for writing
 		t.releaseFile(expected.filenum)
-		newHead, err := t.openFile(expected.filenum, openFreezerFileForAppend)
+		newHead, err := t.openHead(expected.filenum)
 		if err != nil {
 			return err
 		}
 		// Release any files _after the current head -- both the previous head
 		// and any files which may have been opened for reading
 		t.releaseFilesAfter(expected.filenum, true)
+		if err := t.dropOffloaded(func(num uint32) bool { return num > expected.filenum }); err != nil {
+			return err
+		}
 
 		// Set back the historic head
 		t.head = newHead
@@ -810,6 +843,9 @@ func (t *freezerTable) truncateTail(items uint64) error {
 	t.tailId = newTailId
 	t.itemOffset.Store(newDeleted)
 	t.releaseFilesBefore(t.tailId, true)
+	if err := t.dropOffloaded(func(num uint32) bool { return num < t.tailId }); err != nil {
+		return err
+	}
 
 	// Move the index flush offset backward due to the deletion of an index segment.
 	// A crash may occur before the offset is updated, leaving a dangling reference
@@ -856,10 +892,16 @@ func (t *freezerTable) Close() error {
 	for _, f := range t.files {
 		doClose(f)
 	}
+	t.closeOffloaded(func(uint32) bool { return true })
 	t.index = nil
 	t.head = nil
 	t.metadata.file = nil
 
+	if t.zstd != nil {
+		t.zstd.close()
+		t.zstd = nil
+	}
+
 	if errs != nil {
 		return fmt.Errorf("%v", errs)
 	}
@@ -870,13 +912,7 @@ func (t *freezerTable) Close() error {
 func (t *freezerTable) openFile(num uint32, opener func(string) (*os.File, error)) (f *os.File, err error) {
 	var exist bool
 	if f, exist = t.files[num]; !exist {
-		var name string
-		if t.noCompression {
-			name = fmt.Sprintf("%s.%04d.rdat", t.name, num)
-		} else {
-			name = fmt.Sprintf("%s.%04d.cdat", t.name, num)
-		}
-		f, err = opener(filepath.Join(t.path, name))
+		f, err = opener(filepath.Join(t.path, t.dataFileName(num)))
 		if err != nil {
 			return nil, err
 		}
@@ -981,19 +1017,36 @@ func (t *freezerTable) RetrieveItems(start, count, maxBytes uint64) ([][]byte, e
 		output     = make([][]byte, 0, count)
 		offset     int // offset for reading
 		outputSize int // size of uncompressed data
+
+		codec, zstdFrom = t.zstdCodec()
 	)
 	// Now slice up the data and decompress.
 	for i, diskSize := range sizes {
 		item := diskData[offset : offset+diskSize]
 		offset += diskSize
+
+		// Items stored after the table switched to zstd are decompressed
+		// first, zstd lacking a cheap way to retrieve the decompressed size.
+		if codec != nil && start+uint64(i) >= zstdFrom {
+			data, err := codec.decompress(item)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 && maxBytes != 0 && uint64(outputSize+len(data)) > maxBytes {
+				break
+			}
+			output = append(output, data)
+			outputSize += len(data)
+			continue
+		}
 		decompressedSize := diskSize
-		if !t.noCompression {
+		if !t.config.noSnappy {
 			decompressedSize, _ = snappy.DecodedLen(item)
 		}
 		if i > 0 && maxBytes != 0 && uint64(outputSize+decompressedSize) > maxBytes {
 			break
 		}
-		if !t.noCompression {
+		if !t.config.noSnappy {
 			data, err := snappy.Decode(nil, item)
 			if err != nil {
 				return nil, err
@@ -1043,7 +1096,10 @@ func (t *freezerTable) retrieveItems(start, count, maxBytes uint64) ([]byte, []i
 		output = grow(output, length)
 		dataFile, exist := t.files[fileId]
 		if !exist {
-			return fmt.Errorf("missing data file %d", fileId)
+			var err error
+			if dataFile, err = t.openOffloaded(fileId); err != nil {
+				return err
+			}
 		}
 		if _, err := dataFile.ReadAt(output[len(output)-length:], int64(start)); err != nil {
 			return fmt.Errorf("%w, fileid: %d, start: %d, length: %d", err, fileId, start, length)
@@ -0,0 +1,484 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// The data files of a table are sealed once the table advances to the next one,
+// and are never modified again unless the head is truncated back into them.
+// Sealed files can be offloaded to a secondary storage tier, typically slower
+// and cheaper, such as a directory backed by an object store bucket.
+//
+// Offloaded files are content-addressed: they are stored in the tier under the
+// sha256 hash of their content, which is recorded in the tier index shared by
+// all tables of the freezer. They are opened lazily, upon the first read of an
+// item they hold, and are moved back into the table directory if the head is
+// truncated into them.
+
+// offloadInterval is the time between two attempts of the freezer to offload
+// the sealed data files of its tables.
+const offloadInterval = time.Minute
+
+// copyBufferSize is the size of the chunks the files are copied in, between
+// which the copy can be aborted.
+const copyBufferSize = 1024 * 1024
+
+// tierIndexName is the name of the tier index file in the freezer directory.
+const tierIndexName = "TIERINDEX"
+
+// errTierMissing is returned if the freezer holds offloaded data files, but is
+// opened without a storage tier.
+var errTierMissing = errors.New("ancient data offloaded, but no storage tier configured")
+
+// tierIndexEntry is the encoding of an offloaded data file in the tier index.
+type tierIndexEntry struct {
+	Table  string
+	Number uint32
+	Hash   common.Hash
+}
+
+// freezerTier is the secondary storage tier of a freezer, along with the index
+// of the data files offloaded to it by the tables.
+type freezerTier struct {
+	dir      string // directory the sealed data files are offloaded to
+	index    string // path of the tier index file in the freezer directory
+	readonly bool
+
+	files map[string]map[uint32]common.Hash // content hashes of the offloaded files, by table and number
+	lock  sync.RWMutex
+}
+
+// newFreezerTier opens the storage tier at the given directory for the freezer
+// at datadir, loading the index of the offloaded data files. Nil is returned if
+// the tier directory is empty and no data file was offloaded.
+func newFreezerTier(datadir string, tierdir string, readonly bool) (*freezerTier, error) {
+	tier := &freezerTier{
+		dir:      tierdir,
+		index:    filepath.Join(datadir, tierIndexName),
+		readonly: readonly,
+		files:    make(map[string]map[uint32]common.Hash),
+	}
+	blob, err := os.ReadFile(tier.index)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var entries []tierIndexEntry
+	if len(blob) > 0 {
+		if err := rlp.DecodeBytes(blob, &entries); err != nil {
+			return nil, fmt.Errorf("invalid tier index: %w", err)
+		}
+	}
+	if tierdir == "" {
+		if len(entries) > 0 {
+			return nil, errTierMissing
+		}
+		return nil, nil
+	}
+	for _, entry := range entries {
+		if tier.files[entry.Table] == nil {
+			tier.files[entry.Table] = make(map[uint32]common.Hash)
+		}
+		tier.files[entry.Table][entry.Number] = entry.Hash
+	}
+	if !readonly {
+		if err := os.MkdirAll(tierdir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	return tier, nil
+}
+
+// offloaded returns the content hash of an offloaded data file of the table.
+func (t *freezerTier) offloaded(table string, num uint32) (common.Hash, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	hash, ok := t.files[table][num]
+	return hash, ok
+}
+
+// referenced reports whether any offloaded data file of the table is stored
+// under the given content hash.
+func (t *freezerTier) referenced(table string, hash common.Hash) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	for _, h := range t.files[table] {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// add records a data file of the table as offloaded, and flushes the index.
+func (t *freezerTier) add(table string, num uint32, hash common.Hash) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.files[table] == nil {
+		t.files[table] = make(map[uint32]common.Hash)
+	}
+	t.files[table][num] = hash
+	if err := t.write(); err != nil {
+		delete(t.files[table], num)
+		return err
+	}
+	return nil
+}
+
+// drop forgets the offloaded data files of the table matching the filter, and
+// flushes the index. The content hashes of the dropped files are returned.
+func (t *freezerTier) drop(table string, match func(uint32) bool) ([]common.Hash, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var hashes []common.Hash
+	for num, hash := range t.files[table] {
+		if match(num) {
+			delete(t.files[table], num)
+			hashes = append(hashes, hash)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+	return hashes, t.write()
+}
+
+// write flushes the index to disk, replacing the previous one atomically. The
+// caller must hold the write lock.
+func (t *freezerTier) write() error {
+	if t.readonly {
+		return errReadOnly
+	}
+	var entries []tierIndexEntry
+	for table, files := range t.files {
+		for num, hash := range files {
+			entries = append(entries, tierIndexEntry{Table: table, Number: num, Hash: hash})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Table != entries[j].Table {
+			return entries[i].Table < entries[j].Table
+		}
+		return entries[i].Number < entries[j].Number
+	})
+	blob, err := rlp.EncodeToBytes(entries)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(t.index + ".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(blob); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(t.index+".tmp", t.index)
+}
+
+// dataFileName returns the name of a data file in the table directory.
+func (t *freezerTable) dataFileName(num uint32) string {
+	if t.config.noSnappy {
+		return fmt.Sprintf("%s.%04d.rdat", t.name, num) // raw data file
+	}
+	return fmt.Sprintf("%s.%04d.cdat", t.name, num) // compressed data file
+}
+
+// offloadPath returns the path of a data file with the given content hash in
+// the storage tier.
+func (t *freezerTable) offloadPath(hash common.Hash) string {
+	ext := "cdat"
+	if t.config.noSnappy {
+		ext = "rdat"
+	}
+	return filepath.Join(t.config.tier.dir, fmt.Sprintf("%s.%x.%s", t.name, hash, ext))
+}
+
+// offloaded returns the content hash of a data file of the table if it was
+// offloaded to the storage tier.
+func (t *freezerTable) offloaded(num uint32) (common.Hash, bool) {
+	if t.config.tier == nil {
+		return common.Hash{}, false
+	}
+	return t.config.tier.offloaded(t.name, num)
+}
+
+// pending reports whether a data file is sealed and not offloaded yet, and the
+// head wasn't truncated into the sealed files since the given number of rewinds.
+// The caller must hold the lock.
+func (t *freezerTable) pending(num uint32, rewinds uint64) bool {
+	if _, ok := t.offloaded(num); ok {
+		return false
+	}
+	return num >= t.tailId && num < t.headId && t.rewinds == rewinds
+}
+
+// offload moves the sealed data files of the table which are not offloaded yet
+// to the storage tier. The files are copied without holding the table lock, so
+// readers and writers are not blocked meanwhile. The copy stops early if the
+// abort channel is closed.
+func (t *freezerTable) offload(abort <-chan struct{}) error {
+	if t.config.tier == nil || t.readonly {
+		return nil
+	}
+	t.lock.RLock()
+	if t.index == nil {
+		t.lock.RUnlock()
+		return errClosed
+	}
+	var (
+		files   []uint32
+		rewinds = t.rewinds
+	)
+	for num := t.tailId; num < t.headId; num++ {
+		if t.pending(num, rewinds) {
+			files = append(files, num)
+		}
+	}
+	t.lock.RUnlock()
+
+	for _, num := range files {
+		if err := t.offloadFile(num, rewinds, abort); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// offloadFile copies a sealed data file to the storage tier, and removes the
+// local file once the copy is recorded in the tier index. The copy is discarded
+// if the file was modified or dropped meanwhile.
+func (t *freezerTable) offloadFile(num uint32, rewinds uint64, abort <-chan struct{}) error {
+	var (
+		start = time.Now()
+		src   = filepath.Join(t.path, t.dataFileName(num))
+	)
+	tmp, hash, err := copyFileHashed(src, t.config.tier.dir, abort)
+	if err != nil {
+		// The file might have been dropped by a tail truncation meanwhile
+		t.lock.RLock()
+		defer t.lock.RUnlock()
+
+		if t.index != nil && !t.pending(num, rewinds) {
+			return nil
+		}
+		return err
+	}
+	dst := t.offloadPath(hash)
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil {
+		return errClosed
+	}
+	if !t.pending(num, rewinds) {
+		if !t.config.tier.referenced(t.name, hash) {
+			os.Remove(dst)
+		}
+		return nil
+	}
+	if err := t.config.tier.add(t.name, num, hash); err != nil {
+		return err
+	}
+	t.releaseFile(num)
+	if err := os.Remove(src); err != nil {
+		return err
+	}
+	t.logger.Info("Offloaded freezer data file", "number", num, "hash", hash, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// openOffloaded returns the descriptor of an offloaded data file, opening it
+// from the storage tier upon the first access. The caller must hold the lock.
+func (t *freezerTable) openOffloaded(num uint32) (*os.File, error) {
+	t.tierLock.Lock()
+	defer t.tierLock.Unlock()
+
+	if f, exist := t.tierFiles[num]; exist {
+		return f, nil
+	}
+	hash, ok := t.offloaded(num)
+	if !ok {
+		return nil, fmt.Errorf("missing data file %d", num)
+	}
+	f, err := openFreezerFileForReadOnly(t.offloadPath(hash))
+	if err != nil {
+		return nil, err
+	}
+	t.tierFiles[num] = f
+	return f, nil
+}
+
+// checkOffloaded ensures an offloaded data file is present in the storage tier,
+// and removes the local copy left behind by an interrupted offload or restore.
+// The caller must hold the write lock.
+func (t *freezerTable) checkOffloaded(num uint32, hash common.Hash) error {
+	if _, err := os.Stat(t.offloadPath(hash)); err != nil {
+		return fmt.Errorf("missing offloaded data file %d: %w", num, err)
+	}
+	if t.readonly {
+		return nil
+	}
+	if err := os.Remove(filepath.Join(t.path, t.dataFileName(num))); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// openHead opens a data file as the head of the table, moving it back from the
+// storage tier first if it was offloaded. The caller must hold the write lock.
+func (t *freezerTable) openHead(num uint32) (*os.File, error) {
+	if t.readonly {
+		return t.openFile(num, openFreezerFileForReadOnly)
+	}
+	if hash, ok := t.offloaded(num); ok {
+		if err := t.restore(num, hash); err != nil {
+			return nil, err
+		}
+	}
+	return t.openFile(num, openFreezerFileForAppend)
+}
+
+// restore moves an offloaded data file back into the table directory. The
+// caller must hold the write lock.
+func (t *freezerTable) restore(num uint32, hash common.Hash) error {
+	tmp, have, err := copyFileHashed(t.offloadPath(hash), t.path, nil)
+	if err != nil {
+		return err
+	}
+	if have != hash {
+		os.Remove(tmp)
+		return fmt.Errorf("corrupted offloaded data file %d, hash %x, want %x", num, have, hash)
+	}
+	if err := os.Rename(tmp, filepath.Join(t.path, t.dataFileName(num))); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	t.logger.Info("Restored offloaded freezer data file", "number", num, "hash", hash)
+	return t.dropOffloaded(func(n uint32) bool { return n == num })
+}
+
+// dropOffloaded forgets the offloaded data files matching the filter, and
+// removes them from the storage tier unless their content is shared with other
+// files of the table. The caller must hold the write lock.
+func (t *freezerTable) dropOffloaded(drop func(uint32) bool) error {
+	if t.config.tier == nil {
+		return nil
+	}
+	t.closeOffloaded(drop)
+
+	// Update the index first, a crash leaves unreferenced files in the tier
+	// rather than references to missing files.
+	hashes, err := t.config.tier.drop(t.name, drop)
+	if err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		if !t.config.tier.referenced(t.name, hash) {
+			os.Remove(t.offloadPath(hash))
+		}
+	}
+	return nil
+}
+
+// closeOffloaded closes the opened offloaded data files matching the filter.
+// The caller must hold the write lock.
+func (t *freezerTable) closeOffloaded(match func(uint32) bool) {
+	t.tierLock.Lock()
+	defer t.tierLock.Unlock()
+
+	for num, f := range t.tierFiles {
+		if match(num) {
+			delete(t.tierFiles, num)
+			f.Close()
+		}
+	}
+}
+
+// copyFileHashed copies a file into a temporary file of the given directory,
+// returning the path of the copy along with the sha256 hash of its content. The
+// copy is flushed to disk, ready to be renamed into place. It stops with an
+// error if the abort channel is closed.
+func copyFileHashed(srcPath, dir string, abort <-chan struct{}) (string, common.Hash, error) {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return "", common.Hash{}, err
+	}
+	defer src.Close()
+
+	f, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return "", common.Hash{}, err
+	}
+	var (
+		fname  = f.Name()
+		hasher = sha256.New()
+		dst    = io.MultiWriter(f, hasher)
+	)
+	fail := func(err error) (string, common.Hash, error) {
+		f.Close()
+		os.Remove(fname)
+		return "", common.Hash{}, err
+	}
+	for {
+		select {
+		case <-abort:
+			return fail(errClosed)
+		default:
+		}
+		if _, err := io.CopyN(dst, src, copyBufferSize); err == io.EOF {
+			break
+		} else if err != nil {
+			return fail(err)
+		}
+	}
+	if err := f.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(fname)
+		return "", common.Hash{}, err
+	}
+	return fname, common.BytesToHash(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,277 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// checkTier ensures exactly the given data files of the table are moved to
+// the storage tier, while the others remain in the table directory.
+func checkTier(t *testing.T, f *freezerTable, offloaded ...uint32) {
+	t.Helper()
+
+	want := make(map[uint32]bool)
+	for _, num := range offloaded {
+		want[num] = true
+	}
+	if files := f.config.tier.files[f.name]; len(files) != len(want) {
+		t.Fatalf("Wrong number of offloaded files: have %d, want %d", len(files), len(want))
+	}
+	for num := f.tailId; num <= f.headId; num++ {
+		hash, ok := f.offloaded(num)
+		if ok != want[num] {
+			t.Fatalf("File %d: offloaded %t, want %t", num, ok, want[num])
+		}
+		_, err := os.Stat(filepath.Join(f.path, f.dataFileName(num)))
+		if local := err == nil; local == want[num] {
+			t.Fatalf("File %d: present locally %t, want %t", num, local, !want[num])
+		}
+		if ok {
+			if _, err := os.Stat(f.offloadPath(hash)); err != nil {
+				t.Fatalf("File %d missing from tier: %v", num, err)
+			}
+		}
+	}
+	if files, _ := os.ReadDir(f.config.tier.dir); len(files) != len(want) {
+		t.Fatalf("Wrong number of files in tier: have %d, want %d", len(files), len(want))
+	}
+}
+
+// newTestTier opens the storage tier at tierdir for the tables at datadir.
+func newTestTier(t *testing.T, datadir, tierdir string, readonly bool) *freezerTier {
+	t.Helper()
+
+	tier, err := newFreezerTier(datadir, tierdir, readonly)
+	if err != nil {
+		t.Fatalf("Failed to open storage tier: %v", err)
+	}
+	return tier
+}
+
+// makeChunks creates the items written by writeChunks.
+func makeChunks(n int, length int) map[uint64][]byte {
+	items := make(map[uint64][]byte)
+	for i := 0; i < n; i++ {
+		items[uint64(i)] = getChunk(length, i)
+	}
+	return items
+}
+
+func TestFreezerTableOffload(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir    = t.TempDir()
+		tier   = t.TempDir()
+		fname  = fmt.Sprintf("offload-%d", rand.Uint64())
+		config = freezerTableConfig{noSnappy: true, prunable: true, tier: newTestTier(t, dir, tier, false)}
+		items  = makeChunks(30, 15)
+	)
+	// Write 10 files of 3 items each, and offload the sealed ones.
+	f, err := newTable(dir, fname, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 50, config, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeChunks(t, f, 30, 15)
+	if err := f.offload(nil); err != nil {
+		t.Fatalf("Failed to offload: %v", err)
+	}
+	checkTier(t, f, 0, 1, 2, 3, 4, 5, 6, 7, 8)
+	checkRetrieve(t, f, items)
+
+	// Items spanning across offloaded and local files are retrieved together.
+	have, err := f.RetrieveItems(20, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to retrieve items: %v", err)
+	}
+	for i, item := range have {
+		if !bytes.Equal(item, items[20+uint64(i)]) {
+			t.Fatalf("Item %d mismatch", 20+i)
+		}
+	}
+	// Offloading again is a noop.
+	if err := f.offload(nil); err != nil {
+		t.Fatalf("Failed to offload: %v", err)
+	}
+	checkTier(t, f, 0, 1, 2, 3, 4, 5, 6, 7, 8)
+	f.Close()
+
+	// Leave a local copy behind, as an interrupted offload would.
+	hash, _ := f.offloaded(4)
+	blob, _ := os.ReadFile(f.offloadPath(hash))
+	os.WriteFile(filepath.Join(dir, f.dataFileName(4)), blob, 0644)
+
+	// Reopen the table in both modes, the offloaded files must be opened lazily.
+	for _, readonly := range []bool{false, true} {
+		config.tier = newTestTier(t, dir, tier, readonly)
+		f, err = newTable(dir, fname, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 50, config, readonly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkTier(t, f, 0, 1, 2, 3, 4, 5, 6, 7, 8)
+		if len(f.tierFiles) != 0 {
+			t.Fatalf("Offloaded files opened eagerly: %d", len(f.tierFiles))
+		}
+		checkRetrieve(t, f, items)
+		if len(f.tierFiles) != 9 {
+			t.Fatalf("Wrong number of opened offloaded files: have %d, want %d", len(f.tierFiles), 9)
+		}
+		f.Close()
+	}
+	// A table missing an offloaded file can't be opened.
+	os.Remove(f.offloadPath(hash))
+	config.tier = newTestTier(t, dir, tier, false)
+	if _, err := newTable(dir, fname, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 50, config, false); err == nil {
+		t.Fatal("Opened table with missing offloaded file")
+	}
+}
+
+func TestFreezerTableOffloadTruncate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir    = t.TempDir()
+		fname  = fmt.Sprintf("offload-truncate-%d", rand.Uint64())
+		config = freezerTableConfig{noSnappy: true, prunable: true, tier: newTestTier(t, dir, t.TempDir(), false)}
+		items  = makeChunks(30, 15)
+	)
+	f, err := newTable(dir, fname, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 50, config, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	writeChunks(t, f, 30, 15)
+	if err := f.offload(nil); err != nil {
+		t.Fatalf("Failed to offload: %v", err)
+	}
+	// Truncate the head into an offloaded file, it must be moved back.
+	if err := f.truncateHead(10); err != nil {
+		t.Fatal(err)
+	}
+	if f.headId != 3 {
+		t.Fatalf("Wrong head file: have %d, want %d", f.headId, 3)
+	}
+	checkTier(t, f, 0, 1, 2)
+	checkRetrieveError(t, f, map[uint64]error{10: errOutOfBounds})
+
+	// Refill the table, the files sealed again are offloaded again.
+	batch := f.newBatch()
+	for i := uint64(10); i < 30; i++ {
+		if err := batch.AppendRaw(i, items[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := batch.commit(); err != nil {
+		t.Fatal(err)
+	}
+	checkTier(t, f, 0, 1, 2)
+	checkRetrieve(t, f, items)
+
+	if err := f.offload(nil); err != nil {
+		t.Fatalf("Failed to offload: %v", err)
+	}
+	checkTier(t, f, 0, 1, 2, 3, 4, 5, 6, 7, 8)
+
+	// Truncate the tail, the dropped files must be removed from the tier.
+	if err := f.truncateTail(7); err != nil {
+		t.Fatal(err)
+	}
+	checkTier(t, f, 2, 3, 4, 5, 6, 7, 8)
+	checkRetrieveError(t, f, map[uint64]error{6: errOutOfBounds})
+	for i := uint64(0); i < 7; i++ {
+		delete(items, i)
+	}
+	checkRetrieve(t, f, items)
+}
+
+func TestTieredFreezer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir   = t.TempDir()
+		tier  = t.TempDir()
+		items [][]byte
+	)
+	for i := 0; i < 10; i++ {
+		items = append(items, getChunk(2048, i))
+	}
+	// Each item fills a data file of the freezer.
+	f, err := NewTieredFreezer(dir, tier, "", false, 2049, freezerTestTableDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		for i, item := range items {
+			if err := op.AppendRaw("test", uint64(i), item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.offload()
+	checkTier(t, f.tables["test"], 0, 1, 2, 3, 4, 5, 6, 7, 8)
+
+	check := func(f *Freezer, n int) {
+		t.Helper()
+
+		checkAncientCount(t, f, "test", uint64(n))
+		have, err := f.AncientRange("test", 0, uint64(n), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range have {
+			if !bytes.Equal(have[i], items[i]) {
+				t.Fatalf("Item %d mismatch", i)
+			}
+		}
+	}
+	check(f, 10)
+
+	if _, err := f.TruncateHead(5); err != nil {
+		t.Fatal(err)
+	}
+	check(f, 5)
+	checkTier(t, f.tables["test"], 0, 1, 2, 3)
+	f.Close()
+
+	// The freezer can't be opened without the tier holding its offloaded data.
+	if _, err := NewFreezer(dir, "", false, 2049, freezerTestTableDef); !errors.Is(err, errTierMissing) {
+		t.Fatalf("Wrong error opening freezer without tier: have %v, want %v", err, errTierMissing)
+	}
+	// Reopen the freezer read-only, the offloaded data must remain readable.
+	f, err = NewTieredFreezer(dir, tier, "", true, 2049, freezerTestTableDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	check(f, 5)
+	checkTier(t, f.tables["test"], 0, 1, 2, 3)
+}
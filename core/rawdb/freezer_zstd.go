@@ -0,0 +1,152 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// zstdDictID is the identifier of the freezer dictionaries, embedded in
+	// the compressed items.
+	zstdDictID = 1
+
+	// zstdDictSize is the maximum size of the dictionary of a table.
+	zstdDictSize = 32 * 1024
+
+	// zstdDictHashBytes is the minimum length of the matches the dictionary is
+	// built out of.
+	zstdDictHashBytes = 6
+
+	// zstdTrainItems is the number of items a table must hold before its
+	// dictionary is trained. Until then, items are compressed with snappy.
+	zstdTrainItems = 1024
+
+	// zstdTrainBytes is the maximum amount of sample data the dictionary is
+	// trained on.
+	zstdTrainBytes = 1024 * 1024
+)
+
+// zstdCodec compresses and decompresses the items of a table with zstd, using
+// the table dictionary. It is safe for concurrent use.
+type zstdCodec struct {
+	enc *zstd.Encoder
+	dec *zstd.Decoder
+}
+
+// newZstdCodec creates a codec for the given raw content dictionary.
+func newZstdCodec(dict []byte) (*zstdCodec, error) {
+	enc, err := zstd.NewWriter(nil,
+		zstd.WithEncoderDictRaw(zstdDictID, dict),
+		zstd.WithEncoderConcurrency(1),
+		zstd.WithEncoderCRC(false),
+	)
+	if err != nil {
+		return nil, err
+	}
+	dec, err := zstd.NewReader(nil, zstd.WithDecoderDictRaw(zstdDictID, dict))
+	if err != nil {
+		enc.Close()
+		return nil, err
+	}
+	return &zstdCodec{enc: enc, dec: dec}, nil
+}
+
+// compress zstd-compresses the data, reusing the given buffer.
+func (c *zstdCodec) compress(dst, data []byte) []byte {
+	return c.enc.EncodeAll(data, dst[:0])
+}
+
+// decompress zstd-decompresses the data.
+func (c *zstdCodec) decompress(data []byte) ([]byte, error) {
+	return c.dec.DecodeAll(data, nil)
+}
+
+// close releases the resources held by the codec.
+func (c *zstdCodec) close() {
+	c.enc.Close()
+	c.dec.Close()
+}
+
+// maybeEnableZstd trains the dictionary of a zstd table once it holds enough
+// items, switching the compression of the subsequently appended items from
+// snappy to zstd. The items already stored are not recompressed: they are left
+// untouched, and keep being decompressed with snappy.
+func (t *freezerTable) maybeEnableZstd() error {
+	if !t.config.zstd || t.config.noSnappy || t.readonly || t.zstd != nil {
+		return nil
+	}
+	head, hidden := t.items.Load(), t.itemHidden.Load()
+	if head-hidden < zstdTrainItems || head < t.zstdRetry {
+		return nil
+	}
+	samples, err := t.RetrieveItems(head-zstdTrainItems, zstdTrainItems, zstdTrainBytes)
+	if err != nil {
+		return err
+	}
+	raw, err := trainZstdDict(samples)
+	if err != nil {
+		// Keep using snappy, and retry on a fresh set of samples once enough
+		// new items are appended rather than retraining on every commit.
+		t.zstdRetry = head + zstdTrainItems
+		t.logger.Warn("Failed to train zstd dictionary", "retry", t.zstdRetry, "err", err)
+		return nil
+	}
+	codec, err := newZstdCodec(raw)
+	if err != nil {
+		return err
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if err := t.metadata.setZstd(head, raw); err != nil {
+		codec.close()
+		return err
+	}
+	t.zstd = codec
+	t.logger.Info("Enabled zstd compression", "from", head, "dict", len(raw))
+	return nil
+}
+
+// trainZstdDict builds a raw content dictionary out of the given samples. The
+// dictionary builder panics on samples it cannot find any repeated content in
+// (e.g. items too short to be matched), which is reported as an error instead.
+func trainZstdDict(samples [][]byte) (raw []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			raw, err = nil, fmt.Errorf("untrainable samples: %v", r)
+		}
+	}()
+	raw, err = dict.BuildRawDict(samples, dict.Options{MaxDictSize: zstdDictSize, HashBytes: zstdDictHashBytes})
+	if err == nil && len(raw) == 0 {
+		err = errors.New("empty dictionary")
+	}
+	return raw, err
+}
+
+// zstdCodec returns the zstd codec of the table along with the first item it
+// compresses, or nil if the table only uses snappy.
+func (t *freezerTable) zstdCodec() (*zstdCodec, uint64) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.zstd, t.metadata.zstdFrom
+}
@@ -0,0 +1,306 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// makeReceiptItems generates freezer receipt items resembling the mainnet ones:
+// token transfers of a small set of contracts.
+func makeReceiptItems(seed int64, n int) [][]byte {
+	var (
+		rng       = rand.New(rand.NewSource(seed))
+		contracts = make([]common.Address, 16)
+		topics    = make([]common.Hash, 8)
+		items     = make([][]byte, n)
+	)
+	for i := range contracts {
+		contracts[i] = common.Address{byte(i), 0xaa, 0xbb}
+	}
+	for i := range topics {
+		topics[i] = common.Hash{byte(i), 0xdd, 0xf2, 0x52, 0xad}
+	}
+	for i := range items {
+		var (
+			receipts []*types.ReceiptForStorage
+			gas      uint64
+		)
+		for j := 0; j < 1+rng.Intn(8); j++ {
+			gas += 21000 + uint64(rng.Intn(100000))
+			receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: gas}
+			for k := 0; k < rng.Intn(4); k++ {
+				data := make([]byte, 32)
+				rng.Read(data[24:])
+				receipt.Logs = append(receipt.Logs, &types.Log{
+					Address: contracts[rng.Intn(len(contracts))],
+					Topics:  []common.Hash{topics[rng.Intn(len(topics))], common.BytesToHash(data[24:])},
+					Data:    data,
+				})
+			}
+			receipts = append(receipts, (*types.ReceiptForStorage)(receipt))
+		}
+		items[i], _ = rlp.EncodeToBytes(receipts)
+	}
+	return items
+}
+
+// appendItems appends the items to the table, starting at the given number.
+func appendItems(t testing.TB, f *freezerTable, from uint64, items [][]byte) {
+	t.Helper()
+
+	batch := f.newBatch()
+	for i, item := range items {
+		if err := batch.AppendRaw(from+uint64(i), item); err != nil {
+			t.Fatalf("AppendRaw(%d, ...) returned error: %v", from+uint64(i), err)
+		}
+	}
+	if err := batch.commit(); err != nil {
+		t.Fatalf("Commit returned error: %v", err)
+	}
+}
+
+// checkItems ensures the table holds the items, starting at the given number.
+func checkItems(t *testing.T, f *freezerTable, from uint64, items [][]byte) {
+	t.Helper()
+
+	have, err := f.RetrieveItems(from, uint64(len(items)), 0)
+	if err != nil {
+		t.Fatalf("Failed to retrieve items: %v", err)
+	}
+	if len(have) != len(items) {
+		t.Fatalf("Wrong number of items: have %d, want %d", len(have), len(items))
+	}
+	for i := range items {
+		if !bytes.Equal(have[i], items[i]) {
+			t.Fatalf("Item %d mismatch", from+uint64(i))
+		}
+	}
+}
+
+func TestFreezerTableZstd(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir    = t.TempDir()
+		fname  = fmt.Sprintf("zstd-%d", rand.Uint64())
+		config = freezerTableConfig{prunable: true, zstd: true}
+		items  = makeReceiptItems(1, 2*zstdTrainItems)
+	)
+	f, err := newTable(dir, fname, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 1<<20, config, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Items are compressed with snappy until there's enough to train on.
+	appendItems(t, f, 0, items[:zstdTrainItems-1])
+	if f.zstd != nil {
+		t.Fatal("Zstd enabled too early")
+	}
+	// The batch crossing the threshold is still compressed with snappy.
+	appendItems(t, f, zstdTrainItems-1, items[zstdTrainItems-1:zstdTrainItems])
+	if f.zstd == nil {
+		t.Fatal("Zstd not enabled")
+	}
+	if from := f.metadata.zstdFrom; from != zstdTrainItems {
+		t.Fatalf("Unexpected first zstd item: have %d, want %d", from, zstdTrainItems)
+	}
+	appendItems(t, f, zstdTrainItems, items[zstdTrainItems:])
+	checkItems(t, f, 0, items)
+
+	// Truncating the head below the first zstd item moves it.
+	if err := f.truncateHead(zstdTrainItems - 10); err != nil {
+		t.Fatal(err)
+	}
+	if from := f.metadata.zstdFrom; from != zstdTrainItems-10 {
+		t.Fatalf("Unexpected first zstd item: have %d, want %d", from, zstdTrainItems-10)
+	}
+	appendItems(t, f, zstdTrainItems-10, items[zstdTrainItems-10:])
+	checkItems(t, f, 0, items)
+	f.Close()
+
+	// Reopen the table in both modes, the dictionary must be retained.
+	for _, readonly := range []bool{false, true} {
+		f, err = newTable(dir, fname, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 1<<20, config, readonly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.zstd == nil || f.metadata.zstdFrom != zstdTrainItems-10 {
+			t.Fatal("Zstd settings lost")
+		}
+		checkItems(t, f, 0, items)
+		f.Close()
+	}
+}
+
+func TestFreezerTableZstdExisting(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir   = t.TempDir()
+		fname = fmt.Sprintf("zstd-existing-%d", rand.Uint64())
+		items = makeReceiptItems(2, 3*zstdTrainItems)
+	)
+	// Fill a table using snappy only.
+	config := freezerTableConfig{prunable: true}
+	f, err := newTable(dir, fname, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 1<<20, config, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendItems(t, f, 0, items[:2*zstdTrainItems])
+	if f.zstd != nil {
+		t.Fatal("Zstd enabled on snappy table")
+	}
+	f.Close()
+
+	// Reopen it with zstd enabled, the new items must be compressed with it
+	// while the existing ones are kept as is and remain readable.
+	config.zstd = true
+	f, err = newTable(dir, fname, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 1<<20, config, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	appendItems(t, f, 2*zstdTrainItems, items[2*zstdTrainItems:2*zstdTrainItems+1])
+	if f.zstd == nil || f.metadata.zstdFrom != 2*zstdTrainItems+1 {
+		t.Fatal("Zstd not enabled on existing table")
+	}
+	appendItems(t, f, 2*zstdTrainItems+1, items[2*zstdTrainItems+1:])
+	checkItems(t, f, 0, items)
+}
+
+// Tests that a failed dictionary training is only retried once enough new
+// items are appended, and that the table metadata stays at v2 until then.
+func TestFreezerTableZstdRetry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir    = t.TempDir()
+		fname  = fmt.Sprintf("zstd-retry-%d", rand.Uint64())
+		config = freezerTableConfig{prunable: true, zstd: true}
+		short  = make([][]byte, zstdTrainItems)
+		items  = makeReceiptItems(5, zstdTrainItems)
+	)
+	for i := range short {
+		short[i] = []byte{byte(i)} // too short for the dictionary builder
+	}
+	f, err := newTable(dir, fname, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 1<<20, config, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	appendItems(t, f, 0, short)
+	if f.zstd != nil {
+		t.Fatal("Zstd enabled on untrainable items")
+	}
+	if f.zstdRetry != 2*zstdTrainItems {
+		t.Fatalf("Unexpected retry threshold: have %d, want %d", f.zstdRetry, 2*zstdTrainItems)
+	}
+	// The training must not be retried before the threshold is reached.
+	appendItems(t, f, zstdTrainItems, items[:zstdTrainItems-1])
+	if f.zstd != nil || f.zstdRetry != 2*zstdTrainItems {
+		t.Fatal("Dictionary training retried too early")
+	}
+	meta, err := newMetadata(f.metadata.file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.version != freezerTableV2 {
+		t.Fatalf("Unexpected metadata version: have %d, want %d", meta.version, freezerTableV2)
+	}
+	// Reaching the threshold retries the training on the new items.
+	appendItems(t, f, 2*zstdTrainItems-1, items[zstdTrainItems-1:])
+	if f.zstd == nil || f.metadata.zstdFrom != 2*zstdTrainItems {
+		t.Fatal("Zstd not enabled on retry")
+	}
+	if meta, err = newMetadata(f.metadata.file); err != nil {
+		t.Fatal(err)
+	}
+	if meta.version != freezerTableV3 {
+		t.Fatalf("Unexpected metadata version: have %d, want %d", meta.version, freezerTableV3)
+	}
+	checkItems(t, f, 0, short)
+	checkItems(t, f, zstdTrainItems, items)
+}
+
+// newBenchTable creates a table with enough receipt items for the zstd
+// dictionary to be trained, if enabled.
+func newBenchTable(b *testing.B, zstd bool) *freezerTable {
+	config := freezerTableConfig{prunable: true, zstd: zstd}
+	f, err := newTable(b.TempDir(), "bench", metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), freezerTableSize, config, false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	appendItems(b, f, 0, makeReceiptItems(3, zstdTrainItems))
+	return f
+}
+
+// BenchmarkFreezerCompressionWrite compares the compression ratio and speed of
+// snappy and zstd on receipt-like items.
+func BenchmarkFreezerCompressionWrite(b *testing.B) {
+	for _, name := range []string{"snappy", "zstd"} {
+		b.Run(name, func(b *testing.B) {
+			f := newBenchTable(b, name == "zstd")
+			defer f.Close()
+
+			var (
+				items  = makeReceiptItems(4, b.N)
+				raw    int
+				before uint64
+			)
+			for _, item := range items {
+				raw += len(item)
+			}
+			before, _ = f.size()
+			b.ResetTimer()
+			appendItems(b, f, zstdTrainItems, items)
+			b.StopTimer()
+
+			after, _ := f.size()
+			b.ReportMetric(float64(after-before)/float64(b.N), "disk-bytes/op")
+			b.ReportMetric(float64(raw)/float64(after-before), "ratio")
+		})
+	}
+}
+
+// BenchmarkFreezerCompressionRead compares the decompression speed of snappy
+// and zstd on receipt-like items.
+func BenchmarkFreezerCompressionRead(b *testing.B) {
+	for _, name := range []string{"snappy", "zstd"} {
+		b.Run(name, func(b *testing.B) {
+			f := newBenchTable(b, name == "zstd")
+			defer f.Close()
+			appendItems(b, f, zstdTrainItems, makeReceiptItems(4, 4096))
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := f.Retrieve(zstdTrainItems + uint64(i%4096)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
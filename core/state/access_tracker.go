@@ -0,0 +1,382 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+// AccessSet is a set of state locations, namely accounts and storage slots.
+type AccessSet struct {
+	accounts map[common.Address]struct{}
+	slots    map[common.Address]map[common.Hash]struct{}
+}
+
+// NewAccessSet creates an empty access set.
+func NewAccessSet() *AccessSet {
+	return &AccessSet{
+		accounts: make(map[common.Address]struct{}),
+		slots:    make(map[common.Address]map[common.Hash]struct{}),
+	}
+}
+
+// AddAccount inserts an account into the set.
+func (set *AccessSet) AddAccount(addr common.Address) {
+	set.accounts[addr] = struct{}{}
+}
+
+// AddSlot inserts a storage slot into the set.
+func (set *AccessSet) AddSlot(addr common.Address, slot common.Hash) {
+	slots, ok := set.slots[addr]
+	if !ok {
+		slots = make(map[common.Hash]struct{})
+		set.slots[addr] = slots
+	}
+	slots[slot] = struct{}{}
+}
+
+// Len returns the number of accounts and storage slots in the set.
+func (set *AccessSet) Len() int {
+	n := len(set.accounts)
+	for _, slots := range set.slots {
+		n += len(slots)
+	}
+	return n
+}
+
+// Merge inserts all the locations of the given set into this one.
+func (set *AccessSet) Merge(other *AccessSet) {
+	for addr := range other.accounts {
+		set.AddAccount(addr)
+	}
+	for addr, slots := range other.slots {
+		for slot := range slots {
+			set.AddSlot(addr, slot)
+		}
+	}
+}
+
+// Accounts returns the accounts contained in the set, excluding the ones only
+// present through their storage slots.
+func (set *AccessSet) Accounts() []common.Address {
+	return slices.Collect(maps.Keys(set.accounts))
+}
+
+// Slots returns the storage slots of the given account contained in the set.
+func (set *AccessSet) Slots(addr common.Address) []common.Hash {
+	return slices.Collect(maps.Keys(set.slots[addr]))
+}
+
+// Compare counts the locations of the set which are also contained in the other
+// one, the locations which are only in this set and the ones only in the other.
+func (set *AccessSet) Compare(other *AccessSet) (common, only, missing int) {
+	for addr := range set.accounts {
+		if _, ok := other.accounts[addr]; ok {
+			common++
+		} else {
+			only++
+		}
+	}
+	for addr, slots := range set.slots {
+		for slot := range slots {
+			if _, ok := other.slots[addr][slot]; ok {
+				common++
+			} else {
+				only++
+			}
+		}
+	}
+	for addr := range other.accounts {
+		if _, ok := set.accounts[addr]; !ok {
+			missing++
+		}
+	}
+	for addr, slots := range other.slots {
+		for slot := range slots {
+			if _, ok := set.slots[addr][slot]; !ok {
+				missing++
+			}
+		}
+	}
+	return common, only, missing
+}
+
+// Intersects reports whether any location is contained in both sets.
+func (set *AccessSet) Intersects(other *AccessSet) bool {
+	for addr := range other.accounts {
+		if _, ok := set.accounts[addr]; ok {
+			return true
+		}
+	}
+	for addr, slots := range other.slots {
+		mine, ok := set.slots[addr]
+		if !ok {
+			continue
+		}
+		for slot := range slots {
+			if _, ok := mine[slot]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// accessTracker records the state read during execution. For transactions
+// executed speculatively, it also accumulates the fees credited to the block's
+// fee recipient. The fees are kept out of the state, otherwise every transaction
+// would conflict on the fee recipient with all its predecessors.
+type accessTracker struct {
+	reads *AccessSet
+
+	deferFees bool           // Whether to keep the fees out of the state
+	coinbase  common.Address // Fee recipient of the block
+	fee       *uint256.Int   // Fees credited to the coinbase, nil if none
+}
+
+// accountWrite is the final value of the fields of an account modified by a
+// transaction. Nil fields are left untouched.
+type accountWrite struct {
+	created    bool // Account was created by the transaction
+	contract   bool // Account was turned into a contract by the transaction
+	destructed bool // Account was self-destructed by the transaction
+	touched    bool // Account was marked dirty, potentially deleting it if empty
+
+	balance *uint256.Int
+	nonce   *uint64
+	code    []byte
+	codeSet bool
+	storage map[common.Hash]common.Hash
+}
+
+// TxWrites is the effect of a single transaction on the state, which can be
+// replayed on top of a different state containing the same values for all the
+// locations read by the transaction.
+type TxWrites struct {
+	accounts  map[common.Address]*accountWrite
+	logs      []*types.Log
+	preimages map[common.Hash][]byte
+
+	coinbase common.Address
+	fee      *uint256.Int // Deferred fees credited to the coinbase, nil if none
+}
+
+// Keys returns the set of state locations modified by the transaction.
+func (w *TxWrites) Keys() *AccessSet {
+	set := NewAccessSet()
+	for addr, acc := range w.accounts {
+		if acc.created || acc.contract || acc.destructed || acc.touched || acc.balance != nil || acc.nonce != nil || acc.codeSet {
+			set.AddAccount(addr)
+		}
+		for slot := range acc.storage {
+			set.AddSlot(addr, slot)
+		}
+	}
+	if w.fee != nil {
+		set.AddAccount(w.coinbase)
+	}
+	return set
+}
+
+// TrackAccess starts recording the state locations read by the next transaction,
+// deferring the transaction fees credited to the coinbase instead of applying
+// them to the state. It is meant to be used on a dedicated copy of the state
+// for executing a single transaction speculatively.
+func (s *StateDB) TrackAccess(coinbase common.Address) {
+	s.access = &accessTracker{
+		reads:     NewAccessSet(),
+		deferFees: true,
+		coinbase:  coinbase,
+	}
+}
+
+// TrackReads starts recording the state locations read, without altering the
+// execution in any way. It's a noop if the reads are already tracked.
+func (s *StateDB) TrackReads() {
+	if s.access != nil && !s.access.deferFees {
+		return
+	}
+	s.access = &accessTracker{reads: NewAccessSet()}
+}
+
+// Reads returns the state locations read since the tracking started, or nil
+// if the access tracking is not enabled.
+func (s *StateDB) Reads() *AccessSet {
+	if s.access == nil {
+		return nil
+	}
+	return s.access.reads
+}
+
+// trackAccount records an account read if the access tracking is enabled.
+func (s *StateDB) trackAccount(addr common.Address) {
+	if s.access != nil {
+		s.access.reads.AddAccount(addr)
+	}
+}
+
+// trackSlot records a storage slot read if the access tracking is enabled.
+func (s *StateDB) trackSlot(addr common.Address, slot common.Hash) {
+	if s.access != nil {
+		s.access.reads.AddSlot(addr, slot)
+	}
+}
+
+// deferFee reports whether the balance increase is a transaction fee owed to the
+// tracked coinbase, accumulating it instead of applying it if so.
+func (s *StateDB) deferFee(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) bool {
+	if s.access == nil || !s.access.deferFees || reason != tracing.BalanceIncreaseRewardTransactionFee || addr != s.access.coinbase {
+		return false
+	}
+	if s.access.fee == nil {
+		s.access.fee = new(uint256.Int)
+	}
+	s.access.fee.Add(s.access.fee, amount)
+	return true
+}
+
+// Writes collects the modifications made by the current transaction from the
+// journal. It must be called before the transaction is finalised.
+func (s *StateDB) Writes() *TxWrites {
+	w := &TxWrites{
+		accounts:  make(map[common.Address]*accountWrite),
+		logs:      s.logs[s.thash],
+		preimages: s.preimages,
+	}
+	if s.access != nil && s.access.deferFees {
+		w.coinbase, w.fee = s.access.coinbase, s.access.fee
+	}
+	account := func(addr common.Address) *accountWrite {
+		acc, ok := w.accounts[addr]
+		if !ok {
+			acc = new(accountWrite)
+			w.accounts[addr] = acc
+		}
+		return acc
+	}
+	for _, entry := range s.journal.entries {
+		switch ch := entry.(type) {
+		case createObjectChange:
+			account(ch.account).created = true
+		case createContractChange:
+			account(ch.account).contract = true
+		case selfDestructChange:
+			account(ch.account).destructed = true
+		case balanceChange:
+			account(ch.account).balance = new(uint256.Int)
+		case nonceChange:
+			account(ch.account).nonce = new(uint64)
+		case codeChange:
+			account(ch.account).codeSet = true
+		case storageChange:
+			acc := account(ch.account)
+			if acc.storage == nil {
+				acc.storage = make(map[common.Hash]common.Hash)
+			}
+			acc.storage[ch.key] = common.Hash{}
+		case touchChange:
+			account(ch.account).touched = true
+		}
+	}
+	// Accounts may be dirty without any journal entry left, e.g. the ripemd
+	// precompile touched in a reverted scope.
+	for addr := range s.journal.dirties {
+		if _, ok := w.accounts[addr]; !ok {
+			account(addr).touched = true
+		}
+	}
+	// Resolve the final values of all the modified fields
+	for addr, acc := range w.accounts {
+		obj := s.stateObjects[addr]
+		if obj == nil {
+			continue
+		}
+		if acc.balance != nil {
+			acc.balance.Set(obj.Balance())
+		}
+		if acc.nonce != nil {
+			*acc.nonce = obj.Nonce()
+		}
+		if acc.codeSet {
+			acc.code = obj.Code()
+		}
+		for slot := range acc.storage {
+			acc.storage[slot] = obj.GetState(slot)
+		}
+	}
+	return w
+}
+
+// ApplyWrites replays the modifications made by a transaction executed on a
+// different state. The transaction context must be set beforehand, and the
+// transaction must be finalised afterwards as if it was executed on this state.
+//
+// The writes only record the final values, not the reasons of the changes, so
+// balance and nonce updates are replayed as unspecified ones. This is only sound
+// because ParallelStateProcessor.Process falls back to the sequential execution
+// whenever a tracer is set: the replayed changes must never reach a tracer.
+func (s *StateDB) ApplyWrites(w *TxWrites) {
+	for _, addr := range slices.SortedFunc(maps.Keys(w.accounts), common.Address.Cmp) {
+		acc := w.accounts[addr]
+		if acc.created {
+			s.CreateAccount(addr)
+		}
+		if acc.contract {
+			s.CreateContract(addr)
+		}
+		if acc.balance != nil {
+			s.SetBalance(addr, acc.balance, tracing.BalanceChangeUnspecified)
+		}
+		if acc.nonce != nil {
+			s.SetNonce(addr, *acc.nonce, tracing.NonceChangeUnspecified)
+		}
+		if acc.codeSet {
+			s.SetCode(addr, acc.code)
+		}
+		for slot, value := range acc.storage {
+			s.SetState(addr, slot, value)
+		}
+		if acc.destructed {
+			s.SelfDestruct(addr)
+		}
+		if acc.touched {
+			// Load the account so that it's deleted at finalisation if empty,
+			// without creating it if it doesn't exist.
+			s.getStateObject(addr)
+			s.journal.dirty(addr)
+		}
+	}
+	if w.fee != nil {
+		s.AddBalance(w.coinbase, w.fee, tracing.BalanceIncreaseRewardTransactionFee)
+	}
+	for _, l := range w.logs {
+		s.AddLog(&types.Log{
+			Address: l.Address,
+			Topics:  l.Topics,
+			Data:    l.Data,
+		})
+	}
+	for hash, preimage := range w.preimages {
+		s.AddPreimage(hash, preimage)
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pruner
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// errPruningAborted is returned if the pruning is stopped before completion.
+var errPruningAborted = errors.New("pruning aborted")
+
+// headPollInterval is the time between two checks of the chain progression
+// while the online pruner waits for the pre-pruning states to be released.
+var headPollInterval = 3 * time.Second
+
+// OnlineConfig includes all the configurations for pruning a live database.
+type OnlineConfig struct {
+	BloomSize  uint64        // The Megabytes of memory allocated to bloom-filter
+	BatchSize  int           // Bytes of stale trie nodes deleted in a single batch
+	BatchDelay time.Duration // Pause between two deletion batches to throttle disk load
+}
+
+// OnlineDefaults contains the default settings for online pruning.
+var OnlineDefaults = OnlineConfig{
+	BloomSize:  2048,
+	BatchSize:  ethdb.IdealBatchSize,
+	BatchDelay: 50 * time.Millisecond,
+}
+
+// ChainReader defines the small collection of methods needed to access the
+// live chain during online pruning.
+type ChainReader interface {
+	// CurrentBlock retrieves the current head header of the canonical chain.
+	CurrentBlock() *types.Header
+
+	// TrieDB retrieves the trie database the chain commits its state into.
+	TrieDB() *triedb.Database
+}
+
+// OnlinePruner deletes the stale state of a hash-scheme database while the
+// node keeps running. The workflow is:
+//
+//   - start tracking all the trie nodes persisted by the trie database, then
+//     flush the head state to disk and mark all the nodes reachable from it
+//     and from the genesis state
+//   - wait until the chain progressed past all the states which predate the
+//     head state, as their nodes were not marked
+//   - iterate the database, deleting in throttled batches all the trie nodes
+//     which were neither marked nor persisted since the tracking started
+//
+// Every state created after the marked head is made of marked nodes and of new
+// nodes, the latter being either still in memory or tracked when persisted.
+// The deletions are exclusive with commits of the trie database, so a node that
+// is written back concurrently is never lost.
+//
+// Contrary to the offline pruner, the reachable nodes are collected from the
+// trie instead of being regenerated from the snapshot, since the snapshot diff
+// layers go stale whenever the chain progresses. Contract codes are not pruned,
+// as they are written outside of the trie database and cannot be tracked.
+type OnlinePruner struct {
+	config OnlineConfig
+	db     ethdb.Database
+	chain  ChainReader
+	bloom  *syncBloom
+
+	abort    chan struct{}
+	stopOnce sync.Once
+}
+
+// NewOnlinePruner creates an online pruner for the given live chain.
+func NewOnlinePruner(db ethdb.Database, chain ChainReader, config OnlineConfig) (*OnlinePruner, error) {
+	if scheme := chain.TrieDB().Scheme(); scheme != rawdb.HashScheme {
+		return nil, fmt.Errorf("online pruning is not supported by the %s scheme", scheme)
+	}
+	// Sanitize the bloom filter size if it's too small.
+	if config.BloomSize < 256 {
+		log.Warn("Sanitizing bloomfilter size", "provided(MB)", config.BloomSize, "updated(MB)", 256)
+		config.BloomSize = 256
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = ethdb.IdealBatchSize
+	}
+	bloom, err := newStateBloomWithSize(config.BloomSize)
+	if err != nil {
+		return nil, err
+	}
+	return &OnlinePruner{
+		config: config,
+		db:     db,
+		chain:  chain,
+		bloom:  &syncBloom{bloom: bloom},
+		abort:  make(chan struct{}),
+	}, nil
+}
+
+// Stop interrupts a running pruning. The stale state deleted up to this point
+// is not restored, which is harmless.
+func (p *OnlinePruner) Stop() {
+	p.stopOnce.Do(func() { close(p.abort) })
+}
+
+// Prune runs the online pruning, blocking until it either finishes, fails or
+// gets stopped.
+func (p *OnlinePruner) Prune() error {
+	var (
+		start = time.Now()
+		tdb   = p.chain.TrieDB()
+	)
+	// Track all the nodes persisted from now on: they may belong to states
+	// newer than the marked one and must survive the pruning.
+	if err := tdb.TrackWrites(func(hash common.Hash) { p.bloom.Put(hash.Bytes(), nil) }); err != nil {
+		return err
+	}
+	defer tdb.TrackWrites(nil)
+
+	// Flush the head state to disk, so that any of its nodes flushed before the
+	// tracking started are reachable from a marked root.
+	head := p.chain.CurrentBlock()
+	if err := tdb.Commit(head.Root, false); err != nil {
+		return err
+	}
+	log.Info("Marking live state for pruning", "number", head.Number, "root", head.Root)
+	if err := markState(tdb, head.Root, p.bloom, p.abort); err != nil {
+		return err
+	}
+	if err := extractGenesis(p.db, p.bloom); err != nil {
+		return err
+	}
+	log.Info("Marked live state for pruning", "elapsed", common.PrettyDuration(time.Since(start)))
+
+	// The states preceding the marked one may still be used, e.g. by a reorg,
+	// wait until they are released from memory before deleting anything.
+	if err := p.waitHead(head.Number.Uint64() + state.TriesInMemory); err != nil {
+		return err
+	}
+	count, err := p.sweep(tdb)
+	if err != nil {
+		return err
+	}
+	// Start compactions, will remove the deleted data from the disk immediately.
+	// Note for small pruning, the compaction is skipped.
+	if count >= rangeCompactionThreshold {
+		for b := 0x00; b <= 0xf0; b += 0x10 {
+			var (
+				start = []byte{byte(b)}
+				end   = []byte{byte(b + 0x10)}
+			)
+			if b == 0xf0 {
+				end = nil
+			}
+			select {
+			case <-p.abort:
+				return errPruningAborted
+			default:
+			}
+			log.Info("Compacting database", "range", fmt.Sprintf("%#x-%#x", start, end))
+			if err := p.db.Compact(start, end); err != nil {
+				log.Error("Database compaction failed", "error", err)
+				return err
+			}
+		}
+	}
+	log.Info("Online state pruning successful", "nodes", count, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// waitHead blocks until the chain head reaches the given number.
+func (p *OnlinePruner) waitHead(number uint64) error {
+	logged := time.Now()
+	for {
+		head := p.chain.CurrentBlock()
+		if head.Number.Uint64() >= number {
+			return nil
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Waiting for older states to be released", "number", head.Number, "target", number)
+			logged = time.Now()
+		}
+		select {
+		case <-p.abort:
+			return errPruningAborted
+		case <-time.After(headPollInterval):
+		}
+	}
+}
+
+// sweep iterates the database and deletes all the trie nodes which are neither
+// marked nor tracked, returning the number of deleted nodes.
+func (p *OnlinePruner) sweep(tdb *triedb.Database) (int, error) {
+	var (
+		count, skipped int
+		size           int
+		hashes         []common.Hash
+		pstart         = time.Now()
+		logged         = time.Now()
+		iter           = p.db.NewIterator(nil, nil)
+	)
+	defer func() { iter.Release() }()
+
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != common.HashLength {
+			continue
+		}
+		if p.bloom.Contain(key) {
+			skipped += 1
+			continue
+		}
+		hashes = append(hashes, common.BytesToHash(key))
+		size += len(key) + len(iter.Value())
+
+		if size >= p.config.BatchSize {
+			// Recreate the iterator after every batch deletion in order
+			// to allow the underlying compactor to delete the entries.
+			next := common.CopyBytes(key)
+			iter.Release()
+
+			deleted, err := tdb.DeleteNodes(hashes, p.bloom.containHash)
+			iter = p.db.NewIterator(nil, next)
+			if err != nil {
+				return count, err
+			}
+			count += deleted
+			skipped += len(hashes) - deleted
+			hashes, size = hashes[:0], 0
+
+			if time.Since(logged) > 8*time.Second {
+				log.Info("Pruning state data", "nodes", count, "skipped", skipped, "elapsed", common.PrettyDuration(time.Since(pstart)))
+				logged = time.Now()
+			}
+			select {
+			case <-p.abort:
+				return count, errPruningAborted
+			case <-time.After(p.config.BatchDelay):
+			}
+		}
+	}
+	if len(hashes) > 0 {
+		deleted, err := tdb.DeleteNodes(hashes, p.bloom.containHash)
+		if err != nil {
+			return count, err
+		}
+		count += deleted
+		skipped += len(hashes) - deleted
+	}
+	log.Info("Pruned state data", "nodes", count, "skipped", skipped, "elapsed", common.PrettyDuration(time.Since(pstart)))
+	return count, iter.Error()
+}
+
+// syncBloom is a state bloom which is safe for concurrent use, allowing the
+// trie database to record the nodes it persists while pruning is in progress.
+type syncBloom struct {
+	bloom *stateBloom
+	lock  sync.Mutex
+}
+
+// Put implements the KeyValueWriter interface. But here only the key is needed.
+func (b *syncBloom) Put(key []byte, value []byte) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.bloom.Put(key, value)
+}
+
+// Delete removes the key from the key-value data store.
+func (b *syncBloom) Delete(key []byte) error { panic("not supported") }
+
+// Contain reports whether the key may be contained in the bloom.
+func (b *syncBloom) Contain(key []byte) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.bloom.Contain(key)
+}
+
+// containHash reports whether the trie node with the given hash may be contained
+// in the bloom.
+func (b *syncBloom) containHash(hash common.Hash) bool {
+	return b.Contain(hash.Bytes())
+}
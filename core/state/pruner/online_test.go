@@ -0,0 +1,123 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pruner
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+)
+
+// testChain is a fake live chain, progressing once after the pruner retrieved
+// the head to mark.
+type testChain struct {
+	triedb *triedb.Database
+	head   *types.Header
+	next   func() *types.Header
+	calls  int
+}
+
+func (c *testChain) CurrentBlock() *types.Header {
+	c.calls++
+	if c.calls > 1 && c.next != nil {
+		c.head, c.next = c.next(), nil
+	}
+	return c.head
+}
+
+func (c *testChain) TrieDB() *triedb.Database { return c.triedb }
+
+// Tests that online pruning deletes the stale state, but keeps the genesis, the
+// marked head and the states committed while the pruning is running, even if
+// they resurrect nodes of the stale state.
+func TestOnlinePruning(t *testing.T) {
+	defer func(interval time.Duration) { headPollInterval = interval }(headPollInterval)
+	headPollInterval = 10 * time.Millisecond
+
+	var (
+		db       = rawdb.NewMemoryDatabase()
+		tdb      = triedb.NewDatabase(db, triedb.HashDefaults)
+		sdb      = state.NewDatabase(tdb, nil)
+		account  = common.HexToAddress("0x1")
+		contract = common.HexToAddress("0x2")
+	)
+	commit := func(parent common.Hash, balance uint64, slot byte, flush bool) common.Hash {
+		statedb, err := state.New(parent, sdb)
+		if err != nil {
+			t.Fatalf("Failed to open state %x: %v", parent, err)
+		}
+		statedb.SetBalance(account, uint256.NewInt(balance), tracing.BalanceChangeUnspecified)
+		statedb.SetCode(contract, []byte{0x60, 0x00})
+		statedb.SetState(contract, common.Hash{}, common.Hash{slot})
+		root, err := statedb.Commit(0, false, false)
+		if err != nil {
+			t.Fatalf("Failed to commit state: %v", err)
+		}
+		if flush {
+			if err := tdb.Commit(root, false); err != nil {
+				t.Fatalf("Failed to flush state: %v", err)
+			}
+		}
+		return root
+	}
+	genesis := commit(types.EmptyRootHash, 1, 1, true)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Root: genesis})
+	rawdb.WriteBlock(db, block)
+	rawdb.WriteCanonicalHash(db, block.Hash(), 0)
+
+	var (
+		stale = commit(genesis, 2, 2, true)
+		head  = commit(stale, 3, 3, false)
+		next  common.Hash
+	)
+	chain := &testChain{
+		triedb: tdb,
+		head:   &types.Header{Number: big.NewInt(1), Root: head},
+		next: func() *types.Header {
+			// Restore the storage of the stale state while pruning
+			next = commit(head, 4, 2, true)
+			return &types.Header{Number: big.NewInt(1 + state.TriesInMemory), Root: next}
+		},
+	}
+	pruner, err := NewOnlinePruner(db, chain, OnlineDefaults)
+	if err != nil {
+		t.Fatalf("Failed to create pruner: %v", err)
+	}
+	if err := pruner.Prune(); err != nil {
+		t.Fatalf("Failed to prune state: %v", err)
+	}
+	if rawdb.HasLegacyTrieNode(db, stale) {
+		t.Error("Stale state root was not pruned")
+	}
+	for _, root := range []common.Hash{genesis, head, next} {
+		bloom, err := newStateBloomWithSize(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := markState(tdb, root, bloom, nil); err != nil {
+			t.Errorf("State %x is not complete after pruning: %v", root, err)
+		}
+	}
+}
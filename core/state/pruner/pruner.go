@@ -400,7 +400,7 @@ func RecoverPruning(datadir string, db ethdb.Database) error {
 
 // extractGenesis loads the genesis state and commits all the state entries
 // into the given bloomfilter.
-func extractGenesis(db ethdb.Database, stateBloom *stateBloom) error {
+func extractGenesis(db ethdb.Database, stateBloom ethdb.KeyValueWriter) error {
 	genesisHash := rawdb.ReadCanonicalHash(db, 0)
 	if genesisHash == (common.Hash{}) {
 		return errors.New("missing genesis hash")
@@ -409,7 +409,14 @@ func extractGenesis(db ethdb.Database, stateBloom *stateBloom) error {
 	if genesis == nil {
 		return errors.New("missing genesis block")
 	}
-	t, err := trie.NewStateTrie(trie.StateTrieID(genesis.Root()), triedb.NewDatabase(db, triedb.HashDefaults))
+	return markState(triedb.NewDatabase(db, triedb.HashDefaults), genesis.Root(), stateBloom, nil)
+}
+
+// markState iterates all the trie nodes and contract codes belonging to the
+// given state and commits their keys into the given bloomfilter. The iteration
+// can be interrupted by closing the abort channel.
+func markState(db *triedb.Database, root common.Hash, stateBloom ethdb.KeyValueWriter, abort chan struct{}) error {
+	t, err := trie.NewStateTrie(trie.StateTrieID(root), db)
 	if err != nil {
 		return err
 	}
@@ -427,13 +434,18 @@ func extractGenesis(db ethdb.Database, stateBloom *stateBloom) error {
 		// If it's a leaf node, yes we are touching an account,
 		// dig into the storage trie further.
 		if accIter.Leaf() {
+			select {
+			case <-abort:
+				return errPruningAborted
+			default:
+			}
 			var acc types.StateAccount
 			if err := rlp.DecodeBytes(accIter.LeafBlob(), &acc); err != nil {
 				return err
 			}
 			if acc.Root != types.EmptyRootHash {
-				id := trie.StorageTrieID(genesis.Root(), common.BytesToHash(accIter.LeafKey()), acc.Root)
-				storageTrie, err := trie.NewStateTrie(id, triedb.NewDatabase(db, triedb.HashDefaults))
+				id := trie.StorageTrieID(root, common.BytesToHash(accIter.LeafKey()), acc.Root)
+				storageTrie, err := trie.NewStateTrie(id, db)
 				if err != nil {
 					return err
 				}
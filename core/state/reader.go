@@ -212,10 +212,13 @@ func newTrieReader(root common.Hash, db *triedb.Database, cache *utils.PointCach
 		tr  Trie
 		err error
 	)
-	if !db.IsVerkle() {
-		tr, err = trie.NewStateTrie(trie.StateTrieID(root), db)
-	} else {
+	switch {
+	case db.IsBinary():
+		tr, err = trie.NewBinaryTrie(root, db)
+	case db.IsVerkle():
 		tr, err = trie.NewVerkleTrie(root, db, cache)
+	default:
+		tr, err = trie.NewStateTrie(trie.StateTrieID(root), db)
 	}
 	if err != nil {
 		return nil, err
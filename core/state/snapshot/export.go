@@ -0,0 +1,635 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/golang/snappy"
+)
+
+// The snapshot export format is a sequence of chunks, each made of the length
+// of its payload, the CRC32-C checksum of the payload and the payload itself,
+// which is a snappy compressed RLP list. The first chunk holds the export
+// header, along with the genesis hash of the network and the block the state
+// belongs to, the following ones lists of records, and an empty chunk ends the
+// stream.
+//
+// The headers of the chain between the genesis and the exported block come
+// first, ordered by number. The state records follow, ordered by account hash.
+// Every account is followed by its storage slots ordered by slot hash, and the
+// code of a contract is placed right before the first account using it.
+const (
+	exportVersion   = 3                // Version of the snapshot export format
+	exportChunkSize = 1024 * 1024      // Uncompressed size after which a chunk is flushed
+	exportMaxChunk  = 16 * 1024 * 1024 // Maximum compressed size of a chunk accepted on import
+	importHeaders   = 2048             // Number of headers written into the ancient store at once
+)
+
+var (
+	// exportMagic is the identifier prefixed to all snapshot export files.
+	exportMagic = []byte("gethsnap")
+
+	// exportChecksum is the checksum table used for the chunks.
+	exportChecksum = crc32.MakeTable(crc32.Castagnoli)
+
+	// errExportTruncated is returned if an export ends before its last chunk.
+	errExportTruncated = errors.New("truncated snapshot export")
+)
+
+// Kinds of the records stored in a snapshot export.
+const (
+	exportAccount     byte = iota // Account in slim RLP format
+	exportStorage                 // Storage slot of the last account
+	exportCode                    // Contract code of the next account
+	exportChainHeader             // Header of a block preceding the exported one
+)
+
+// exportHeader is the content of the first chunk of a snapshot export.
+type exportHeader struct {
+	Version  uint64
+	Root     common.Hash
+	Genesis  common.Hash  // Hash of the genesis block of the network
+	Block    rlp.RawValue // Block whose post-state is exported
+	Receipts rlp.RawValue // Receipts of the exported block, in storage encoding
+}
+
+// exportRecord is a single state entry in a snapshot export.
+type exportRecord struct {
+	Kind byte
+	Hash common.Hash
+	Data []byte
+}
+
+// exportWriter batches records into chunks and writes them out.
+type exportWriter struct {
+	w       io.Writer
+	records []exportRecord
+	size    int
+}
+
+// writeChunk compresses and writes a single chunk with the given payload.
+func (ew *exportWriter) writeChunk(payload []byte) error {
+	var (
+		data   = snappy.Encode(nil, payload)
+		prefix [8]byte
+	)
+	if len(payload) == 0 {
+		data = nil
+	}
+	binary.BigEndian.PutUint32(prefix[:4], uint32(len(data)))
+	binary.BigEndian.PutUint32(prefix[4:], crc32.Checksum(data, exportChecksum))
+	if _, err := ew.w.Write(prefix[:]); err != nil {
+		return err
+	}
+	_, err := ew.w.Write(data)
+	return err
+}
+
+// add appends a record to the pending chunk, flushing it if it's large enough.
+func (ew *exportWriter) add(kind byte, hash common.Hash, data []byte) error {
+	ew.records = append(ew.records, exportRecord{Kind: kind, Hash: hash, Data: data})
+	ew.size += common.HashLength + len(data)
+	if ew.size >= exportChunkSize {
+		return ew.flush()
+	}
+	return nil
+}
+
+// flush writes out the pending records as a chunk.
+func (ew *exportWriter) flush() error {
+	if len(ew.records) == 0 {
+		return nil
+	}
+	payload, err := rlp.EncodeToBytes(ew.records)
+	if err != nil {
+		return err
+	}
+	ew.records, ew.size = ew.records[:0], 0
+	return ew.writeChunk(payload)
+}
+
+// Export streams the flat post-state of the given block into w, in a format
+// which can be restored with Import. The canonical headers preceding the block,
+// its receipts and the contract codes are read from the given database.
+func Export(w io.Writer, snaptree *Tree, block *types.Block, db ethdb.Reader) error {
+	genesis := rawdb.ReadCanonicalHash(db, 0)
+	if genesis == (common.Hash{}) {
+		return errors.New("missing genesis block")
+	}
+	receipts := rawdb.ReadReceiptsRLP(db, block.Hash(), block.NumberU64())
+	if receipts == nil {
+		return fmt.Errorf("missing receipts of block %d", block.NumberU64())
+	}
+	root := block.Root()
+	acctIt, err := snaptree.AccountIterator(root, common.Hash{})
+	if err != nil {
+		return err
+	}
+	defer acctIt.Release()
+
+	blob, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return err
+	}
+	ew := &exportWriter{w: w}
+	if _, err := w.Write(exportMagic); err != nil {
+		return err
+	}
+	header, err := rlp.EncodeToBytes(&exportHeader{Version: exportVersion, Root: root, Genesis: genesis, Block: blob, Receipts: receipts})
+	if err != nil {
+		return err
+	}
+	if err := ew.writeChunk(header); err != nil {
+		return err
+	}
+	// Export the header chain linking the block to the genesis
+	parent := genesis
+	for number := uint64(1); number < block.NumberU64(); number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		header := rawdb.ReadHeader(db, hash, number)
+		if header == nil {
+			return fmt.Errorf("missing header %d", number)
+		}
+		if header.ParentHash != parent {
+			return fmt.Errorf("header %d not linked to its parent", number)
+		}
+		blob, err := rlp.EncodeToBytes(header)
+		if err != nil {
+			return err
+		}
+		if err := ew.add(exportChainHeader, hash, blob); err != nil {
+			return err
+		}
+		parent = hash
+	}
+	if block.NumberU64() > 0 && block.ParentHash() != parent {
+		return fmt.Errorf("block %d not canonical", block.NumberU64())
+	}
+	var (
+		start  = time.Now()
+		logged = time.Now()
+		codes  = make(map[common.Hash]struct{})
+
+		accounts, slots uint64
+	)
+	for acctIt.Next() {
+		account, err := types.FullAccount(acctIt.Account())
+		if err != nil {
+			return err
+		}
+		codeHash := common.BytesToHash(account.CodeHash)
+		if codeHash != types.EmptyCodeHash {
+			if _, ok := codes[codeHash]; !ok {
+				code := rawdb.ReadCode(db, codeHash)
+				if len(code) == 0 {
+					return fmt.Errorf("missing code %x", codeHash)
+				}
+				if err := ew.add(exportCode, codeHash, code); err != nil {
+					return err
+				}
+				codes[codeHash] = struct{}{}
+			}
+		}
+		if err := ew.add(exportAccount, acctIt.Hash(), common.CopyBytes(acctIt.Account())); err != nil {
+			return err
+		}
+		accounts++
+
+		if account.Root != types.EmptyRootHash {
+			storageIt, err := snaptree.StorageIterator(root, acctIt.Hash(), common.Hash{})
+			if err != nil {
+				return err
+			}
+			for storageIt.Next() {
+				if err := ew.add(exportStorage, storageIt.Hash(), common.CopyBytes(storageIt.Slot())); err != nil {
+					storageIt.Release()
+					return err
+				}
+				slots++
+			}
+			err = storageIt.Error()
+			storageIt.Release()
+			if err != nil {
+				return err
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Exporting snapshot", "at", acctIt.Hash(), "accounts", accounts, "slots", slots, "codes", len(codes),
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := acctIt.Error(); err != nil {
+		return err
+	}
+	if err := ew.flush(); err != nil {
+		return err
+	}
+	if err := ew.writeChunk(nil); err != nil {
+		return err
+	}
+	log.Info("Exported snapshot", "number", block.Number(), "hash", block.Hash(), "root", root, "accounts", accounts, "slots", slots, "codes", len(codes),
+		"elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// readChunk reads and verifies the next chunk of a snapshot export, returning
+// its uncompressed payload. The payload of the closing chunk is empty.
+func readChunk(r io.Reader) ([]byte, error) {
+	var prefix [8]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errExportTruncated
+		}
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(prefix[:4])
+	if size > exportMaxChunk {
+		return nil, fmt.Errorf("oversized chunk: %d bytes", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errExportTruncated
+		}
+		return nil, err
+	}
+	if crc32.Checksum(data, exportChecksum) != binary.BigEndian.Uint32(prefix[4:]) {
+		return nil, errors.New("chunk checksum mismatch")
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	return snappy.Decode(nil, data)
+}
+
+// importer regenerates the state tries while restoring a snapshot export.
+type importer struct {
+	db     ethdb.Database
+	batch  ethdb.Batch
+	scheme string
+
+	accTrie     *trie.StackTrie
+	storageTrie *trie.StackTrie
+	owner       common.Hash // Hash of the account whose storage is being imported
+	account     []byte      // Slim RLP of the account whose storage is being imported
+
+	parent  common.Hash     // Hash of the last imported header
+	number  uint64          // Number of the last imported header
+	headers []*types.Header // Headers pending to be written into the ancient store
+
+	accounts, slots, codes uint64
+}
+
+// newImporter creates an importer writing the state into the given database,
+// and the header chain on top of the given genesis into its ancient store.
+func newImporter(db ethdb.Database, scheme string, genesis common.Hash) *importer {
+	imp := &importer{
+		db:     db,
+		batch:  db.NewBatch(),
+		scheme: scheme,
+		parent: genesis,
+	}
+	imp.accTrie = trie.NewStackTrie(func(path []byte, hash common.Hash, blob []byte) {
+		rawdb.WriteTrieNode(imp.batch, common.Hash{}, path, hash, blob, scheme)
+	})
+	imp.storageTrie = trie.NewStackTrie(func(path []byte, hash common.Hash, blob []byte) {
+		rawdb.WriteTrieNode(imp.batch, imp.owner, path, hash, blob, scheme)
+	})
+	return imp
+}
+
+// process imports a single record of a snapshot export.
+func (imp *importer) process(record *exportRecord) error {
+	switch record.Kind {
+	case exportChainHeader:
+		if imp.accounts > 0 || imp.codes > 0 {
+			return fmt.Errorf("header %x after state records", record.Hash)
+		}
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(record.Data, header); err != nil {
+			return fmt.Errorf("invalid header %x: %v", record.Hash, err)
+		}
+		if header.Hash() != record.Hash {
+			return fmt.Errorf("header hash mismatch: %x", record.Hash)
+		}
+		if header.Number.Uint64() != imp.number+1 || header.ParentHash != imp.parent {
+			return fmt.Errorf("header %d not linked to its parent", header.Number)
+		}
+		imp.parent, imp.number = record.Hash, header.Number.Uint64()
+		imp.headers = append(imp.headers, header)
+		if len(imp.headers) >= importHeaders {
+			return imp.flushHeaders()
+		}
+		return nil
+
+	case exportCode:
+		if crypto.Keccak256Hash(record.Data) != record.Hash {
+			return fmt.Errorf("code hash mismatch: %x", record.Hash)
+		}
+		rawdb.WriteCode(imp.batch, record.Hash, record.Data)
+		imp.codes++
+
+	case exportAccount:
+		if err := imp.finishAccount(); err != nil {
+			return err
+		}
+		imp.owner, imp.account = record.Hash, record.Data
+		rawdb.WriteAccountSnapshot(imp.batch, record.Hash, record.Data)
+		imp.accounts++
+
+	case exportStorage:
+		if imp.account == nil {
+			return fmt.Errorf("storage slot %x without account", record.Hash)
+		}
+		if err := imp.storageTrie.Update(record.Hash.Bytes(), record.Data); err != nil {
+			return fmt.Errorf("storage slot %x of account %x: %v", record.Hash, imp.owner, err)
+		}
+		rawdb.WriteStorageSnapshot(imp.batch, imp.owner, record.Hash, record.Data)
+		imp.slots++
+
+	default:
+		return fmt.Errorf("unknown record kind %d", record.Kind)
+	}
+	if imp.batch.ValueSize() >= ethdb.IdealBatchSize {
+		if err := imp.batch.Write(); err != nil {
+			return err
+		}
+		imp.batch.Reset()
+	}
+	return nil
+}
+
+// flushHeaders appends the pending headers to the ancient store, as skeleton
+// blocks whose bodies and receipts are left empty, to be pruned once the import
+// is done.
+func (imp *importer) flushHeaders() error {
+	if len(imp.headers) == 0 {
+		return nil
+	}
+	_, err := imp.db.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		for _, header := range imp.headers {
+			var (
+				number = header.Number.Uint64()
+				hash   = header.Hash()
+			)
+			if err := op.AppendRaw(rawdb.ChainFreezerHashTable, number, hash.Bytes()); err != nil {
+				return err
+			}
+			if err := op.Append(rawdb.ChainFreezerHeaderTable, number, header); err != nil {
+				return err
+			}
+			if err := op.Append(rawdb.ChainFreezerBodiesTable, number, new(types.Body)); err != nil {
+				return err
+			}
+			if err := op.AppendRaw(rawdb.ChainFreezerReceiptTable, number, rlp.EmptyList); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	imp.headers = imp.headers[:0]
+	return err
+}
+
+// writeAncientGenesis moves the genesis block into the empty ancient store of
+// the database, ahead of the imported header chain.
+func writeAncientGenesis(db ethdb.Database, genesis common.Hash) error {
+	var (
+		header   = rawdb.ReadHeaderRLP(db, genesis, 0)
+		body     = rawdb.ReadBodyRLP(db, genesis, 0)
+		receipts = rawdb.ReadReceiptsRLP(db, genesis, 0)
+	)
+	if header == nil || body == nil {
+		return errors.New("missing genesis block")
+	}
+	if receipts == nil {
+		receipts = rlp.EmptyList
+	}
+	_, err := db.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		if err := op.AppendRaw(rawdb.ChainFreezerHashTable, 0, genesis.Bytes()); err != nil {
+			return err
+		}
+		if err := op.AppendRaw(rawdb.ChainFreezerHeaderTable, 0, header); err != nil {
+			return err
+		}
+		if err := op.AppendRaw(rawdb.ChainFreezerBodiesTable, 0, body); err != nil {
+			return err
+		}
+		return op.AppendRaw(rawdb.ChainFreezerReceiptTable, 0, receipts)
+	})
+	return err
+}
+
+// decodeReceipts decodes the exported receipts of a block, and verifies them
+// against its receipt root.
+func decodeReceipts(blob []byte, block *types.Block) (types.Receipts, error) {
+	var stored []*types.ReceiptForStorage
+	if err := rlp.DecodeBytes(blob, &stored); err != nil {
+		return nil, fmt.Errorf("invalid export receipts: %v", err)
+	}
+	txs := block.Transactions()
+	if len(stored) != len(txs) {
+		return nil, fmt.Errorf("export receipt count mismatch: have %d, want %d", len(stored), len(txs))
+	}
+	receipts := make(types.Receipts, len(stored))
+	for i, receipt := range stored {
+		receipts[i] = (*types.Receipt)(receipt)
+		receipts[i].Type = txs[i].Type()
+	}
+	if root := types.DeriveSha(receipts, trie.NewStackTrie(nil)); root != block.ReceiptHash() {
+		return nil, fmt.Errorf("export receipt root mismatch: have %x, want %x", root, block.ReceiptHash())
+	}
+	return receipts, nil
+}
+
+// finishAccount completes the storage trie of the last imported account and
+// inserts the account into the account trie.
+func (imp *importer) finishAccount() error {
+	if imp.account == nil {
+		return nil
+	}
+	account, err := types.FullAccount(imp.account)
+	if err != nil {
+		return err
+	}
+	if root := imp.storageTrie.Hash(); root != account.Root {
+		return fmt.Errorf("storage root mismatch of account %x: have %x, want %x", imp.owner, root, account.Root)
+	}
+	imp.storageTrie.Reset()
+
+	blob, err := types.FullAccountRLP(imp.account)
+	if err != nil {
+		return err
+	}
+	if err := imp.accTrie.Update(imp.owner.Bytes(), blob); err != nil {
+		return fmt.Errorf("account %x: %v", imp.owner, err)
+	}
+	imp.account = nil
+	return nil
+}
+
+// checkImportable ensures a database can be the target of a snapshot import: it
+// must be initialized with the genesis block of the exported network, and must
+// not hold any chain or state snapshot beyond it.
+func checkImportable(db ethdb.Database, genesis common.Hash) error {
+	if have := rawdb.ReadCanonicalHash(db, 0); have == (common.Hash{}) {
+		return errors.New("database not initialized with a genesis block")
+	} else if have != genesis {
+		return fmt.Errorf("genesis mismatch: have %x, want %x", have, genesis)
+	}
+	if head := rawdb.ReadHeadHeader(db); head == nil || head.Number.Sign() > 0 {
+		return errors.New("database not empty, it holds a chain beyond the genesis")
+	}
+	if root := rawdb.ReadSnapshotRoot(db); root != (common.Hash{}) {
+		return fmt.Errorf("database not empty, it holds the state snapshot %x", root)
+	}
+	if frozen, err := db.Ancients(); err != nil {
+		return fmt.Errorf("database without ancient store: %v", err)
+	} else if frozen > 0 {
+		return errors.New("database not empty, it holds ancient chain segments")
+	}
+	return nil
+}
+
+// Import restores a snapshot export read from r into the given database, which
+// must hold nothing but the genesis block of the exported network. The account
+// and storage tries are regenerated in the given state scheme, and their root
+// verified against the exported one. On success, the database holds a complete
+// snapshot of the state, and the block it belongs to is the head of the chain.
+//
+// The chain history below the imported block is not restored. Its headers are
+// written into the ancient store, with the bodies and receipts pruned up to the
+// imported block, as if the history was expired.
+func Import(r io.Reader, db ethdb.Database, scheme string) (*types.Block, error) {
+	magic := make([]byte, len(exportMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != string(exportMagic) {
+		return nil, errors.New("not a snapshot export")
+	}
+	payload, err := readChunk(r)
+	if err != nil {
+		return nil, err
+	}
+	var header exportHeader
+	if err := rlp.DecodeBytes(payload, &header); err != nil {
+		return nil, fmt.Errorf("invalid export header: %v", err)
+	}
+	if header.Version != exportVersion {
+		return nil, fmt.Errorf("unsupported export version %d", header.Version)
+	}
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(header.Block, block); err != nil {
+		return nil, fmt.Errorf("invalid export block: %v", err)
+	}
+	if block.Root() != header.Root {
+		return nil, fmt.Errorf("export block root mismatch: have %x, want %x", block.Root(), header.Root)
+	}
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis state export")
+	}
+	receipts, err := decodeReceipts(header.Receipts, block)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkImportable(db, header.Genesis); err != nil {
+		return nil, err
+	}
+	log.Info("Importing snapshot", "number", block.Number(), "hash", block.Hash(), "root", header.Root, "scheme", scheme)
+
+	// The imported header chain is appended to the genesis in the ancient store
+	if err := writeAncientGenesis(db, header.Genesis); err != nil {
+		return nil, err
+	}
+	var (
+		imp    = newImporter(db, scheme, header.Genesis)
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for {
+		payload, err := readChunk(r)
+		if err != nil {
+			return nil, err
+		}
+		if payload == nil {
+			break
+		}
+		var records []exportRecord
+		if err := rlp.DecodeBytes(payload, &records); err != nil {
+			return nil, fmt.Errorf("invalid chunk: %v", err)
+		}
+		for i := range records {
+			if err := imp.process(&records[i]); err != nil {
+				return nil, err
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Importing snapshot", "at", imp.owner, "accounts", imp.accounts, "slots", imp.slots, "codes", imp.codes,
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := imp.finishAccount(); err != nil {
+		return nil, err
+	}
+	if root := imp.accTrie.Hash(); root != header.Root {
+		return nil, fmt.Errorf("state root mismatch: have %x, want %x", root, header.Root)
+	}
+	if err := imp.flushHeaders(); err != nil {
+		return nil, err
+	}
+	if imp.number+1 != block.NumberU64() || block.ParentHash() != imp.parent {
+		return nil, fmt.Errorf("header chain not linked to block %d", block.NumberU64())
+	}
+	// Prune the missing bodies and receipts of the chain history
+	if _, err := db.TruncateTail(block.NumberU64()); err != nil {
+		return nil, err
+	}
+	if err := db.Sync(); err != nil {
+		return nil, err
+	}
+	// The state is complete, mark the snapshot as fully generated and make its
+	// block the head of the chain
+	rawdb.WriteSnapshotRoot(imp.batch, header.Root)
+	journalProgress(imp.batch, nil, &generatorStats{accounts: imp.accounts, slots: imp.slots})
+
+	rawdb.WriteBlock(imp.batch, block)
+	rawdb.WriteReceipts(imp.batch, block.Hash(), block.NumberU64(), receipts)
+	rawdb.WriteCanonicalHash(imp.batch, block.Hash(), block.NumberU64())
+	rawdb.WriteHeadHeaderHash(imp.batch, block.Hash())
+	rawdb.WriteHeadFastBlockHash(imp.batch, block.Hash())
+	rawdb.WriteHeadBlockHash(imp.batch, block.Hash())
+	if err := imp.batch.Write(); err != nil {
+		return nil, err
+	}
+	log.Info("Imported snapshot", "number", block.Number(), "hash", block.Hash(), "root", header.Root,
+		"accounts", imp.accounts, "slots", imp.slots, "codes", imp.codes, "elapsed", common.PrettyDuration(time.Since(start)))
+	return block, nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/hashdb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
+	"github.com/holiman/uint256"
+)
+
+// testGenesis is the genesis block of the chain the exported states belong to.
+var testGenesis = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Root: types.EmptyRootHash})
+
+// writeTestGenesis initializes a database with the test genesis block.
+func writeTestGenesis(db ethdb.KeyValueWriter) {
+	rawdb.WriteBlock(db, testGenesis)
+	rawdb.WriteCanonicalHash(db, testGenesis.Hash(), 0)
+	rawdb.WriteHeadHeaderHash(db, testGenesis.Hash())
+	rawdb.WriteHeadFastBlockHash(db, testGenesis.Hash())
+	rawdb.WriteHeadBlockHash(db, testGenesis.Hash())
+}
+
+// newImportTarget creates a database with an ancient store, initialized with
+// the test genesis block.
+func newImportTarget() ethdb.Database {
+	db, _ := rawdb.NewDatabaseWithFreezer(memorydb.New(), "", "", false)
+	writeTestGenesis(db)
+	return db
+}
+
+// writeTestChain writes a canonical chain of empty blocks on top of the test
+// genesis, up to the parent of the given number, and returns its headers.
+func writeTestChain(db ethdb.KeyValueWriter, number uint64) []*types.Header {
+	var (
+		headers []*types.Header
+		parent  = testGenesis.Hash()
+	)
+	for n := uint64(1); n < number; n++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(n), Root: types.EmptyRootHash, ParentHash: parent}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), n)
+		headers = append(headers, header)
+		parent = header.Hash()
+	}
+	return headers
+}
+
+// Tests that a snapshot can be exported and imported back into both state
+// schemes, regenerating the same state along with the block it belongs to.
+func TestExportImport(t *testing.T) {
+	var (
+		helper   = newHelper(rawdb.HashScheme)
+		code     = []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+		codeHash = crypto.Keccak256Hash(code)
+	)
+	stRoot := helper.makeStorageTrie("", []string{"key-1", "key-2", "key-3"}, []string{"val-1", "val-2", "val-3"}, false)
+	helper.addTrieAccount("acc-1", &types.StateAccount{Balance: uint256.NewInt(1), Root: stRoot, CodeHash: codeHash.Bytes()})
+	helper.addTrieAccount("acc-2", &types.StateAccount{Balance: uint256.NewInt(2), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()})
+	helper.addTrieAccount("acc-3", &types.StateAccount{Balance: uint256.NewInt(3), Root: stRoot, CodeHash: codeHash.Bytes()})
+	helper.makeStorageTrie("acc-1", []string{"key-1", "key-2", "key-3"}, []string{"val-1", "val-2", "val-3"}, true)
+	helper.makeStorageTrie("acc-3", []string{"key-1", "key-2", "key-3"}, []string{"val-1", "val-2", "val-3"}, true)
+	rawdb.WriteCode(helper.diskdb, codeHash, code)
+
+	root, snap := helper.CommitAndGenerate()
+	select {
+	case <-snap.genPending:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Snapshot generation failed")
+	}
+	defer func() {
+		stop := make(chan *generatorStats)
+		snap.genAbort <- stop
+		<-stop
+	}()
+	snaps := &Tree{layers: map[common.Hash]snapshot{root: snap}}
+
+	writeTestGenesis(helper.diskdb)
+	headers := writeTestChain(helper.diskdb, 10)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10), Root: root, ParentHash: headers[8].Hash(), ReceiptHash: types.EmptyReceiptsHash})
+	rawdb.WriteReceipts(helper.diskdb, block.Hash(), 10, nil)
+
+	var export bytes.Buffer
+	if err := Export(&export, snaps, block, helper.diskdb); err != nil {
+		t.Fatalf("Failed to export snapshot: %v", err)
+	}
+	for _, scheme := range []string{rawdb.HashScheme, rawdb.PathScheme} {
+		db := newImportTarget()
+
+		imported, err := Import(bytes.NewReader(export.Bytes()), db, scheme)
+		if err != nil {
+			t.Fatalf("%s: failed to import snapshot: %v", scheme, err)
+		}
+		if imported.Hash() != block.Hash() {
+			t.Fatalf("%s: block mismatch: have %x, want %x", scheme, imported.Hash(), block.Hash())
+		}
+		if head := rawdb.ReadHeadBlock(db); head == nil || head.Hash() != block.Hash() {
+			t.Fatalf("%s: head block mismatch: have %v, want %x", scheme, head, block.Hash())
+		}
+		if hash := rawdb.ReadHeadHeaderHash(db); hash != block.Hash() {
+			t.Fatalf("%s: head header mismatch: have %x, want %x", scheme, hash, block.Hash())
+		}
+		if hash := rawdb.ReadHeadFastBlockHash(db); hash != block.Hash() {
+			t.Fatalf("%s: head snap block mismatch: have %x, want %x", scheme, hash, block.Hash())
+		}
+		if hash := rawdb.ReadCanonicalHash(db, block.NumberU64()); hash != block.Hash() {
+			t.Fatalf("%s: canonical hash mismatch: have %x, want %x", scheme, hash, block.Hash())
+		}
+		// The header chain must be frozen, with the history pruned below the block
+		for _, header := range headers {
+			if have := rawdb.ReadHeader(db, header.Hash(), header.Number.Uint64()); have == nil || have.Hash() != header.Hash() {
+				t.Fatalf("%s: header %d mismatch: have %v, want %x", scheme, header.Number, have, header.Hash())
+			}
+		}
+		if frozen, _ := db.Ancients(); frozen != block.NumberU64() {
+			t.Fatalf("%s: frozen blocks mismatch: have %d, want %d", scheme, frozen, block.NumberU64())
+		}
+		if tail, _ := db.Tail(); tail != block.NumberU64() {
+			t.Fatalf("%s: history tail mismatch: have %d, want %d", scheme, tail, block.NumberU64())
+		}
+		if have := rawdb.ReadCode(db, codeHash); !bytes.Equal(have, code) {
+			t.Fatalf("%s: code mismatch: have %x, want %x", scheme, have, code)
+		}
+		config := &triedb.Config{HashDB: &hashdb.Config{}}
+		if scheme == rawdb.PathScheme {
+			config = &triedb.Config{PathDB: &pathdb.Config{}}
+		}
+		tdb := triedb.NewDatabase(db, config)
+		tr, err := trie.NewStateTrie(trie.StateTrieID(root), tdb)
+		if err != nil {
+			t.Fatalf("%s: failed to open imported trie: %v", scheme, err)
+		}
+		it := trie.NewIterator(tr.MustNodeIterator(nil))
+		for it.Next() {
+		}
+		if it.Err != nil {
+			t.Fatalf("%s: imported trie is incomplete: %v", scheme, it.Err)
+		}
+		tree, err := New(Config{CacheSize: 16, NoBuild: true}, db, tdb, root)
+		if err != nil {
+			t.Fatalf("%s: failed to load imported snapshot: %v", scheme, err)
+		}
+		checkSnapRoot(t, tree.disklayer(), root)
+
+		// Importing again into the now populated database must fail
+		if _, err := Import(bytes.NewReader(export.Bytes()), db, scheme); err == nil {
+			t.Fatalf("%s: imported into non-empty database", scheme)
+		}
+	}
+	// Importing into a database without genesis, ancient store or with another
+	// genesis must fail
+	if _, err := Import(bytes.NewReader(export.Bytes()), rawdb.NewMemoryDatabase(), rawdb.HashScheme); err == nil {
+		t.Fatal("Imported into uninitialized database")
+	}
+	noancients := rawdb.NewMemoryDatabase()
+	writeTestGenesis(noancients)
+	if _, err := Import(bytes.NewReader(export.Bytes()), noancients, rawdb.HashScheme); err == nil {
+		t.Fatal("Imported into database without ancient store")
+	}
+	other, _ := rawdb.NewDatabaseWithFreezer(memorydb.New(), "", "", false)
+	genesis := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Root: types.EmptyRootHash, Extra: []byte("other")})
+	rawdb.WriteBlock(other, genesis)
+	rawdb.WriteCanonicalHash(other, genesis.Hash(), 0)
+	rawdb.WriteHeadHeaderHash(other, genesis.Hash())
+	if _, err := Import(bytes.NewReader(export.Bytes()), other, rawdb.HashScheme); err == nil {
+		t.Fatal("Imported into database of another network")
+	}
+	// Importing into a database holding a state snapshot must fail
+	stale := newImportTarget()
+	rawdb.WriteSnapshotRoot(stale, types.EmptyRootHash)
+	if _, err := Import(bytes.NewReader(export.Bytes()), stale, rawdb.HashScheme); err == nil {
+		t.Fatal("Imported into database with existing state snapshot")
+	}
+	// Corrupt a byte of the export and ensure it's rejected
+	corrupt := bytes.Clone(export.Bytes())
+	corrupt[len(corrupt)/2] ^= 0xff
+	if _, err := Import(bytes.NewReader(corrupt), newImportTarget(), rawdb.HashScheme); err == nil {
+		t.Fatal("Corrupted export imported")
+	}
+	// Truncate the export and ensure it's rejected
+	if _, err := Import(bytes.NewReader(export.Bytes()[:export.Len()-8]), newImportTarget(), rawdb.HashScheme); err != errExportTruncated {
+		t.Fatalf("Truncated export error mismatch: have %v, want %v", err, errExportTruncated)
+	}
+}
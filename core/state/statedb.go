@@ -138,6 +138,9 @@ type StateDB struct {
 	// State witness if cross validation is needed
 	witness *stateless.Witness
 
+	// Read set and deferred fees of a speculatively executed transaction
+	access *accessTracker
+
 	// Measurements gathered during execution for debugging purposes
 	AccountReads    time.Duration
 	AccountHashes   time.Duration
@@ -212,6 +215,29 @@ func (s *StateDB) StartPrefetcher(namespace string, witness *stateless.Witness)
 	}
 }
 
+// Prefetch schedules the given accounts and storage slots to be loaded by the
+// running prefetcher ahead of their use, e.g. as hinted by access lists. The
+// storage roots of the accounts owning hinted slots are resolved by the
+// prefetcher in the background, so the call does not block on any state read.
+// It's a noop if no prefetcher is running.
+func (s *StateDB) Prefetch(hints *AccessSet) {
+	if s.prefetcher == nil {
+		return
+	}
+	if err := s.prefetcher.prefetch(common.Hash{}, s.originalRoot, common.Address{}, hints.Accounts(), nil, false); err != nil {
+		log.Error("Failed to prefetch hinted accounts", "err", err)
+		return
+	}
+	if len(hints.slots) == 0 {
+		return
+	}
+	slots := make(map[common.Address][]common.Hash, len(hints.slots))
+	for addr, keys := range hints.slots {
+		slots[addr] = slices.Collect(maps.Keys(keys))
+	}
+	s.prefetcher.prefetchStorage(slots)
+}
+
 // StopPrefetcher terminates a running prefetcher and reports any leftover stats
 // from the gathered metrics.
 func (s *StateDB) StopPrefetcher() {
@@ -370,6 +396,7 @@ func (s *StateDB) GetCodeHash(addr common.Address) common.Hash {
 
 // GetState retrieves the value associated with the specific key.
 func (s *StateDB) GetState(addr common.Address, hash common.Hash) common.Hash {
+	s.trackSlot(addr, hash)
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.GetState(hash)
@@ -380,6 +407,7 @@ func (s *StateDB) GetState(addr common.Address, hash common.Hash) common.Hash {
 // GetCommittedState retrieves the value associated with the specific key
 // without any mutations caused in the current execution.
 func (s *StateDB) GetCommittedState(addr common.Address, hash common.Hash) common.Hash {
+	s.trackSlot(addr, hash)
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.GetCommittedState(hash)
@@ -406,6 +434,9 @@ func (s *StateDB) HasSelfDestructed(addr common.Address) bool {
 
 // AddBalance adds amount to the account associated with addr.
 func (s *StateDB) AddBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	if s.deferFee(addr, amount, reason) {
+		return uint256.Int{}
+	}
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject == nil {
 		return uint256.Int{}
@@ -569,6 +600,8 @@ func (s *StateDB) deleteStateObject(addr common.Address) {
 // getStateObject retrieves a state object given by the address, returning nil if
 // the object is not found or was deleted in this execution context.
 func (s *StateDB) getStateObject(addr common.Address) *stateObject {
+	s.trackAccount(addr)
+
 	// Prefer live objects if any is available
 	if obj := s.stateObjects[addr]; obj != nil {
 		return obj
@@ -21,6 +21,8 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 )
@@ -38,12 +40,14 @@ var (
 // items and does trie-loading of them. The goal is to get as much useful content
 // into the caches as possible.
 //
-// Note, the prefetcher's API is not thread safe.
+// Note, the prefetcher's API is not thread safe. Only the set of subfetchers is
+// guarded, as it's extended from the background by hinted storage prefetches.
 type triePrefetcher struct {
 	verkle   bool                   // Flag whether the prefetcher is in verkle mode
 	db       Database               // Database to fetch trie nodes through
 	root     common.Hash            // Root hash of the account trie for metrics
 	fetchers map[string]*subfetcher // Subfetchers for each trie
+	lock     sync.Mutex             // Mutex protecting the subfetchers and termination
 	term     chan struct{}          // Channel to signal interruption
 	noreads  bool                   // Whether to ignore state-read-only prefetch requests
 
@@ -96,6 +100,9 @@ func newTriePrefetcher(db Database, root common.Hash, namespace string, noreads
 // to all of them. Depending on the async parameter, the method will either block
 // until all subfetchers spin down, or return immediately.
 func (p *triePrefetcher) terminate(async bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
 	// Short circuit if the fetcher is already closed
 	select {
 	case <-p.term:
@@ -114,6 +121,9 @@ func (p *triePrefetcher) report() {
 	if !metrics.Enabled() {
 		return
 	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
 	for _, fetcher := range p.fetchers {
 		fetcher.wait() // ensure the fetcher's idle before poking in its internals
 
@@ -163,6 +173,9 @@ func (p *triePrefetcher) prefetch(owner common.Hash, root common.Hash, addr comm
 	if read && p.noreads {
 		return nil
 	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
 	// Ensure the subfetcher is still alive
 	select {
 	case <-p.term:
@@ -178,12 +191,47 @@ func (p *triePrefetcher) prefetch(owner common.Hash, root common.Hash, addr comm
 	return fetcher.schedule(addrs, slots, read)
 }
 
+// prefetchStorage schedules a batch of storage slots to prefetch, without the
+// owning accounts having to be resolved by the caller. The storage roots are
+// looked up in the background through a dedicated account trie, after which the
+// slots are handed over to the matching storage subfetchers.
+func (p *triePrefetcher) prefetchStorage(slots map[common.Address][]common.Hash) {
+	go func() {
+		tr, err := p.db.OpenTrie(p.root)
+		if err != nil {
+			log.Warn("Trie prefetcher failed opening account trie", "root", p.root, "err", err)
+			return
+		}
+		for addr, keys := range slots {
+			select {
+			case <-p.term:
+				return
+			default:
+			}
+			account, err := tr.GetAccount(addr)
+			if err != nil {
+				log.Error("Trie prefetcher failed resolving account", "addr", addr, "err", err)
+				continue
+			}
+			if account == nil || account.Root == types.EmptyRootHash {
+				continue
+			}
+			if err := p.prefetch(crypto.Keccak256Hash(addr.Bytes()), account.Root, addr, nil, keys, false); err != nil {
+				return
+			}
+		}
+	}()
+}
+
 // trie returns the trie matching the root hash, blocking until the fetcher of
 // the given trie terminates. If no fetcher exists for the request, nil will be
 // returned.
 func (p *triePrefetcher) trie(owner common.Hash, root common.Hash) Trie {
 	// Bail if no trie was prefetched for this root
+	p.lock.Lock()
 	fetcher := p.fetchers[p.trieID(owner, root)]
+	p.lock.Unlock()
+
 	if fetcher == nil {
 		log.Error("Prefetcher missed to load trie", "owner", owner, "root", root)
 		p.deliveryMissMeter.Mark(1)
@@ -196,7 +244,11 @@ func (p *triePrefetcher) trie(owner common.Hash, root common.Hash) Trie {
 // used marks a batch of state items used to allow creating statistics as to
 // how useful or wasteful the fetcher is.
 func (p *triePrefetcher) used(owner common.Hash, root common.Hash, usedAddr []common.Address, usedSlot []common.Hash) {
-	if fetcher := p.fetchers[p.trieID(owner, root)]; fetcher != nil {
+	p.lock.Lock()
+	fetcher := p.fetchers[p.trieID(owner, root)]
+	p.lock.Unlock()
+
+	if fetcher != nil {
 		fetcher.wait() // ensure the fetcher's idle before poking in its internals
 
 		fetcher.usedAddr = append(fetcher.usedAddr, usedAddr...)
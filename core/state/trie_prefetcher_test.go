@@ -19,6 +19,7 @@ package state
 import (
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -105,3 +106,52 @@ func TestVerklePrefetcher(t *testing.T) {
 		t.Fatal("Two different tries are retrieved")
 	}
 }
+
+func TestPrefetchStorage(t *testing.T) {
+	db := NewDatabaseForTesting()
+	state, _ := New(types.EmptyRootHash, db)
+
+	addr := testrand.Address()
+	skey := testrand.Hash()
+	sval := testrand.Hash()
+	state.SetBalance(addr, uint256.NewInt(42), tracing.BalanceChangeUnspecified)
+	state.SetState(addr, skey, sval)
+	root, _ := state.Commit(0, true, false)
+
+	state, _ = New(root, db)
+	sRoot := state.GetStorageRoot(addr)
+
+	// Schedule the slots of a known and an unknown account, without resolving
+	// either of them upfront.
+	fetcher := newTriePrefetcher(db, root, "", false)
+	fetcher.prefetchStorage(map[common.Address][]common.Hash{
+		addr:               {skey},
+		testrand.Address(): {testrand.Hash()},
+	})
+	owner := crypto.Keccak256Hash(addr.Bytes())
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		fetcher.lock.Lock()
+		scheduled := fetcher.fetchers[fetcher.trieID(owner, sRoot)] != nil
+		fetcher.lock.Unlock()
+
+		if scheduled {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Hinted storage was not scheduled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	fetcher.terminate(false)
+
+	tr := fetcher.trie(owner, sRoot)
+	if tr == nil {
+		t.Fatal("Prefetcher returned nil storage trie")
+	}
+	if tr.Hash() != sRoot {
+		t.Fatalf("Storage trie root mismatch: have %x, want %x", tr.Hash(), sRoot)
+	}
+	if n := len(fetcher.fetchers); n != 1 {
+		t.Fatalf("Subfetcher count mismatch: have %d, want 1", n)
+	}
+}
@@ -19,6 +19,7 @@ package core
 import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -78,3 +79,74 @@ func ExecuteStateless(config *params.ChainConfig, vmconfig vm.Config, block *typ
 	stateRoot := db.IntermediateRoot(config.IsEIP158(block.Number()))
 	return stateRoot, receiptRoot, nil
 }
+
+// BuildWitness re-executes a block of the chain on top of its parent state and
+// returns the witness required to execute it statelessly. The parent state needs
+// to be available, which is only guaranteed for recent blocks on non-archive
+// nodes.
+func (bc *BlockChain) BuildWitness(block *types.Block) (*stateless.Witness, error) {
+	witness, _, err := bc.buildWitness(block, false)
+	return witness, err
+}
+
+// WitnessStats re-executes a block of the chain on top of its parent state and
+// analyzes the witness required to execute it statelessly, attributing its
+// content to the individual transactions.
+func (bc *BlockChain) WitnessStats(block *types.Block) (*stateless.Stats, error) {
+	witness, accesses, err := bc.buildWitness(block, true)
+	if err != nil {
+		return nil, err
+	}
+	return witness.Stats(accesses), nil
+}
+
+// buildWitness re-executes a block on top of its parent state, collecting its
+// witness and optionally the state locations accessed by each transaction.
+func (bc *BlockChain) buildWitness(block *types.Block, record bool) (*stateless.Witness, []*stateless.Accesses, error) {
+	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, nil, consensus.ErrUnknownAncestor
+	}
+	statedb, err := bc.StateAt(parent.Root)
+	if err != nil {
+		return nil, nil, consensus.ErrPrunedAncestor
+	}
+	witness, err := stateless.NewWitness(block.Header(), bc)
+	if err != nil {
+		return nil, nil, err
+	}
+	statedb.StartPrefetcher("witness", witness)
+	defer statedb.StopPrefetcher()
+
+	// Execute the block without tracing, recording its access list if committed
+	// to by the header, as it's part of the validation.
+	var (
+		vmConfig = bc.vmConfig
+		recorder *accessListRecorder
+		accesses *witnessAccessRecorder
+	)
+	vmConfig.Tracer = nil
+	if block.Header().BlockAccessListHash != nil {
+		recorder = newAccessListRecorder(statedb)
+		vmConfig.Tracer = recorder.hooks(vmConfig.Tracer)
+	}
+	if record {
+		accesses = newWitnessAccessRecorder(statedb)
+		vmConfig.Tracer = accesses.hooks(vmConfig.Tracer)
+	}
+	res, err := bc.processor.Process(block, statedb, vmConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if recorder != nil {
+		res.AccessList = recorder.finish()
+	}
+	// Validate the state to pull in the trie nodes needed for the post-state root
+	if err := bc.validator.ValidateState(block, statedb, res, false); err != nil {
+		return nil, nil, err
+	}
+	if accesses != nil {
+		return witness, accesses.finish(), nil
+	}
+	return witness, nil, nil
+}
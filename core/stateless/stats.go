@@ -0,0 +1,262 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package stateless
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Accesses is the set of state locations accessed in the scope of a single
+// transaction, used to attribute the content of a witness to it.
+type Accesses struct {
+	Accounts []common.Address
+	Slots    map[common.Address][]common.Hash
+}
+
+// Stats is the size and composition breakdown of a witness.
+type Stats struct {
+	Size uint64 `json:"size"` // Size of the RLP encoded witness
+
+	Headers     int    `json:"headers"`     // Number of past headers
+	HeaderBytes uint64 `json:"headerBytes"` // Size of the RLP encoded past headers
+	Codes       int    `json:"codes"`       // Number of bytecodes
+	CodeBytes   uint64 `json:"codeBytes"`   // Size of the bytecodes
+	Nodes       int    `json:"nodes"`       // Number of trie nodes
+	NodeBytes   uint64 `json:"nodeBytes"`   // Size of the trie nodes
+
+	AccountNodes []int `json:"accountNodes"` // Number of account trie nodes by depth
+	StorageNodes []int `json:"storageNodes"` // Number of storage trie nodes by depth
+	Unreachable  int   `json:"unreachable"`  // Number of trie nodes not reachable from the pre-state root
+
+	// Contribution of the individual transactions, if the accesses are known.
+	// Indexed as the block access lists: 0 being the pre-execution system calls
+	// and len(txs)+1 the post-execution operations.
+	Transactions []TxStats `json:"transactions,omitempty"`
+}
+
+// TxStats is the marginal contribution of a transaction to a witness: the trie
+// nodes and bytecodes needed to access its locations, which were not already
+// needed by the preceding transactions.
+type TxStats struct {
+	Index     int    `json:"index"`
+	Nodes     int    `json:"nodes"`
+	NodeBytes uint64 `json:"nodeBytes"`
+	Codes     int    `json:"codes"`
+	CodeBytes uint64 `json:"codeBytes"`
+}
+
+// Stats analyzes the composition of the witness. If the locations accessed by
+// the individual transactions are given, the content of the witness is also
+// attributed to them. Nodes needed only to compute the post-state root, e.g.
+// the siblings of deleted ones, are not attributed to any transaction.
+func (w *Witness) Stats(accesses []*Accesses) *Stats {
+	stats := &Stats{
+		Headers: len(w.Headers),
+		Codes:   len(w.Codes),
+		Nodes:   len(w.State),
+	}
+	if blob, err := rlp.EncodeToBytes(w); err == nil {
+		stats.Size = uint64(len(blob))
+	}
+	for _, header := range w.Headers {
+		if blob, err := rlp.EncodeToBytes(header); err == nil {
+			stats.HeaderBytes += uint64(len(blob))
+		}
+	}
+	a := &witnessAnalyzer{
+		nodes: make(map[common.Hash][]byte, len(w.State)),
+		codes: make(map[common.Hash]int, len(w.Codes)),
+		seen:  make(map[common.Hash]struct{}),
+	}
+	for code := range w.Codes {
+		stats.CodeBytes += uint64(len(code))
+		a.codes[crypto.Keccak256Hash([]byte(code))] = len(code)
+	}
+	for node := range w.State {
+		stats.NodeBytes += uint64(len(node))
+		a.nodes[crypto.Keccak256Hash([]byte(node))] = []byte(node)
+	}
+	// Traverse all the tries reachable from the pre-state root
+	if len(w.Headers) > 0 {
+		visited := make(map[common.Hash]struct{})
+		a.walkTrie(w.Root(), visited, &stats.AccountNodes, &stats.StorageNodes)
+		stats.Unreachable = len(a.nodes) - len(visited)
+	}
+	// Attribute the content to the transactions in the order of execution
+	for i, access := range accesses {
+		tx := TxStats{Index: i}
+		if access != nil && len(w.Headers) > 0 {
+			a.attribute(w.Root(), access, &tx)
+		}
+		stats.Transactions = append(stats.Transactions, tx)
+	}
+	return stats
+}
+
+// witnessAnalyzer resolves the trie nodes and bytecodes of a witness.
+type witnessAnalyzer struct {
+	nodes map[common.Hash][]byte // Trie nodes by hash
+	codes map[common.Hash]int    // Bytecode sizes by hash
+	seen  map[common.Hash]struct{}
+}
+
+// walkTrie traverses the account trie with the given root and all the storage
+// tries it references, counting the nodes contained in the witness by depth.
+func (a *witnessAnalyzer) walkTrie(root common.Hash, visited map[common.Hash]struct{}, accounts, storages *[]int) {
+	var walk func(blob []byte, depth int, counts *[]int, onLeaf func(value []byte))
+	walk = func(blob []byte, depth int, counts *[]int, onLeaf func(value []byte)) {
+		var children []common.Hash
+		trie.ForGatherChildrenWithPath(blob, func(_ []byte, hash common.Hash) {
+			children = append(children, hash)
+		}, func(_ []byte, value []byte) {
+			if onLeaf != nil {
+				onLeaf(value)
+			}
+		})
+		for _, hash := range children {
+			if _, ok := visited[hash]; ok {
+				continue
+			}
+			if child, ok := a.nodes[hash]; ok {
+				visited[hash] = struct{}{}
+				addDepth(counts, depth+1)
+				walk(child, depth+1, counts, onLeaf)
+			}
+		}
+	}
+	onAccount := func(value []byte) {
+		var account types.StateAccount
+		if err := rlp.DecodeBytes(value, &account); err != nil || account.Root == types.EmptyRootHash {
+			return
+		}
+		if _, ok := visited[account.Root]; ok {
+			return
+		}
+		if blob, ok := a.nodes[account.Root]; ok {
+			visited[account.Root] = struct{}{}
+			addDepth(storages, 0)
+			walk(blob, 0, storages, nil)
+		}
+	}
+	if blob, ok := a.nodes[root]; ok {
+		visited[root] = struct{}{}
+		addDepth(accounts, 0)
+		walk(blob, 0, accounts, onAccount)
+	}
+}
+
+// attribute accounts the trie nodes and bytecodes needed to access the given
+// locations, which were not attributed yet, to the transaction.
+func (a *witnessAnalyzer) attribute(root common.Hash, access *Accesses, tx *TxStats) {
+	onNode := func(hash common.Hash) {
+		if _, ok := a.seen[hash]; ok {
+			return
+		}
+		a.seen[hash] = struct{}{}
+		tx.Nodes++
+		tx.NodeBytes += uint64(len(a.nodes[hash]))
+	}
+	account := func(addr common.Address) *types.StateAccount {
+		value := a.walkPath(root, crypto.Keccak256(addr.Bytes()), onNode)
+		if value == nil {
+			return nil
+		}
+		account := new(types.StateAccount)
+		if err := rlp.DecodeBytes(value, account); err != nil {
+			return nil
+		}
+		return account
+	}
+	for _, addr := range access.Accounts {
+		acc := account(addr)
+		if acc == nil {
+			continue
+		}
+		hash := common.BytesToHash(acc.CodeHash)
+		if size, ok := a.codes[hash]; ok {
+			if _, ok := a.seen[hash]; !ok {
+				a.seen[hash] = struct{}{}
+				tx.Codes++
+				tx.CodeBytes += uint64(size)
+			}
+		}
+	}
+	for addr, slots := range access.Slots {
+		acc := account(addr)
+		if acc == nil || acc.Root == types.EmptyRootHash {
+			continue
+		}
+		for _, slot := range slots {
+			a.walkPath(acc.Root, crypto.Keccak256(slot.Bytes()), onNode)
+		}
+	}
+}
+
+// walkPath traverses the trie with the given root along the path of a key as
+// far as the witness allows, invoking onNode for all the nodes on the way. The
+// value of the key is returned if it's reached.
+func (a *witnessAnalyzer) walkPath(root common.Hash, key []byte, onNode func(common.Hash)) []byte {
+	blob, ok := a.nodes[root]
+	if !ok {
+		return nil
+	}
+	onNode(root)
+
+	path := trie.KeybytesToNibbles(key)
+	for {
+		var (
+			next  common.Hash
+			rest  []byte
+			found bool
+			value []byte
+		)
+		err := trie.ForGatherChildrenWithPath(blob, func(sub []byte, hash common.Hash) {
+			if bytes.HasPrefix(path, sub) {
+				next, rest, found = hash, path[len(sub):], true
+			}
+		}, func(sub []byte, val []byte) {
+			if bytes.Equal(path, sub) {
+				value = val
+			}
+		})
+		if err != nil || value != nil {
+			return value
+		}
+		if !found {
+			return nil
+		}
+		if blob, ok = a.nodes[next]; !ok {
+			return nil
+		}
+		onNode(next)
+		path = rest
+	}
+}
+
+// addDepth increments the counter of the given depth.
+func addDepth(counts *[]int, depth int) {
+	for len(*counts) <= depth {
+		*counts = append(*counts, 0)
+	}
+	(*counts)[depth]++
+}
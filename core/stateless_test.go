@@ -0,0 +1,201 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Tests that the witnesses built for imported blocks survive an encoding round
+// trip and are sufficient to execute the blocks statelessly.
+func TestBuildWitness(t *testing.T) {
+	var (
+		config  = params.MergedTestChainConfig
+		engine  = beacon.New(ethash.NewFaker())
+		key, _  = crypto.GenerateKey()
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+		counter = common.HexToAddress("0xc0")
+		gspec   = &Genesis{
+			Config: config,
+			Alloc: types.GenesisAlloc{
+				sender:                    {Balance: big.NewInt(params.Ether)},
+				params.BeaconRootsAddress: {Code: params.BeaconRootsCode},
+				// slot0 += 1
+				counter: {Code: []byte{byte(vm.PUSH1), 0, byte(vm.SLOAD), byte(vm.PUSH1), 1, byte(vm.ADD), byte(vm.PUSH1), 0, byte(vm.SSTORE), byte(vm.STOP)}},
+			},
+		}
+		signer = types.LatestSigner(config)
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 3, func(i int, b *BlockGen) {
+		b.SetCoinbase(common.Address{0xcb})
+		b.SetParentBeaconRoot(common.Hash{byte(i + 1)})
+		for _, to := range []*common.Address{&counter, {0xaa}} {
+			b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+				Nonce:    b.TxNonce(sender),
+				To:       to,
+				Value:    big.NewInt(1),
+				Gas:      100000,
+				GasPrice: b.header.BaseFee,
+			}))
+		}
+	})
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Failed to import blocks: %v", err)
+	}
+	for _, block := range blocks {
+		witness, err := chain.BuildWitness(block)
+		if err != nil {
+			t.Fatalf("Block %d: failed to build witness: %v", block.NumberU64(), err)
+		}
+		blob, err := rlp.EncodeToBytes(witness)
+		if err != nil {
+			t.Fatalf("Block %d: failed to encode witness: %v", block.NumberU64(), err)
+		}
+		witness = new(stateless.Witness)
+		if err := rlp.DecodeBytes(blob, witness); err != nil {
+			t.Fatalf("Block %d: failed to decode witness: %v", block.NumberU64(), err)
+		}
+		header := block.Header()
+		header.Root, header.ReceiptHash = common.Hash{}, common.Hash{}
+
+		stateRoot, receiptRoot, err := ExecuteStateless(config, vm.Config{}, types.NewBlockWithHeader(header).WithBody(*block.Body()), witness)
+		if err != nil {
+			t.Fatalf("Block %d: stateless execution failed: %v", block.NumberU64(), err)
+		}
+		if stateRoot != block.Root() {
+			t.Errorf("Block %d: state root mismatch: have %x, want %x", block.NumberU64(), stateRoot, block.Root())
+		}
+		if receiptRoot != block.ReceiptHash() {
+			t.Errorf("Block %d: receipt root mismatch: have %x, want %x", block.NumberU64(), receiptRoot, block.ReceiptHash())
+		}
+	}
+}
+
+// Tests that the composition of witnesses is analyzed and attributed to the
+// transactions accessing the state.
+func TestWitnessStats(t *testing.T) {
+	var (
+		config  = params.MergedTestChainConfig
+		engine  = beacon.New(ethash.NewFaker())
+		key, _  = crypto.GenerateKey()
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+		counter = common.HexToAddress("0xc0")
+		code    = []byte{byte(vm.PUSH1), 0, byte(vm.SLOAD), byte(vm.PUSH1), 1, byte(vm.ADD), byte(vm.PUSH1), 0, byte(vm.SSTORE), byte(vm.STOP)}
+		gspec   = &Genesis{
+			Config: config,
+			Alloc: types.GenesisAlloc{
+				sender:                    {Balance: big.NewInt(params.Ether)},
+				params.BeaconRootsAddress: {Code: params.BeaconRootsCode},
+				// slot0 += 1
+				counter: {Code: code, Storage: map[common.Hash]common.Hash{{}: {31: 1}, {31: 1}: {31: 1}}},
+			},
+		}
+		signer = types.LatestSigner(config)
+	)
+	for i := 0; i < 32; i++ {
+		gspec.Alloc[common.Address{0xaa, byte(i)}] = types.Account{Balance: big.NewInt(1)}
+	}
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 1, func(i int, b *BlockGen) {
+		b.SetCoinbase(common.Address{0xcb})
+		b.SetParentBeaconRoot(common.Hash{byte(i + 1)})
+		for _, to := range []*common.Address{{0xaa, 0x01}, &counter, &counter} {
+			b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+				Nonce:    b.TxNonce(sender),
+				To:       to,
+				Value:    big.NewInt(1),
+				Gas:      100000,
+				GasPrice: b.header.BaseFee,
+			}))
+		}
+	})
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Failed to import blocks: %v", err)
+	}
+	witness, err := chain.BuildWitness(blocks[0])
+	if err != nil {
+		t.Fatalf("Failed to build witness: %v", err)
+	}
+	stats, err := chain.WitnessStats(blocks[0])
+	if err != nil {
+		t.Fatalf("Failed to analyze witness: %v", err)
+	}
+	blob, _ := rlp.EncodeToBytes(witness)
+	if stats.Size != uint64(len(blob)) {
+		t.Errorf("Witness size mismatch: have %d, want %d", stats.Size, len(blob))
+	}
+	if stats.Headers != 1 || stats.Codes != 2 || stats.CodeBytes != uint64(len(code)+len(params.BeaconRootsCode)) {
+		t.Errorf("Witness content mismatch: headers %d, codes %d, code bytes %d", stats.Headers, stats.Codes, stats.CodeBytes)
+	}
+	// All the nodes must be reachable and counted by depth, the account trie
+	// being deep enough to have multiple levels
+	var reached int
+	for _, n := range append(stats.AccountNodes, stats.StorageNodes...) {
+		reached += n
+	}
+	if stats.Unreachable != 0 || reached != stats.Nodes {
+		t.Errorf("Witness nodes mismatch: total %d, reached %d, unreachable %d", stats.Nodes, reached, stats.Unreachable)
+	}
+	if len(stats.AccountNodes) < 2 || stats.AccountNodes[0] != 1 || len(stats.StorageNodes) < 2 {
+		t.Errorf("Witness depths mismatch: accounts %v, storage %v", stats.AccountNodes, stats.StorageNodes)
+	}
+	// The system call, the transactions and the post-execution operations are
+	// all accounted for. The first call of the counter pulls in its code, the
+	// second one is already covered.
+	if len(stats.Transactions) != 5 {
+		t.Fatalf("Transaction stats count mismatch: have %d, want 5", len(stats.Transactions))
+	}
+	if tx := stats.Transactions[0]; tx.Codes != 1 || tx.Nodes == 0 {
+		t.Errorf("System call stats mismatch: %+v", tx)
+	}
+	if tx := stats.Transactions[2]; tx.Codes != 1 || tx.CodeBytes != uint64(len(code)) || tx.Nodes == 0 {
+		t.Errorf("First counter call stats mismatch: %+v", tx)
+	}
+	if tx := stats.Transactions[3]; tx.Codes != 0 || tx.Nodes != 0 {
+		t.Errorf("Second counter call stats mismatch: %+v", tx)
+	}
+	var attributed uint64
+	for _, tx := range stats.Transactions {
+		attributed += tx.NodeBytes
+	}
+	if attributed == 0 || attributed > stats.NodeBytes {
+		t.Errorf("Attributed node bytes mismatch: have %d, total %d", attributed, stats.NodeBytes)
+	}
+}
@@ -387,8 +387,7 @@ func (p *BlobPool) Init(gasTip uint64, head *types.Header, reserve txpool.Addres
 			fails = append(fails, id)
 		}
 	}
-	slotter := newSlotter(eip4844.LatestMaxBlobsPerBlock(p.chain.Config()))
-	store, err := billy.Open(billy.Options{Path: queuedir, Repair: true}, slotter, index)
+	store, err := openStore(queuedir, eip4844.LatestMaxBlobsPerBlock(p.chain.Config()), index)
 	if err != nil {
 		return err
 	}
@@ -1220,14 +1219,18 @@ func (p *BlobPool) Get(hash common.Hash) *types.Transaction {
 	return item
 }
 
-// GetBlobs returns a number of blobs are proofs for the given versioned hashes.
+// GetBlobs returns a number of blobs and proofs for the given versioned hashes.
 // This is a utility method for the engine API, enabling consensus clients to
 // retrieve blobs from the pools directly instead of the network.
-func (p *BlobPool) GetBlobs(vhashes []common.Hash) ([]*kzg4844.Blob, []*kzg4844.Proof) {
+//
+// The version selects the proof format to return: a single blob proof for
+// version 0 or the cell proofs for version 1. Blobs whose sidecar is stored in
+// a different format are not returned.
+func (p *BlobPool) GetBlobs(vhashes []common.Hash, version byte) ([]*kzg4844.Blob, [][]kzg4844.Proof) {
 	// Create a map of the blob hash to indices for faster fills
 	var (
 		blobs  = make([]*kzg4844.Blob, len(vhashes))
-		proofs = make([]*kzg4844.Proof, len(vhashes))
+		proofs = make([][]kzg4844.Proof, len(vhashes))
 	)
 	index := make(map[common.Hash]int)
 	for i, vhash := range vhashes {
@@ -1262,11 +1265,26 @@ func (p *BlobPool) GetBlobs(vhashes []common.Hash) ([]*kzg4844.Blob, []*kzg4844.
 		}
 		// Fill anything requested, not just the current versioned hash
 		sidecar := item.BlobTxSidecar()
+		if sidecar.Version != version {
+			continue
+		}
 		for j, blobhash := range item.BlobHashes() {
-			if idx, ok := index[blobhash]; ok {
-				blobs[idx] = &sidecar.Blobs[j]
-				proofs[idx] = &sidecar.Proofs[j]
+			idx, ok := index[blobhash]
+			if !ok {
+				continue
 			}
+			switch version {
+			case types.BlobSidecarVersion0:
+				proofs[idx] = []kzg4844.Proof{sidecar.Proofs[j]}
+			case types.BlobSidecarVersion1:
+				cellProofs, err := sidecar.CellProofsAt(j)
+				if err != nil {
+					log.Error("Cell proofs corrupted for tracked transaction", "id", id, "err", err)
+					continue
+				}
+				proofs[idx] = cellProofs
+			}
+			blobs[idx] = &sidecar.Blobs[j]
 		}
 	}
 	return blobs, proofs
@@ -1280,6 +1298,9 @@ func (p *BlobPool) Add(txs []*types.Transaction, sync bool) []error {
 		errs = make([]error, len(txs))
 	)
 	for i, tx := range txs {
+		if tx, errs[i] = p.convertSidecar(tx); errs[i] != nil {
+			continue
+		}
 		errs[i] = p.add(tx)
 		if errs[i] == nil {
 			adds = append(adds, tx.WithoutBlobTxSidecar())
@@ -1292,6 +1313,67 @@ func (p *BlobPool) Add(txs []*types.Transaction, sync bool) []error {
 	return errs
 }
 
+// AddPrivate is not supported by the blob pool: blob transactions are announced
+// to the network and retrieved on demand, so they cannot be withheld selectively.
+func (p *BlobPool) AddPrivate(txs []*types.Transaction, sync bool) []error {
+	errs := make([]error, len(txs))
+	for i := range errs {
+		errs[i] = txpool.ErrPrivateUnsupported
+	}
+	return errs
+}
+
+// IsPrivate always returns false, since the blob pool does not accept private
+// transactions.
+func (p *BlobPool) IsPrivate(hash common.Hash) bool {
+	return false
+}
+
+// PrivateNonce always reports no private transactions, since the blob pool does
+// not accept any.
+func (p *BlobPool) PrivateNonce(addr common.Address) (uint64, bool) {
+	return 0, false
+}
+
+// convertSidecar converts the legacy sidecar of a blob transaction into the cell
+// proof format (EIP-7594) if Osaka is already active. Computing the cell proofs
+// is expensive, so it is done before acquiring the pool write lock and only after
+// the blobs were checked to match the transaction. The proofs themselves are
+// verified once, when the converted transaction is validated upon insertion.
+func (p *BlobPool) convertSidecar(tx *types.Transaction) (*types.Transaction, error) {
+	sidecar := tx.BlobTxSidecar()
+	if sidecar == nil || sidecar.Version != types.BlobSidecarVersion0 {
+		return tx, nil
+	}
+	p.lock.RLock()
+	head := p.head
+	p.lock.RUnlock()
+
+	if !p.chain.Config().IsOsaka(head.Number, head.Time) {
+		return tx, nil
+	}
+	hashes := tx.BlobHashes()
+	if maxBlobs := eip4844.MaxBlobsPerBlock(p.chain.Config(), head.Time); len(hashes) > maxBlobs {
+		addInvalidMeter.Mark(1)
+		return nil, fmt.Errorf("too many blobs in transaction: have %d, permitted %d", len(hashes), maxBlobs)
+	}
+	if len(sidecar.Blobs) != len(hashes) {
+		addInvalidMeter.Mark(1)
+		return nil, fmt.Errorf("invalid number of %d blobs compared to %d blob hashes", len(sidecar.Blobs), len(hashes))
+	}
+	if err := sidecar.ValidateBlobCommitmentHashes(hashes); err != nil {
+		addInvalidMeter.Mark(1)
+		return nil, err
+	}
+	converted := sidecar.Copy()
+	if err := converted.ToV1(); err != nil {
+		addInvalidMeter.Mark(1)
+		return nil, err
+	}
+	addConvertedMeter.Mark(1)
+	return tx.WithBlobTxSidecar(converted), nil
+}
+
 // add inserts a new blob transaction into the pool if it passes validation (both
 // consensus validity and pool restrictions).
 func (p *BlobPool) add(tx *types.Transaction) (err error) {
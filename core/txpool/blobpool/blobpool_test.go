@@ -406,7 +406,11 @@ func verifyBlobRetrievals(t *testing.T, pool *BlobPool) {
 	for i := range testBlobVHashes {
 		copy(hashes[i][:], testBlobVHashes[i][:])
 	}
-	blobs, proofs := pool.GetBlobs(hashes)
+	version := types.BlobSidecarVersion0
+	if pool.chain.Config().IsOsaka(pool.head.Number, pool.head.Time) {
+		version = types.BlobSidecarVersion1
+	}
+	blobs, proofs := pool.GetBlobs(hashes, version)
 
 	// Cross validate what we received vs what we wanted
 	if len(blobs) != len(hashes) || len(proofs) != len(hashes) {
@@ -422,10 +426,22 @@ func verifyBlobRetrievals(t *testing.T, pool *BlobPool) {
 			continue
 		}
 		// Item retrieved, make sure it matches the expectation
-		if *blobs[i] != *testBlobs[i] || *proofs[i] != testBlobProofs[i] {
-			t.Errorf("retrieved blob or proof mismatch: item %d, hash %x", i, hash)
+		if *blobs[i] != *testBlobs[i] {
+			t.Errorf("retrieved blob mismatch: item %d, hash %x", i, hash)
 			continue
 		}
+		switch version {
+		case types.BlobSidecarVersion0:
+			if len(proofs[i]) != 1 || proofs[i][0] != testBlobProofs[i] {
+				t.Errorf("retrieved proof mismatch: item %d, hash %x", i, hash)
+				continue
+			}
+		case types.BlobSidecarVersion1:
+			if len(proofs[i]) != kzg4844.CellProofsPerBlob {
+				t.Errorf("retrieved cell proof count mismatch: item %d, hash %x, have %d", i, hash, len(proofs[i]))
+				continue
+			}
+		}
 		delete(known, hash)
 	}
 	for hash := range known {
@@ -974,7 +990,7 @@ func TestOpenCap(t *testing.T) {
 
 		keep = []common.Address{addr1, addr3}
 		drop = []common.Address{addr2}
-		size = uint64(2 * (txAvgSize + blobSize))
+		size = uint64(2 * (txAvgSize + blobSize + txBlobOverhead))
 	)
 	store.Put(blob1)
 	store.Put(blob2)
@@ -983,7 +999,7 @@ func TestOpenCap(t *testing.T) {
 
 	// Verify pool capping twice: first by reducing the data cap, then restarting
 	// with a high cap to ensure everything was persisted previously
-	for _, datacap := range []uint64{2 * (txAvgSize + blobSize), 100 * (txAvgSize + blobSize)} {
+	for _, datacap := range []uint64{2 * uint64(txAvgSize+blobSize+txBlobOverhead), 100 * uint64(txAvgSize+blobSize+txBlobOverhead)} {
 		// Create a blob pool out of the pre-seeded data, but cap it to 2 blob transaction
 		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
 		statedb.AddBalance(addr1, uint256.NewInt(1_000_000_000), tracing.BalanceChangeUnspecified)
@@ -1034,6 +1050,50 @@ func TestOpenCap(t *testing.T) {
 // of the pool is increased. This would happen during a client release where a
 // new fork is added with a max blob count higher than the previous fork. We
 // want to make sure transactions a persisted between those runs.
+// Tests that a blob pool without a data directory runs in memory, leaving the
+// shelves and legacy directory in the working directory untouched.
+func TestOpenWithoutDatadir(t *testing.T) {
+	dir := t.TempDir()
+
+	legacy, err := billy.Open(billy.Options{Path: dir}, newLegacySlotter(testMaxBlobsPerBlock), nil)
+	if err != nil {
+		t.Fatalf("failed to open legacy store: %v", err)
+	}
+	legacy.Close()
+	os.MkdirAll(filepath.Join(dir, "legacy"), 0700)
+
+	shelves, _ := os.ReadDir(dir)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	chain := &testBlockChain{
+		config:  params.MainnetChainConfig,
+		basefee: uint256.NewInt(params.InitialBaseFee),
+		blobfee: uint256.NewInt(params.BlobTxMinBlobGasprice),
+		statedb: statedb,
+	}
+	pool := New(Config{}, chain)
+	if err := pool.Init(1, chain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("failed to create blob pool: %v", err)
+	}
+	pool.Close()
+
+	if files, _ := os.ReadDir(dir); len(files) != len(shelves) {
+		t.Fatalf("working directory modified: have %d files, want %d", len(files), len(shelves))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "legacy")); err != nil {
+		t.Fatalf("legacy directory removed: %v", err)
+	}
+}
+
 func TestChangingSlotterSize(t *testing.T) {
 	//log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(os.Stderr, log.LevelTrace, true)))
 
@@ -1588,6 +1648,79 @@ func TestAdd(t *testing.T) {
 	}
 }
 
+// Tests that legacy sidecars are converted into cell proof sidecars when being
+// inserted into the pool after Osaka, and that cell proof sidecars are rejected
+// before it.
+func TestAddConvertsLegacySidecar(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+	)
+	newPool := func(osaka bool) (*BlobPool, *params.ChainConfig) {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		statedb.AddBalance(addr, uint256.NewInt(1_000_000_000), tracing.BalanceChangeUnspecified)
+		statedb.Commit(0, true, false)
+
+		config := *params.MainnetChainConfig
+		config.BlobScheduleConfig = params.DefaultBlobSchedule
+		if osaka {
+			config.PragueTime = config.CancunTime
+			config.OsakaTime = config.CancunTime
+		}
+		chain := &testBlockChain{
+			config:  &config,
+			basefee: uint256.NewInt(1050),
+			blobfee: uint256.NewInt(105),
+			statedb: statedb,
+		}
+		pool := New(Config{Datadir: t.TempDir()}, chain)
+		if err := pool.Init(1, chain.CurrentBlock(), makeAddressReserver()); err != nil {
+			t.Fatalf("failed to create blob pool: %v", err)
+		}
+		return pool, &config
+	}
+	// Cell proof sidecars must be rejected before Osaka
+	pool, config := newPool(false)
+
+	blobtx := makeUnsignedTxWithTestBlob(0, 1, 1000, 100, 0)
+	if err := blobtx.Sidecar.ToV1(); err != nil {
+		t.Fatalf("failed to convert sidecar: %v", err)
+	}
+	tx := types.MustSignNewTx(key, types.LatestSigner(config), blobtx)
+	if err := pool.Add([]*types.Transaction{tx}, true)[0]; err == nil {
+		t.Fatalf("cell proof sidecar accepted before osaka")
+	}
+	pool.Close()
+
+	// Legacy sidecars must be converted after Osaka
+	pool, config = newPool(true)
+	defer pool.Close()
+
+	tx = types.MustSignNewTx(key, types.LatestSigner(config), makeUnsignedTxWithTestBlob(0, 1, 1000, 100, 0))
+	if err := pool.Add([]*types.Transaction{tx}, true)[0]; err != nil {
+		t.Fatalf("failed to add legacy sidecar transaction: %v", err)
+	}
+	pooled := pool.Get(tx.Hash())
+	if pooled == nil {
+		t.Fatalf("transaction missing from pool")
+	}
+	if v := pooled.BlobTxSidecar().Version; v != types.BlobSidecarVersion1 {
+		t.Fatalf("pooled sidecar version mismatch: have %d, want %d", v, types.BlobSidecarVersion1)
+	}
+	hashes := []common.Hash{testBlobVHashes[0]}
+	if blobs, _ := pool.GetBlobs(hashes, types.BlobSidecarVersion0); blobs[0] != nil {
+		t.Errorf("legacy proof returned for converted sidecar")
+	}
+	blobs, proofs := pool.GetBlobs(hashes, types.BlobSidecarVersion1)
+	if blobs[0] == nil || *blobs[0] != *testBlobs[0] {
+		t.Fatalf("converted blob missing or mismatching")
+	}
+	if err := kzg4844.VerifyCellProofs([]kzg4844.Blob{*blobs[0]}, []kzg4844.Commitment{testBlobCommits[0]}, proofs[0]); err != nil {
+		t.Fatalf("failed to verify retrieved cell proofs: %v", err)
+	}
+	verifyPoolInternals(t, pool)
+}
+
 // fakeBilly is a billy.Database implementation which just drops data on the floor.
 type fakeBilly struct {
 	billy.Database
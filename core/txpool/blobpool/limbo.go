@@ -60,7 +60,7 @@ func newLimbo(datadir string, maxBlobsPerTransaction int) (*limbo, error) {
 			fails = append(fails, id)
 		}
 	}
-	store, err := billy.Open(billy.Options{Path: datadir, Repair: true}, newSlotter(maxBlobsPerTransaction), index)
+	store, err := openStore(datadir, maxBlobsPerTransaction, index)
 	if err != nil {
 		return nil, err
 	}
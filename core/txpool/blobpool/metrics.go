@@ -102,4 +102,5 @@ var (
 	addNoreplaceMeter    = metrics.NewRegisteredMeter("blobpool/add/noreplace", nil)    // Replacement fees or tips too low, neutral
 	addNonExclusiveMeter = metrics.NewRegisteredMeter("blobpool/add/nonexclusive", nil) // Plain transaction from same account exists, reject, neutral
 	addValidMeter        = metrics.NewRegisteredMeter("blobpool/add/valid", nil)        // Valid transaction, add, neutral
+	addConvertedMeter    = metrics.NewRegisteredMeter("blobpool/add/converted", nil)    // Legacy sidecar converted to cell proofs, neutral
 )
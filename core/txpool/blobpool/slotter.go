@@ -16,6 +16,21 @@
 
 package blobpool
 
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/billy"
+)
+
+// txBlobOverhead is the maximum byte size of the data accompanying each blob in
+// a transaction: its versioned hash, its commitment and the EIP-7594 cell proofs,
+// along with their RLP headers.
+const txBlobOverhead = uint32(kzg4844.CellProofsPerBlob*(len(kzg4844.Proof{})+1) + 128)
+
 // newSlotter creates a helper method for the Billy datastore that returns the
 // individual shelf sizes used to store transactions in.
 //
@@ -26,6 +41,21 @@ package blobpool
 // allowed in the current protocol, having an empty shelf is not a relevant use
 // of resources, but it makes stress testing with junk transactions simpler.
 func newSlotter(maxBlobsPerTransaction int) func() (uint32, bool) {
+	slotsize := uint32(txAvgSize)
+	slotsize -= uint32(blobSize + txBlobOverhead) // underflows, it's ok, will overflow back in the first return
+
+	return func() (size uint32, done bool) {
+		slotsize += blobSize + txBlobOverhead
+		finished := slotsize > uint32(maxBlobsPerTransaction)*(blobSize+txBlobOverhead)+txMaxSize
+
+		return slotsize, finished
+	}
+}
+
+// newLegacySlotter creates the shelf sizes used before EIP-7594, when sidecars
+// carried a single proof per blob. It's only used to migrate the transactions
+// stored in the legacy shelves over to the current ones.
+func newLegacySlotter(maxBlobsPerTransaction int) func() (uint32, bool) {
 	slotsize := uint32(txAvgSize)
 	slotsize -= uint32(blobSize) // underflows, it's ok, will overflow back in the first return
 
@@ -36,3 +66,85 @@ func newSlotter(maxBlobsPerTransaction int) func() (uint32, bool) {
 		return slotsize, finished
 	}
 }
+
+// openStore opens the Billy datastore at the given path with the shelf sizes of
+// the current slotter, migrating any data left in the shelves of the legacy one.
+func openStore(path string, maxBlobsPerTransaction int, onData billy.OnDataFn) (billy.Database, error) {
+	if err := migrateShelves(path, maxBlobsPerTransaction); err != nil {
+		return nil, err
+	}
+	return billy.Open(billy.Options{Path: path, Repair: true}, newSlotter(maxBlobsPerTransaction), onData)
+}
+
+// migrateShelves moves the data stored in the shelves of the legacy slotter into
+// the shelves of the current one.
+//
+// The legacy shelves are moved aside into a subdirectory first, which is only
+// deleted once all the data is moved over. An interrupted migration is resumed
+// on the next startup, the data entries duplicated by it are dropped when the
+// store is indexed.
+func migrateShelves(path string, maxBlobsPerTransaction int) error {
+	// In-memory stores have no shelves to migrate, and the empty path would be
+	// resolved against the working directory.
+	if path == "" {
+		return nil
+	}
+	current := make(map[uint32]bool)
+	for slotter := newSlotter(maxBlobsPerTransaction); ; {
+		size, done := slotter()
+		current[size] = true
+		if done {
+			break
+		}
+	}
+	var legacy []string
+	for slotter := newLegacySlotter(maxBlobsPerTransaction); ; {
+		size, done := slotter()
+		if !current[size] {
+			name := fmt.Sprintf("bkt_%08d.bag", size)
+			if _, err := os.Stat(filepath.Join(path, name)); err == nil {
+				legacy = append(legacy, name)
+			}
+		}
+		if done {
+			break
+		}
+	}
+	legacydir := filepath.Join(path, "legacy")
+	if _, err := os.Stat(legacydir); len(legacy) == 0 && os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.MkdirAll(legacydir, 0700); err != nil {
+		return err
+	}
+	for _, name := range legacy {
+		if err := os.Rename(filepath.Join(path, name), filepath.Join(legacydir, name)); err != nil {
+			return err
+		}
+	}
+	// Move all the data over from the legacy shelves into the current ones
+	store, err := billy.Open(billy.Options{Path: path, Repair: true}, newSlotter(maxBlobsPerTransaction), nil)
+	if err != nil {
+		return err
+	}
+	var migrated, dropped int
+	move := func(id uint64, size uint32, data []byte) {
+		if _, err := store.Put(data); err != nil {
+			log.Warn("Dropping unmigratable blob pool entry", "id", id, "size", len(data), "err", err)
+			dropped++
+			return
+		}
+		migrated++
+	}
+	old, err := billy.Open(billy.Options{Path: legacydir, Repair: true}, newLegacySlotter(maxBlobsPerTransaction), move)
+	if err != nil {
+		store.Close()
+		return err
+	}
+	old.Close()
+	if err := store.Close(); err != nil {
+		return err
+	}
+	log.Info("Migrated blob pool shelves", "path", path, "migrated", migrated, "dropped", dropped)
+	return os.RemoveAll(legacydir)
+}
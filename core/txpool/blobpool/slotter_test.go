@@ -16,7 +16,22 @@
 
 package blobpool
 
-import "testing"
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/billy"
+	"github.com/holiman/uint256"
+)
 
 // Tests that the slotter creates the expected database shelves.
 func TestNewSlotter(t *testing.T) {
@@ -33,21 +48,21 @@ func TestNewSlotter(t *testing.T) {
 	}
 	// Compare the database shelves to the expected ones
 	want := []uint32{
-		0*blobSize + txAvgSize,  // 0 blob + some expected tx infos
-		1*blobSize + txAvgSize,  // 1 blob + some expected tx infos
-		2*blobSize + txAvgSize,  // 2 blob + some expected tx infos (could be fewer blobs and more tx data)
-		3*blobSize + txAvgSize,  // 3 blob + some expected tx infos (could be fewer blobs and more tx data)
-		4*blobSize + txAvgSize,  // 4 blob + some expected tx infos (could be fewer blobs and more tx data)
-		5*blobSize + txAvgSize,  // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
-		6*blobSize + txAvgSize,  // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
-		7*blobSize + txAvgSize,  // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
-		8*blobSize + txAvgSize,  // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
-		9*blobSize + txAvgSize,  // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
-		10*blobSize + txAvgSize, // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
-		11*blobSize + txAvgSize, // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
-		12*blobSize + txAvgSize, // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
-		13*blobSize + txAvgSize, // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
-		14*blobSize + txAvgSize, // 1-6 blobs + unexpectedly large tx infos >= 4 blobs + max tx metadata size
+		0*(blobSize+txBlobOverhead) + txAvgSize,  // 0 blob + some expected tx infos
+		1*(blobSize+txBlobOverhead) + txAvgSize,  // 1 blob + some expected tx infos
+		2*(blobSize+txBlobOverhead) + txAvgSize,  // 2 blob + some expected tx infos (could be fewer blobs and more tx data)
+		3*(blobSize+txBlobOverhead) + txAvgSize,  // 3 blob + some expected tx infos (could be fewer blobs and more tx data)
+		4*(blobSize+txBlobOverhead) + txAvgSize,  // 4 blob + some expected tx infos (could be fewer blobs and more tx data)
+		5*(blobSize+txBlobOverhead) + txAvgSize,  // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
+		6*(blobSize+txBlobOverhead) + txAvgSize,  // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
+		7*(blobSize+txBlobOverhead) + txAvgSize,  // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
+		8*(blobSize+txBlobOverhead) + txAvgSize,  // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
+		9*(blobSize+txBlobOverhead) + txAvgSize,  // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
+		10*(blobSize+txBlobOverhead) + txAvgSize, // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
+		11*(blobSize+txBlobOverhead) + txAvgSize, // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
+		12*(blobSize+txBlobOverhead) + txAvgSize, // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
+		13*(blobSize+txBlobOverhead) + txAvgSize, // 1-6 blobs + unexpectedly large tx infos < 4 blobs + max tx metadata size
+		14*(blobSize+txBlobOverhead) + txAvgSize, // 1-6 blobs + unexpectedly large tx infos >= 4 blobs + max tx metadata size
 	}
 	if len(shelves) != len(want) {
 		t.Errorf("shelves count mismatch: have %d, want %d", len(shelves), len(want))
@@ -58,3 +73,105 @@ func TestNewSlotter(t *testing.T) {
 		}
 	}
 }
+
+// makeV1Tx creates a blob transaction with a cell proof sidecar, carrying the
+// given number of blobs and the given amount of calldata.
+func makeV1Tx(blobs int, data int) *types.Transaction {
+	sidecar := &types.BlobTxSidecar{
+		Version: types.BlobSidecarVersion1,
+		Proofs:  make([]kzg4844.Proof, blobs*kzg4844.CellProofsPerBlob),
+	}
+	hashes := make([]common.Hash, blobs)
+	for i := 0; i < blobs; i++ {
+		sidecar.Blobs = append(sidecar.Blobs, kzg4844.Blob{})
+		sidecar.Commitments = append(sidecar.Commitments, kzg4844.Commitment{})
+		hashes[i] = kzg4844.CalcBlobHashV1(sha256.New(), &sidecar.Commitments[i])
+	}
+	key, _ := crypto.GenerateKey()
+	return types.MustSignNewTx(key, types.LatestSigner(params.MainnetChainConfig), &types.BlobTx{
+		ChainID:    uint256.MustFromBig(params.MainnetChainConfig.ChainID),
+		GasTipCap:  uint256.NewInt(1),
+		GasFeeCap:  uint256.NewInt(1),
+		Gas:        21000,
+		BlobFeeCap: uint256.NewInt(1),
+		BlobHashes: hashes,
+		Data:       make([]byte, data),
+		Sidecar:    sidecar,
+	})
+}
+
+// Tests that transactions with cell proof sidecars are stored in the shelves
+// sized for their blob count, and that the largest transactions admitted by the
+// pool can be stored for every blob count.
+func TestSlotterV1Sidecars(t *testing.T) {
+	store, err := billy.Open(billy.Options{Path: t.TempDir()}, newSlotter(testMaxBlobsPerBlock), nil)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	for blobs := 1; blobs <= testMaxBlobsPerBlock; blobs++ {
+		// A transaction without extra metadata lands in the shelf of its blob count
+		blob, _ := rlp.EncodeToBytes(makeV1Tx(blobs, 0))
+		id, err := store.Put(blob)
+		if err != nil {
+			t.Fatalf("failed to store %d blob transaction: %v", blobs, err)
+		}
+		if shelf := int(id >> 28); shelf != blobs {
+			t.Errorf("%d blob transaction: shelf mismatch: have %d, want %d", blobs, shelf, blobs)
+		}
+		// The largest transaction admitted by the pool must be storable
+		tx := makeV1Tx(blobs, 0)
+		if tx.Size() > txMaxSize {
+			continue
+		}
+		data := txMaxSize - int(tx.Size())
+		for tx = makeV1Tx(blobs, data); tx.Size() > txMaxSize; tx = makeV1Tx(blobs, data) {
+			data -= int(tx.Size() - txMaxSize)
+		}
+		blob, _ = rlp.EncodeToBytes(tx)
+		if _, err := store.Put(blob); err != nil {
+			t.Errorf("failed to store largest %d blob transaction (size %d): %v", blobs, tx.Size(), err)
+		}
+	}
+}
+
+// Tests that the data stored in the shelves of the legacy slotter is migrated
+// into the current ones when the store is opened.
+func TestMigrateShelves(t *testing.T) {
+	dir := t.TempDir()
+
+	legacy, err := billy.Open(billy.Options{Path: dir}, newLegacySlotter(testMaxBlobsPerBlock), nil)
+	if err != nil {
+		t.Fatalf("failed to open legacy store: %v", err)
+	}
+	var items [][]byte
+	for blobs := 0; blobs <= testMaxBlobsPerBlock; blobs++ {
+		item := bytes.Repeat([]byte{byte(blobs)}, blobs*blobSize+txAvgSize/2)
+		if _, err := legacy.Put(item); err != nil {
+			t.Fatalf("failed to store legacy item: %v", err)
+		}
+		items = append(items, item)
+	}
+	legacy.Close()
+
+	// Reopen the store, all the legacy data must be retained
+	found := make(map[byte]bool)
+	store, err := openStore(dir, testMaxBlobsPerBlock, func(id uint64, size uint32, data []byte) {
+		found[data[0]] = true
+		if !bytes.Equal(data, items[data[0]]) {
+			t.Errorf("migrated item %d mismatch", data[0])
+		}
+	})
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	if len(found) != len(items) {
+		t.Errorf("migrated item count mismatch: have %d, want %d", len(found), len(items))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "legacy")); !os.IsNotExist(err) {
+		t.Errorf("legacy shelves not removed: %v", err)
+	}
+}
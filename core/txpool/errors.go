@@ -56,4 +56,8 @@ var (
 	// input transaction of non-blob type when a blob transaction from this sender
 	// remains pending (and vice-versa).
 	ErrAlreadyReserved = errors.New("address already reserved")
+
+	// ErrPrivateUnsupported is returned if a transaction is submitted for private
+	// relay to a subpool which cannot withhold it from the network.
+	ErrPrivateUnsupported = errors.New("private submission not supported")
 )
@@ -95,6 +95,10 @@ var (
 	queuedNofundsMeter   = metrics.NewRegisteredMeter("txpool/queued/nofunds", nil)   // Dropped due to out-of-funds
 	queuedEvictionMeter  = metrics.NewRegisteredMeter("txpool/queued/eviction", nil)  // Dropped due to lifetime
 
+	// Metrics for the private transactions
+	privateExpiryMeter  = metrics.NewRegisteredMeter("txpool/private/expiry", nil)  // Dropped due to private lifetime
+	privatePublishMeter = metrics.NewRegisteredMeter("txpool/private/publish", nil) // Published due to private lifetime
+
 	// General tx metrics
 	knownTxMeter       = metrics.NewRegisteredMeter("txpool/known", nil)
 	validTxMeter       = metrics.NewRegisteredMeter("txpool/valid", nil)
@@ -114,6 +118,7 @@ var (
 	pendingGauge = metrics.NewRegisteredGauge("txpool/pending", nil)
 	queuedGauge  = metrics.NewRegisteredGauge("txpool/queued", nil)
 	slotsGauge   = metrics.NewRegisteredGauge("txpool/slots", nil)
+	privateGauge = metrics.NewRegisteredGauge("txpool/private", nil)
 
 	reheapTimer = metrics.NewRegisteredTimer("txpool/reheap", nil)
 )
@@ -150,6 +155,9 @@ type Config struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	PrivateLifetime time.Duration // Maximum amount of time private transactions are withheld from the network
+	PrivatePublish  bool          // Whether to publish private transactions on expiry instead of dropping them
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -166,6 +174,8 @@ var DefaultConfig = Config{
 	GlobalQueue:  1024,
 
 	Lifetime: 3 * time.Hour,
+
+	PrivateLifetime: 30 * time.Minute,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -200,6 +210,10 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultConfig.Lifetime)
 		conf.Lifetime = DefaultConfig.Lifetime
 	}
+	if conf.PrivateLifetime < 1 {
+		log.Warn("Sanitizing invalid txpool private lifetime", "provided", conf.PrivateLifetime, "updated", DefaultConfig.PrivateLifetime)
+		conf.PrivateLifetime = DefaultConfig.PrivateLifetime
+	}
 	return conf
 }
 
@@ -244,6 +258,10 @@ type LegacyPool struct {
 	all     *lookup                      // All transactions to allow lookups
 	priced  *pricedList                  // All transactions sorted by price
 
+	private     map[common.Hash]*privateTx                  // Transactions withheld from the network
+	privateBy   map[common.Address]map[common.Hash]struct{} // Private transactions indexed by sender
+	privateLock sync.RWMutex                                // Lock protecting the private set, nested within mu
+
 	reqResetCh      chan *txpoolResetRequest
 	reqPromoteCh    chan *accountSet
 	queueTxEventCh  chan *types.Transaction
@@ -255,6 +273,13 @@ type LegacyPool struct {
 	changesSinceReorg int // A counter for how many drops we've performed in-between reorg.
 }
 
+// privateTx is the metadata of a transaction withheld from the network.
+type privateTx struct {
+	added time.Time      // Submission time of the transaction
+	from  common.Address // Sender of the transaction
+	nonce uint64         // Nonce of the transaction
+}
+
 type txpoolResetRequest struct {
 	oldHead, newHead *types.Header
 }
@@ -275,6 +300,8 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		queue:           make(map[common.Address]*list),
 		beats:           make(map[common.Address]time.Time),
 		all:             newLookup(),
+		private:         make(map[common.Hash]*privateTx),
+		privateBy:       make(map[common.Address]map[common.Hash]struct{}),
 		reqResetCh:      make(chan *txpoolResetRequest),
 		reqPromoteCh:    make(chan *accountSet),
 		queueTxEventCh:  make(chan *types.Transaction),
@@ -380,10 +407,89 @@ func (pool *LegacyPool) loop() {
 				}
 			}
 			pool.mu.Unlock()
+
+			// Drop or publish any private transactions outliving their privacy
+			pool.expirePrivate()
 		}
 	}
 }
 
+// expirePrivate iterates over the private transactions, forgetting the ones that
+// already left the pool and handling the ones older than the private lifetime:
+// depending on the configuration, they are either dropped or made public.
+func (pool *LegacyPool) expirePrivate() {
+	pool.mu.Lock()
+
+	var expired []*types.Transaction
+	pool.privateLock.Lock()
+	for hash, meta := range pool.private {
+		tx := pool.all.Get(hash)
+		if tx == nil {
+			pool.unmarkPrivate(hash)
+			continue
+		}
+		if time.Since(meta.added) > pool.config.PrivateLifetime {
+			pool.unmarkPrivate(hash)
+			expired = append(expired, tx)
+		}
+	}
+	privateGauge.Update(int64(len(pool.private)))
+	pool.privateLock.Unlock()
+
+	var published []*types.Transaction
+	if pool.config.PrivatePublish {
+		published = pool.privateFollowups(expired)
+	} else {
+		for _, tx := range expired {
+			pool.removeTx(tx.Hash(), true, true)
+		}
+		privateExpiryMeter.Mark(int64(len(expired)))
+	}
+	pool.mu.Unlock()
+
+	// Announce the newly public transactions so the network layer can propagate
+	// them as if they just arrived.
+	if len(published) > 0 {
+		privatePublishMeter.Mark(int64(len(expired)))
+		pool.txFeed.Send(core.NewTxsEvent{Txs: published})
+	}
+}
+
+// privateFollowups extends a set of published private transactions with the
+// pending transactions of the same accounts queued up behind them, which were
+// withheld from the network too, up to the next private transaction. The pool
+// lock must be held.
+func (pool *LegacyPool) privateFollowups(txs []*types.Transaction) []*types.Transaction {
+	var (
+		published = make([]*types.Transaction, 0, len(txs))
+		seen      = make(map[common.Hash]struct{})
+	)
+	for _, tx := range txs {
+		if _, ok := seen[tx.Hash()]; ok {
+			continue
+		}
+		seen[tx.Hash()] = struct{}{}
+		published = append(published, tx)
+
+		from, _ := types.Sender(pool.signer, tx)
+		list := pool.pending[from]
+		if list == nil {
+			continue
+		}
+		for _, next := range list.Flatten() {
+			if next.Nonce() <= tx.Nonce() {
+				continue
+			}
+			if _, ok := seen[next.Hash()]; ok || pool.IsPrivate(next.Hash()) {
+				break
+			}
+			seen[next.Hash()] = struct{}{}
+			published = append(published, next)
+		}
+	}
+	return published
+}
+
 // Close terminates the transaction pool.
 func (pool *LegacyPool) Close() error {
 	// Terminate the pool reorger and return
@@ -525,6 +631,10 @@ func (pool *LegacyPool) Pending(filter txpool.PendingFilter) map[common.Address]
 	if filter.BaseFee != nil {
 		baseFeeBig = filter.BaseFee.ToBig()
 	}
+	if filter.NoPrivateTxs {
+		pool.privateLock.RLock()
+		defer pool.privateLock.RUnlock()
+	}
 	pending := make(map[common.Address][]*txpool.LazyTransaction, len(pool.pending))
 	for addr, list := range pool.pending {
 		txs := list.Flatten()
@@ -538,6 +648,16 @@ func (pool *LegacyPool) Pending(filter txpool.PendingFilter) map[common.Address]
 				}
 			}
 		}
+		// If private transactions are to be hidden, cap the lists at the first
+		// one, since anything after it is not executable without it anyway
+		if filter.NoPrivateTxs && len(pool.private) > 0 {
+			for i, tx := range txs {
+				if _, ok := pool.private[tx.Hash()]; ok {
+					txs = txs[:i]
+					break
+				}
+			}
+		}
 		if len(txs) > 0 {
 			lazies := make([]*txpool.LazyTransaction, len(txs))
 			for i := 0; i < len(txs); i++ {
@@ -960,6 +1080,109 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, sync bool) []error {
 	return errs
 }
 
+// AddPrivate enqueues a batch of transactions into the pool if they are valid,
+// marking them private: they are available for local block production, but must
+// not be propagated to the network until the private lifetime expires.
+//
+// Transactions already known to the pool are not made private retroactively.
+func (pool *LegacyPool) AddPrivate(txs []*types.Transaction, sync bool) []error {
+	// Mark the transactions before insertion, so that the network layer can
+	// already filter out the announcement of their arrival.
+	var (
+		now    = time.Now()
+		marked = make([]bool, len(txs))
+	)
+	pool.privateLock.Lock()
+	for i, tx := range txs {
+		if _, ok := pool.private[tx.Hash()]; ok || pool.all.Get(tx.Hash()) != nil {
+			continue
+		}
+		// Transactions with an invalid signature are rejected by the insertion
+		from, err := types.Sender(pool.signer, tx)
+		if err != nil {
+			continue
+		}
+		pool.markPrivate(tx, from, now)
+		marked[i] = true
+	}
+	pool.privateLock.Unlock()
+
+	// Insert the transactions and unmark any that were rejected
+	errs := pool.Add(txs, sync)
+
+	pool.privateLock.Lock()
+	for i, err := range errs {
+		if marked[i] && err != nil {
+			pool.unmarkPrivate(txs[i].Hash())
+		}
+	}
+	privateGauge.Update(int64(len(pool.private)))
+	pool.privateLock.Unlock()
+
+	return errs
+}
+
+// IsPrivate returns whether a transaction is currently withheld from the network.
+func (pool *LegacyPool) IsPrivate(hash common.Hash) bool {
+	pool.privateLock.RLock()
+	defer pool.privateLock.RUnlock()
+
+	_, ok := pool.private[hash]
+	return ok
+}
+
+// PrivateNonce returns the lowest nonce of the private transactions pooled from
+// an account, if any.
+func (pool *LegacyPool) PrivateNonce(addr common.Address) (uint64, bool) {
+	pool.privateLock.RLock()
+	defer pool.privateLock.RUnlock()
+
+	var (
+		nonce uint64
+		found bool
+	)
+	for hash := range pool.privateBy[addr] {
+		if n := pool.private[hash].nonce; !found || n < nonce {
+			nonce, found = n, true
+		}
+	}
+	return nonce, found
+}
+
+// forgetPrivate removes a transaction from the private set.
+func (pool *LegacyPool) forgetPrivate(hash common.Hash) {
+	pool.privateLock.Lock()
+	defer pool.privateLock.Unlock()
+
+	pool.unmarkPrivate(hash)
+}
+
+// markPrivate adds a transaction to the private set. The private lock must be
+// held.
+func (pool *LegacyPool) markPrivate(tx *types.Transaction, from common.Address, added time.Time) {
+	hash := tx.Hash()
+	pool.private[hash] = &privateTx{added: added, from: from, nonce: tx.Nonce()}
+	if pool.privateBy[from] == nil {
+		pool.privateBy[from] = make(map[common.Hash]struct{})
+	}
+	pool.privateBy[from][hash] = struct{}{}
+}
+
+// unmarkPrivate removes a transaction from the private set, if present. The
+// private lock must be held.
+func (pool *LegacyPool) unmarkPrivate(hash common.Hash) {
+	meta, ok := pool.private[hash]
+	if !ok {
+		return
+	}
+	delete(pool.private, hash)
+	if hashes := pool.privateBy[meta.from]; len(hashes) > 1 {
+		delete(hashes, hash)
+	} else {
+		delete(pool.privateBy, meta.from)
+	}
+}
+
 // addTxsLocked attempts to queue a batch of transactions if they are valid.
 // The transaction pool lock must be held.
 func (pool *LegacyPool) addTxsLocked(txs []*types.Transaction) ([]error, *accountSet) {
@@ -1012,7 +1235,7 @@ func (pool *LegacyPool) get(hash common.Hash) *types.Transaction {
 
 // GetBlobs is not supported by the legacy transaction pool, it is just here to
 // implement the txpool.SubPool interface.
-func (pool *LegacyPool) GetBlobs(vhashes []common.Hash) ([]*kzg4844.Blob, []*kzg4844.Proof) {
+func (pool *LegacyPool) GetBlobs(vhashes []common.Hash, version byte) ([]*kzg4844.Blob, [][]kzg4844.Proof) {
 	return nil, nil
 }
 
@@ -1055,6 +1278,7 @@ func (pool *LegacyPool) removeTx(hash common.Hash, outofbound bool, unreserve bo
 	}
 	// Remove it from the list of known transactions
 	pool.all.Remove(hash)
+	pool.forgetPrivate(hash)
 	if outofbound {
 		pool.priced.Removed(1)
 	}
@@ -1121,6 +1121,118 @@ func TestQueueTimeLimiting(t *testing.T) {
 	}
 }
 
+// Tests that private transactions are hidden from network-facing pending queries
+// and that they are dropped or published once their private lifetime expires.
+func TestPrivateTransactions(t *testing.T) {
+	t.Run("drop", func(t *testing.T) { testPrivateTransactions(t, false) })
+	t.Run("publish", func(t *testing.T) { testPrivateTransactions(t, true) })
+}
+
+func testPrivateTransactions(t *testing.T, publish bool) {
+	// Reduce the eviction interval to a testable amount
+	defer func(old time.Duration) { evictionInterval = old }(evictionInterval)
+	evictionInterval = time.Millisecond * 100
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.PrivateLifetime = time.Second
+	config.PrivatePublish = publish
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	events := make(chan core.NewTxsEvent, 32)
+	sub := pool.txFeed.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	key, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	// Add a public transaction, followed by a private one and another public one
+	// depending on it
+	txs := []*types.Transaction{
+		transaction(0, 100000, key),
+		transaction(1, 100000, key),
+		transaction(2, 100000, key),
+	}
+	if err := pool.addRemoteSync(txs[0]); err != nil {
+		t.Fatalf("failed to add public transaction: %v", err)
+	}
+	if err := pool.AddPrivate([]*types.Transaction{txs[1]}, true)[0]; err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(txs[2]); err != nil {
+		t.Fatalf("failed to add public transaction: %v", err)
+	}
+	if err := validateEvents(events, 3); err != nil {
+		t.Fatalf("original event firing failed: %v", err)
+	}
+	if pool.IsPrivate(txs[0].Hash()) || !pool.IsPrivate(txs[1].Hash()) || pool.IsPrivate(txs[2].Hash()) {
+		t.Fatalf("private markers mismatch")
+	}
+	if nonce, ok := pool.PrivateNonce(crypto.PubkeyToAddress(key.PublicKey)); !ok || nonce != 1 {
+		t.Fatalf("private nonce mismatch: have %d/%t, want %d/%t", nonce, ok, 1, true)
+	}
+	// Re-adding a public transaction privately should not make it private
+	if err := pool.AddPrivate([]*types.Transaction{txs[0]}, true)[0]; !errors.Is(err, txpool.ErrAlreadyKnown) {
+		t.Fatalf("re-adding error mismatch: have %v, want %v", err, txpool.ErrAlreadyKnown)
+	}
+	if pool.IsPrivate(txs[0].Hash()) {
+		t.Fatalf("known public transaction made private")
+	}
+	// The miner should see all transactions, the network only the first
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	if have := len(pool.Pending(txpool.PendingFilter{})[from]); have != 3 {
+		t.Fatalf("local pending transactions mismatch: have %d, want %d", have, 3)
+	}
+	if have := len(pool.Pending(txpool.PendingFilter{NoPrivateTxs: true})[from]); have != 1 {
+		t.Fatalf("public pending transactions mismatch: have %d, want %d", have, 1)
+	}
+	// Wait for the private lifetime to expire and check the outcome
+	time.Sleep(config.PrivateLifetime + 2*evictionInterval)
+
+	if pool.IsPrivate(txs[1].Hash()) {
+		t.Fatalf("private transaction not expired")
+	}
+	if _, ok := pool.PrivateNonce(from); ok {
+		t.Fatalf("private nonce reported after expiry")
+	}
+	pool.privateLock.RLock()
+	if len(pool.privateBy) != 0 {
+		t.Fatalf("private sender index not cleaned up: %d senders left", len(pool.privateBy))
+	}
+	pool.privateLock.RUnlock()
+	if publish {
+		if !pool.Has(txs[1].Hash()) {
+			t.Fatalf("published transaction dropped")
+		}
+		// The public transaction withheld behind the private one is published too
+		if err := validateEvents(events, 2); err != nil {
+			t.Fatalf("publish event firing failed: %v", err)
+		}
+		if have := len(pool.Pending(txpool.PendingFilter{NoPrivateTxs: true})[from]); have != 3 {
+			t.Fatalf("public pending transactions mismatch: have %d, want %d", have, 3)
+		}
+	} else {
+		if pool.Has(txs[1].Hash()) {
+			t.Fatalf("expired private transaction not dropped")
+		}
+		pending, queued := pool.Stats()
+		if pending != 1 {
+			t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+		}
+		if queued != 1 {
+			t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 1)
+		}
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that even if the transaction count belonging to a single account goes
 // above some threshold, as long as the transactions are executable, they are
 // accepted.
@@ -84,6 +84,7 @@ type PendingFilter struct {
 
 	OnlyPlainTxs bool // Return only plain EVM transactions (peer-join announces, block space filling)
 	OnlyBlobTxs  bool // Return only blob transactions (block blob-space filling)
+	NoPrivateTxs bool // Skip private transactions and their followups (network announces)
 }
 
 // SubPool represents a specialized transaction pool that lives on its own (e.g.
@@ -124,10 +125,11 @@ type SubPool interface {
 	// Get returns a transaction if it is contained in the pool, or nil otherwise.
 	Get(hash common.Hash) *types.Transaction
 
-	// GetBlobs returns a number of blobs are proofs for the given versioned hashes.
+	// GetBlobs returns a number of blobs and proofs for the given versioned hashes.
 	// This is a utility method for the engine API, enabling consensus clients to
-	// retrieve blobs from the pools directly instead of the network.
-	GetBlobs(vhashes []common.Hash) ([]*kzg4844.Blob, []*kzg4844.Proof)
+	// retrieve blobs from the pools directly instead of the network. The version
+	// selects the sidecar format of the returned proofs.
+	GetBlobs(vhashes []common.Hash, version byte) ([]*kzg4844.Blob, [][]kzg4844.Proof)
 
 	// ValidateTxBasics checks whether a transaction is valid according to the consensus
 	// rules, but does not check state-dependent validation such as sufficient balance.
@@ -140,6 +142,20 @@ type SubPool interface {
 	// to a later point to batch multiple ones together.
 	Add(txs []*types.Transaction, sync bool) []error
 
+	// AddPrivate enqueues a batch of transactions into the pool like Add, but
+	// withholds them from the network until their private lifetime expires. The
+	// transactions are still available for local block production.
+	AddPrivate(txs []*types.Transaction, sync bool) []error
+
+	// IsPrivate returns whether a transaction is currently withheld from the
+	// network.
+	IsPrivate(hash common.Hash) bool
+
+	// PrivateNonce returns the lowest nonce of the private transactions pooled
+	// from an account, if any. Later transactions of the account depend on the
+	// withheld one, so they must not be propagated either.
+	PrivateNonce(addr common.Address) (uint64, bool)
+
 	// Pending retrieves all currently processable transactions, grouped by origin
 	// account and sorted by nonce.
 	//
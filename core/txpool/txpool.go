@@ -299,6 +299,32 @@ func (p *TxPool) Has(hash common.Hash) bool {
 	return false
 }
 
+// IsPrivate returns whether a pooled transaction is currently withheld from the
+// network.
+func (p *TxPool) IsPrivate(hash common.Hash) bool {
+	for _, subpool := range p.subpools {
+		if subpool.IsPrivate(hash) {
+			return true
+		}
+	}
+	return false
+}
+
+// PrivateNonce returns the lowest nonce of the private transactions pooled from
+// an account across all subpools, if any.
+func (p *TxPool) PrivateNonce(addr common.Address) (uint64, bool) {
+	var (
+		nonce uint64
+		found bool
+	)
+	for _, subpool := range p.subpools {
+		if n, ok := subpool.PrivateNonce(addr); ok && (!found || n < nonce) {
+			nonce, found = n, true
+		}
+	}
+	return nonce, found
+}
+
 // Get returns a transaction if it is contained in the pool, or nil otherwise.
 func (p *TxPool) Get(hash common.Hash) *types.Transaction {
 	for _, subpool := range p.subpools {
@@ -309,16 +335,18 @@ func (p *TxPool) Get(hash common.Hash) *types.Transaction {
 	return nil
 }
 
-// GetBlobs returns a number of blobs are proofs for the given versioned hashes.
+// GetBlobs returns a number of blobs and proofs for the given versioned hashes.
 // This is a utility method for the engine API, enabling consensus clients to
 // retrieve blobs from the pools directly instead of the network.
-func (p *TxPool) GetBlobs(vhashes []common.Hash) ([]*kzg4844.Blob, []*kzg4844.Proof) {
+//
+// The version selects the sidecar format of the returned proofs.
+func (p *TxPool) GetBlobs(vhashes []common.Hash, version byte) ([]*kzg4844.Blob, [][]kzg4844.Proof) {
 	for _, subpool := range p.subpools {
 		// It's an ugly to assume that only one pool will be capable of returning
 		// anything meaningful for this call, but anythingh else requires merging
 		// partial responses and that's too annoying to do until we get a second
 		// blobpool (probably never).
-		if blobs, proofs := subpool.GetBlobs(vhashes); blobs != nil {
+		if blobs, proofs := subpool.GetBlobs(vhashes, version); blobs != nil {
 			return blobs, proofs
 		}
 	}
@@ -340,6 +368,19 @@ func (p *TxPool) ValidateTxBasics(tx *types.Transaction) error {
 // to the large transaction churn, add may postpone fully integrating the tx
 // to a later point to batch multiple ones together.
 func (p *TxPool) Add(txs []*types.Transaction, sync bool) []error {
+	return p.add(txs, sync, false)
+}
+
+// AddPrivate enqueues a batch of transactions into the pool like Add, but keeps
+// them private: they are included in locally built blocks, but are neither
+// announced nor served to the network until their private lifetime expires.
+func (p *TxPool) AddPrivate(txs []*types.Transaction, sync bool) []error {
+	return p.add(txs, sync, true)
+}
+
+// add splits a batch of transactions between the subpools and inserts them
+// either publicly or privately.
+func (p *TxPool) add(txs []*types.Transaction, sync bool, private bool) []error {
 	// Split the input transactions between the subpools. It shouldn't really
 	// happen that we receive merged batches, but better graceful than strange
 	// errors.
@@ -366,7 +407,11 @@ func (p *TxPool) Add(txs []*types.Transaction, sync bool) []error {
 	// back the errors into the original sort order.
 	errsets := make([][]error, len(p.subpools))
 	for i := 0; i < len(p.subpools); i++ {
-		errsets[i] = p.subpools[i].Add(txsets[i], sync)
+		if private {
+			errsets[i] = p.subpools[i].AddPrivate(txsets[i], sync)
+		} else {
+			errsets[i] = p.subpools[i].Add(txsets[i], sync)
+		}
 	}
 	errs := make([]error, len(txs))
 	for i, split := range splits {
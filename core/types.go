@@ -57,4 +57,6 @@ type ProcessResult struct {
 	Requests [][]byte
 	Logs     []*types.Log
 	GasUsed  uint64
+
+	AccessList *types.BlockAccessList // EIP-7928 access list, if recorded
 }
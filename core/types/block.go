@@ -106,6 +106,10 @@ type Header struct {
 
 	// RequestsHash was added by EIP-7685 and is ignored in legacy headers.
 	RequestsHash *common.Hash `json:"requestsHash" rlp:"optional"`
+
+	// BlockAccessListHash is an experimental extension committing to the EIP-7928
+	// block access list, and is ignored in legacy headers.
+	BlockAccessListHash *common.Hash `json:"blockAccessListHash" rlp:"optional"`
 }
 
 // field type overrides for gencodec
@@ -214,6 +218,10 @@ type Block struct {
 	// that process it.
 	witness *ExecutionWitness
 
+	// accessList is the EIP-7928 access list delivered along with the block,
+	// ahead of its execution. It is not an encoded part of the block body.
+	accessList *BlockAccessList
+
 	// caches
 	hash atomic.Pointer[common.Hash]
 	size atomic.Uint64
@@ -329,6 +337,10 @@ func CopyHeader(h *Header) *Header {
 		cpy.RequestsHash = new(common.Hash)
 		*cpy.RequestsHash = *h.RequestsHash
 	}
+	if h.BlockAccessListHash != nil {
+		cpy.BlockAccessListHash = new(common.Hash)
+		*cpy.BlockAccessListHash = *h.BlockAccessListHash
+	}
 	return &cpy
 }
 
@@ -432,6 +444,9 @@ func (b *Block) BlobGasUsed() *uint64 {
 // ExecutionWitness returns the verkle execution witneess + proof for a block
 func (b *Block) ExecutionWitness() *ExecutionWitness { return b.witness }
 
+// BlockAccessList returns the access list delivered along with the block, if any.
+func (b *Block) BlockAccessList() *BlockAccessList { return b.accessList }
+
 // Size returns the true RLP encoded storage size of the block, either by encoding
 // and returning it, or returning a previously cached value.
 func (b *Block) Size() uint64 {
@@ -495,6 +510,7 @@ func (b *Block) WithSeal(header *Header) *Block {
 		uncles:       b.uncles,
 		withdrawals:  b.withdrawals,
 		witness:      b.witness,
+		accessList:   b.accessList,
 	}
 }
 
@@ -507,6 +523,7 @@ func (b *Block) WithBody(body Body) *Block {
 		uncles:       make([]*Header, len(body.Uncles)),
 		withdrawals:  slices.Clone(body.Withdrawals),
 		witness:      b.witness,
+		accessList:   b.accessList,
 	}
 	for i := range body.Uncles {
 		block.uncles[i] = CopyHeader(body.Uncles[i])
@@ -521,6 +538,20 @@ func (b *Block) WithWitness(witness *ExecutionWitness) *Block {
 		uncles:       b.uncles,
 		withdrawals:  b.withdrawals,
 		witness:      witness,
+		accessList:   b.accessList,
+	}
+}
+
+// WithBlockAccessList returns a copy of the block with the given access list
+// attached, to be used as a hint when executing the block.
+func (b *Block) WithBlockAccessList(accessList *BlockAccessList) *Block {
+	return &Block{
+		header:       b.header,
+		transactions: b.transactions,
+		uncles:       b.uncles,
+		withdrawals:  b.withdrawals,
+		witness:      b.witness,
+		accessList:   accessList,
 	}
 }
 
@@ -0,0 +1,102 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/holiman/uint256"
+)
+
+// BlockAccessList is the set of accounts and storage slots accessed during the
+// execution of a block, along with the values they were modified to, as defined
+// by EIP-7928. Changes are attributed to the index of the transaction making
+// them, index 0 being the pre-execution system calls and len(txs)+1 being the
+// post-execution operations.
+//
+// All lists are sorted: accounts by address, slots by key and changes by index.
+type BlockAccessList struct {
+	Accounts []AccountAccess `json:"accounts"`
+}
+
+// AccountAccess is the access record of a single account within a block.
+type AccountAccess struct {
+	Address        common.Address  `json:"address"`
+	StorageChanges []SlotChanges   `json:"storageChanges"`
+	StorageReads   []common.Hash   `json:"storageReads"`
+	BalanceChanges []BalanceChange `json:"balanceChanges"`
+	NonceChanges   []NonceChange   `json:"nonceChanges"`
+	CodeChanges    []CodeChange    `json:"codeChanges"`
+}
+
+// SlotChanges is the list of modifications of a single storage slot.
+type SlotChanges struct {
+	Slot    common.Hash     `json:"slot"`
+	Changes []StorageChange `json:"changes"`
+}
+
+// StorageChange is the value of a storage slot after a transaction.
+type StorageChange struct {
+	TxIndex hexutil.Uint64 `json:"txIndex"`
+	Value   common.Hash    `json:"value"`
+}
+
+// BalanceChange is the balance of an account after a transaction.
+type BalanceChange struct {
+	TxIndex hexutil.Uint64 `json:"txIndex"`
+	Balance *uint256.Int   `json:"balance"`
+}
+
+type balanceChangeJSON struct {
+	TxIndex hexutil.Uint64 `json:"txIndex"`
+	Balance *hexutil.U256  `json:"balance"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding the balance as a hex quantity.
+func (c BalanceChange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(balanceChangeJSON{TxIndex: c.TxIndex, Balance: (*hexutil.U256)(c.Balance)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *BalanceChange) UnmarshalJSON(input []byte) error {
+	var dec balanceChangeJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	c.TxIndex, c.Balance = dec.TxIndex, (*uint256.Int)(dec.Balance)
+	return nil
+}
+
+// NonceChange is the nonce of an account after a transaction.
+type NonceChange struct {
+	TxIndex hexutil.Uint64 `json:"txIndex"`
+	Nonce   hexutil.Uint64 `json:"nonce"`
+}
+
+// CodeChange is the code of an account after a transaction.
+type CodeChange struct {
+	TxIndex hexutil.Uint64 `json:"txIndex"`
+	Code    hexutil.Bytes  `json:"code"`
+}
+
+// Hash returns the keccak256 hash of the RLP encoding of the access list, which
+// the block header commits to.
+func (bal *BlockAccessList) Hash() common.Hash {
+	return rlpHash(bal)
+}
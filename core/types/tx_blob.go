@@ -53,13 +53,34 @@ type BlobTx struct {
 	S *uint256.Int
 }
 
+const (
+	// BlobSidecarVersion0 is the legacy sidecar format, carrying a single KZG
+	// proof for every blob (EIP-4844).
+	BlobSidecarVersion0 = byte(0)
+
+	// BlobSidecarVersion1 is the sidecar format carrying a KZG proof for every
+	// cell of the extended blob (EIP-7594).
+	BlobSidecarVersion1 = byte(1)
+)
+
 // BlobTxSidecar contains the blobs of a blob transaction.
 type BlobTxSidecar struct {
+	Version     byte                 // Version of the sidecar, determining the proof format
 	Blobs       []kzg4844.Blob       // Blobs needed by the blob pool
 	Commitments []kzg4844.Commitment // Commitments needed by the blob pool
 	Proofs      []kzg4844.Proof      // Proofs needed by the blob pool
 }
 
+// NewBlobTxSidecar initialises the BlobTxSidecar object with the provided parameters.
+func NewBlobTxSidecar(version byte, blobs []kzg4844.Blob, commitments []kzg4844.Commitment, proofs []kzg4844.Proof) *BlobTxSidecar {
+	return &BlobTxSidecar{
+		Version:     version,
+		Blobs:       blobs,
+		Commitments: commitments,
+		Proofs:      proofs,
+	}
+}
+
 // BlobHashes computes the blob hashes of the given blobs.
 func (sc *BlobTxSidecar) BlobHashes() []common.Hash {
 	hasher := sha256.New()
@@ -70,6 +91,56 @@ func (sc *BlobTxSidecar) BlobHashes() []common.Hash {
 	return h
 }
 
+// CellProofsAt returns the cell proofs for the blob with the given index. It is
+// only valid to call on version 1 sidecars.
+func (sc *BlobTxSidecar) CellProofsAt(idx int) ([]kzg4844.Proof, error) {
+	if sc.Version != BlobSidecarVersion1 {
+		return nil, fmt.Errorf("cell proofs unavailable in sidecar version %d", sc.Version)
+	}
+	if idx < 0 || idx >= len(sc.Blobs) {
+		return nil, fmt.Errorf("blob index %d out of range [0, %d)", idx, len(sc.Blobs))
+	}
+	start := idx * kzg4844.CellProofsPerBlob
+	end := start + kzg4844.CellProofsPerBlob
+	if end > len(sc.Proofs) {
+		return nil, fmt.Errorf("insufficient cell proofs: have %d, want %d", len(sc.Proofs), end)
+	}
+	return sc.Proofs[start:end], nil
+}
+
+// ToV1 converts a version 0 sidecar into version 1 in place by replacing the
+// per-blob proofs with cell proofs. It is a noop for version 1 sidecars. This
+// method does not verify the blobs against the commitments.
+func (sc *BlobTxSidecar) ToV1() error {
+	if sc.Version == BlobSidecarVersion1 {
+		return nil
+	}
+	if sc.Version != BlobSidecarVersion0 {
+		return fmt.Errorf("unknown sidecar version %d", sc.Version)
+	}
+	proofs := make([]kzg4844.Proof, 0, len(sc.Blobs)*kzg4844.CellProofsPerBlob)
+	for i := range sc.Blobs {
+		cellProofs, err := kzg4844.ComputeCellProofs(&sc.Blobs[i])
+		if err != nil {
+			return err
+		}
+		proofs = append(proofs, cellProofs...)
+	}
+	sc.Version = BlobSidecarVersion1
+	sc.Proofs = proofs
+	return nil
+}
+
+// Copy returns a deep copy of the sidecar.
+func (sc *BlobTxSidecar) Copy() *BlobTxSidecar {
+	return &BlobTxSidecar{
+		Version:     sc.Version,
+		Blobs:       append([]kzg4844.Blob(nil), sc.Blobs...),
+		Commitments: append([]kzg4844.Commitment(nil), sc.Commitments...),
+		Proofs:      append([]kzg4844.Proof(nil), sc.Proofs...),
+	}
+}
+
 // encodedSize computes the RLP size of the sidecar elements. This does NOT return the
 // encoded size of the BlobTxSidecar, it's just a helper for tx.Size().
 func (sc *BlobTxSidecar) encodedSize() uint64 {
@@ -83,7 +154,11 @@ func (sc *BlobTxSidecar) encodedSize() uint64 {
 	for i := range sc.Proofs {
 		proofs += rlp.BytesSize(sc.Proofs[i][:])
 	}
-	return rlp.ListSize(blobs) + rlp.ListSize(commitments) + rlp.ListSize(proofs)
+	size := rlp.ListSize(blobs) + rlp.ListSize(commitments) + rlp.ListSize(proofs)
+	if sc.Version != BlobSidecarVersion0 {
+		size += uint64(rlp.IntSize(uint64(sc.Version)))
+	}
+	return size
 }
 
 // ValidateBlobCommitmentHashes checks whether the given hashes correspond to the
@@ -102,7 +177,8 @@ func (sc *BlobTxSidecar) ValidateBlobCommitmentHashes(hashes []common.Hash) erro
 	return nil
 }
 
-// blobTxWithBlobs is used for encoding of transactions when blobs are present.
+// blobTxWithBlobs is used for encoding of transactions when blobs are present
+// in the legacy (version 0) sidecar format.
 type blobTxWithBlobs struct {
 	BlobTx      *BlobTx
 	Blobs       []kzg4844.Blob
@@ -110,6 +186,17 @@ type blobTxWithBlobs struct {
 	Proofs      []kzg4844.Proof
 }
 
+// blobTxWithBlobsV1 is used for encoding of transactions when blobs are present
+// in a versioned sidecar format. The version is inserted right after the tx to
+// allow distinguishing it from the legacy format.
+type blobTxWithBlobsV1 struct {
+	BlobTx      *BlobTx
+	Version     byte
+	Blobs       []kzg4844.Blob
+	Commitments []kzg4844.Commitment
+	Proofs      []kzg4844.Proof
+}
+
 // copy creates a deep copy of the transaction data and initializes all fields.
 func (tx *BlobTx) copy() TxData {
 	cpy := &BlobTx{
@@ -157,11 +244,7 @@ func (tx *BlobTx) copy() TxData {
 		cpy.S.Set(tx.S)
 	}
 	if tx.Sidecar != nil {
-		cpy.Sidecar = &BlobTxSidecar{
-			Blobs:       append([]kzg4844.Blob(nil), tx.Sidecar.Blobs...),
-			Commitments: append([]kzg4844.Commitment(nil), tx.Sidecar.Commitments...),
-			Proofs:      append([]kzg4844.Proof(nil), tx.Sidecar.Proofs...),
-		}
+		cpy.Sidecar = tx.Sidecar.Copy()
 	}
 	return cpy
 }
@@ -215,8 +298,19 @@ func (tx *BlobTx) withSidecar(sideCar *BlobTxSidecar) *BlobTx {
 }
 
 func (tx *BlobTx) encode(b *bytes.Buffer) error {
-	if tx.Sidecar == nil {
+	switch {
+	case tx.Sidecar == nil:
 		return rlp.Encode(b, tx)
+
+	case tx.Sidecar.Version != BlobSidecarVersion0:
+		inner := &blobTxWithBlobsV1{
+			BlobTx:      tx,
+			Version:     tx.Sidecar.Version,
+			Blobs:       tx.Sidecar.Blobs,
+			Commitments: tx.Sidecar.Commitments,
+			Proofs:      tx.Sidecar.Proofs,
+		}
+		return rlp.Encode(b, inner)
 	}
 	inner := &blobTxWithBlobs{
 		BlobTx:      tx,
@@ -238,7 +332,7 @@ func (tx *BlobTx) decode(input []byte) error {
 	if err != nil {
 		return err
 	}
-	firstElemKind, _, _, err := rlp.Split(outerList)
+	firstElemKind, _, rest, err := rlp.Split(outerList)
 	if err != nil {
 		return err
 	}
@@ -246,7 +340,24 @@ func (tx *BlobTx) decode(input []byte) error {
 	if firstElemKind != rlp.List {
 		return rlp.DecodeBytes(input, tx)
 	}
-	// It's a tx with blobs.
+	// It's a tx with blobs. The legacy sidecar format is followed by the list
+	// of blobs, whereas the versioned format is followed by the version byte.
+	secondElemKind, _, _, err := rlp.Split(rest)
+	if err != nil {
+		return err
+	}
+	if secondElemKind != rlp.List {
+		var inner blobTxWithBlobsV1
+		if err := rlp.DecodeBytes(input, &inner); err != nil {
+			return err
+		}
+		if inner.Version != BlobSidecarVersion1 {
+			return fmt.Errorf("unsupported sidecar version %d", inner.Version)
+		}
+		*tx = *inner.BlobTx
+		tx.Sidecar = NewBlobTxSidecar(inner.Version, inner.Blobs, inner.Commitments, inner.Proofs)
+		return nil
+	}
 	var inner blobTxWithBlobs
 	if err := rlp.DecodeBytes(input, &inner); err != nil {
 		return err
@@ -74,6 +74,46 @@ func TestBlobTxSize(t *testing.T) {
 	}
 }
 
+// This test verifies that version 1 sidecars survive an encoding roundtrip and
+// are distinguishable from legacy ones.
+func TestBlobTxSidecarV1Encoding(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	blobtx := createEmptyBlobTxInner(true)
+	if err := blobtx.Sidecar.ToV1(); err != nil {
+		t.Fatalf("failed to convert sidecar: %v", err)
+	}
+	if n := len(blobtx.Sidecar.Proofs); n != kzg4844.CellProofsPerBlob {
+		t.Fatalf("cell proof count mismatch: have %d, want %d", n, kzg4844.CellProofsPerBlob)
+	}
+	tx := MustSignNewTx(key, NewCancunSigner(blobtx.ChainID.ToBig()), blobtx)
+
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	if size := tx.Size(); size != uint64(len(enc)) {
+		t.Errorf("wrong size with v1 sidecar: %d, encoded length: %d", size, len(enc))
+	}
+	dec := new(Transaction)
+	if err := dec.UnmarshalBinary(enc); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if dec.Hash() != tx.Hash() {
+		t.Errorf("tx hash mismatch: have %x, want %x", dec.Hash(), tx.Hash())
+	}
+	sidecar := dec.BlobTxSidecar()
+	if sidecar == nil || sidecar.Version != BlobSidecarVersion1 {
+		t.Fatalf("decoded sidecar version mismatch")
+	}
+	proofs, err := sidecar.CellProofsAt(0)
+	if err != nil {
+		t.Fatalf("failed to retrieve cell proofs: %v", err)
+	}
+	if err := kzg4844.VerifyCellProofs(sidecar.Blobs, sidecar.Commitments, proofs); err != nil {
+		t.Fatalf("failed to verify decoded cell proofs: %v", err)
+	}
+}
+
 var (
 	emptyBlob          = new(kzg4844.Blob)
 	emptyBlobCommit, _ = kzg4844.BlobToCommitment(emptyBlob)
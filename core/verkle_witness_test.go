@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/trie/utils"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/ethereum/go-verkle"
@@ -257,7 +258,7 @@ func TestProcessParentBlockHash(t *testing.T) {
 		db := rawdb.NewMemoryDatabase()
 		cacheConfig := DefaultCacheConfigWithScheme(rawdb.PathScheme)
 		cacheConfig.SnapshotLimit = 0
-		triedb := triedb.NewDatabase(db, cacheConfig.triedbConfig(true))
+		triedb := triedb.NewDatabase(db, cacheConfig.triedbConfig(true, false))
 		statedb, _ := state.New(types.EmptyVerkleHash, state.NewDatabase(triedb, nil))
 		checkBlockHashes(statedb, true)
 	})
@@ -1101,3 +1102,61 @@ func TestProcessVerkleSelfDestructInSameTxWithSelfBeneficiaryAndPrefundedAccount
 		}
 	}
 }
+
+// Tests that a chain storing its state in the binary merkle tree (EIP-7864) is
+// generated and imported consistently.
+func TestProcessBinaryTree(t *testing.T) {
+	var (
+		config     = *testVerkleChainConfig
+		signer     = types.LatestSigner(testVerkleChainConfig)
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender     = crypto.PubkeyToAddress(testKey.PublicKey)
+		counter    = common.HexToAddress("0xc0")
+		// slot0 += 1
+		code = []byte{byte(vm.PUSH1), 0, byte(vm.SLOAD), byte(vm.PUSH1), 1, byte(vm.ADD), byte(vm.PUSH1), 0, byte(vm.SSTORE), byte(vm.STOP)}
+	)
+	config.EnableBinaryTrie = true
+	gspec := &Genesis{
+		Config: &config,
+		Alloc: GenesisAlloc{
+			sender:                       {Balance: big.NewInt(1000000000000000000)},
+			counter:                      {Code: code, Storage: map[common.Hash]common.Hash{{}: {31: 1}}},
+			params.BeaconRootsAddress:    {Nonce: 1, Code: params.BeaconRootsCode, Balance: common.Big0},
+			params.HistoryStorageAddress: {Nonce: 1, Code: params.HistoryStorageCode, Balance: common.Big0},
+		},
+	}
+	_, _, chain, _, _, _ := GenerateVerkleChainWithGenesis(gspec, beacon.New(ethash.NewFaker()), 3, func(i int, gen *BlockGen) {
+		gen.SetPoS()
+		for _, to := range []common.Address{counter, {byte(i + 1)}} {
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(sender), to, big.NewInt(999), 100000, big.NewInt(875000000), nil), signer, testKey)
+			gen.AddTx(tx)
+		}
+	})
+	if gspec.ToBlock().Root() == (common.Hash{}) {
+		t.Fatal("Empty genesis state root")
+	}
+	cacheConfig := DefaultCacheConfigWithScheme(rawdb.PathScheme)
+	cacheConfig.SnapshotLimit = 0
+	blockchain, err := NewBlockChain(rawdb.NewMemoryDatabase(), cacheConfig, gspec, nil, beacon.New(ethash.NewFaker()), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer blockchain.Stop()
+
+	if n, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatalf("Block %d imported with error: %v", n, err)
+	}
+	statedb, err := blockchain.State()
+	if err != nil {
+		t.Fatalf("Failed to open head state: %v", err)
+	}
+	if _, ok := statedb.GetTrie().(*trie.BinaryTrie); !ok {
+		t.Fatalf("Head state stored in %T, want binary tree", statedb.GetTrie())
+	}
+	if have := statedb.GetState(counter, common.Hash{}); have != (common.Hash{31: 4}) {
+		t.Errorf("Counter mismatch: have %x, want 4", have)
+	}
+	if have := statedb.GetBalance(common.Address{3}); have.Uint64() != 999 {
+		t.Errorf("Balance mismatch: have %v, want 999", have)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	witnessSizeHist         = metrics.NewRegisteredHistogram("chain/witness/size", nil, metrics.NewExpDecaySample(1028, 0.015))
+	witnessHeadersHist      = metrics.NewRegisteredHistogram("chain/witness/headers", nil, metrics.NewExpDecaySample(1028, 0.015))
+	witnessCodeBytesHist    = metrics.NewRegisteredHistogram("chain/witness/codes", nil, metrics.NewExpDecaySample(1028, 0.015))
+	witnessNodeBytesHist    = metrics.NewRegisteredHistogram("chain/witness/nodes", nil, metrics.NewExpDecaySample(1028, 0.015))
+	witnessAccountDepthHist = metrics.NewRegisteredHistogram("chain/witness/depth/account", nil, metrics.NewExpDecaySample(1028, 0.015))
+	witnessStorageDepthHist = metrics.NewRegisteredHistogram("chain/witness/depth/storage", nil, metrics.NewExpDecaySample(1028, 0.015))
+	witnessTxBytesHist      = metrics.NewRegisteredHistogram("chain/witness/tx", nil, metrics.NewExpDecaySample(1028, 0.015))
+)
+
+// witnessAccessRecorder splits the state locations accessed while executing a
+// block by transaction, in order to attribute the content of the witness to
+// them. The indexing follows the block access lists: 0 is the pre-execution
+// system calls and len(txs)+1 the post-execution operations.
+type witnessAccessRecorder struct {
+	statedb *state.StateDB
+	txs     int // Number of transactions started so far
+	index   int // Index the current accesses are attributed to
+
+	accounts map[common.Address]struct{}                 // Accounts already attributed
+	slots    map[common.Address]map[common.Hash]struct{} // Slots already attributed
+	accesses []*stateless.Accesses
+}
+
+// newWitnessAccessRecorder creates a recorder for a block executed on top of
+// the given state, enabling the read tracking of the latter.
+func newWitnessAccessRecorder(statedb *state.StateDB) *witnessAccessRecorder {
+	statedb.TrackReads()
+	return &witnessAccessRecorder{
+		statedb:  statedb,
+		accounts: make(map[common.Address]struct{}),
+		slots:    make(map[common.Address]map[common.Hash]struct{}),
+	}
+}
+
+// hooks returns the tracing hooks feeding the recorder, chained after the given
+// ones, if any.
+func (r *witnessAccessRecorder) hooks(inner *tracing.Hooks) *tracing.Hooks {
+	hooks := new(tracing.Hooks)
+	if inner != nil {
+		*hooks = *inner
+	}
+	hooks.OnTxStart = func(vm *tracing.VMContext, tx *types.Transaction, from common.Address) {
+		if inner != nil && inner.OnTxStart != nil {
+			inner.OnTxStart(vm, tx, from)
+		}
+		r.flush()
+		r.txs++
+		r.index = r.txs
+	}
+	hooks.OnTxEnd = func(receipt *types.Receipt, err error) {
+		if inner != nil && inner.OnTxEnd != nil {
+			inner.OnTxEnd(receipt, err)
+		}
+		r.flush()
+		r.index = r.txs + 1
+	}
+	return hooks
+}
+
+// flush attributes the locations read since the last flush to the current index.
+func (r *witnessAccessRecorder) flush() {
+	for len(r.accesses) <= r.index {
+		r.accesses = append(r.accesses, &stateless.Accesses{Slots: make(map[common.Address][]common.Hash)})
+	}
+	var (
+		access = r.accesses[r.index]
+		reads  = r.statedb.Reads()
+	)
+	for _, addr := range reads.Accounts() {
+		if _, ok := r.accounts[addr]; !ok {
+			r.accounts[addr] = struct{}{}
+			access.Accounts = append(access.Accounts, addr)
+		}
+		slots, ok := r.slots[addr]
+		if !ok {
+			slots = make(map[common.Hash]struct{})
+			r.slots[addr] = slots
+		}
+		for _, slot := range reads.Slots(addr) {
+			if _, ok := slots[slot]; !ok {
+				slots[slot] = struct{}{}
+				access.Slots[addr] = append(access.Slots[addr], slot)
+			}
+		}
+	}
+}
+
+// finish attributes the accesses of the post-execution operations and returns
+// the accesses of the block.
+func (r *witnessAccessRecorder) finish() []*stateless.Accesses {
+	r.flush()
+	for len(r.accesses) < r.txs+2 {
+		r.accesses = append(r.accesses, &stateless.Accesses{})
+	}
+	return r.accesses
+}
+
+// reportWitnessStats updates the witness metrics with the stats of a block.
+func reportWitnessStats(stats *stateless.Stats) {
+	witnessSizeHist.Update(int64(stats.Size))
+	witnessHeadersHist.Update(int64(stats.Headers))
+	witnessCodeBytesHist.Update(int64(stats.CodeBytes))
+	witnessNodeBytesHist.Update(int64(stats.NodeBytes))
+
+	for depth, n := range stats.AccountNodes {
+		for i := 0; i < n; i++ {
+			witnessAccountDepthHist.Update(int64(depth))
+		}
+	}
+	for depth, n := range stats.StorageNodes {
+		for i := 0; i < n; i++ {
+			witnessStorageDepthHist.Update(int64(depth))
+		}
+	}
+	for _, tx := range stats.Transactions {
+		witnessTxBytesHist.Update(int64(tx.NodeBytes + tx.CodeBytes))
+	}
+}
@@ -20,6 +20,7 @@ package kzg4844
 import (
 	"embed"
 	"errors"
+	"fmt"
 	"hash"
 	"reflect"
 	"sync/atomic"
@@ -75,6 +76,10 @@ func (p Proof) MarshalText() ([]byte, error) {
 	return hexutil.Bytes(p[:]).MarshalText()
 }
 
+// CellProofsPerBlob is the number of cell proofs a blob is extended into for
+// EIP-7594 data availability sampling.
+const CellProofsPerBlob = 128
+
 // Point is a BLS field element.
 type Point [32]byte
 
@@ -96,10 +101,14 @@ func UseCKZG(use bool) error {
 	// Initializing the library can take 2-4 seconds - and can potentially crash
 	// on CKZG and non-ADX CPUs - so might as well do it now and don't wait until
 	// a crypto operation is actually needed live.
+	//
+	// The Go library additionally precomputes the cell proof tables, which takes
+	// a few more seconds but cannot crash, so run that in the background. Any
+	// crypto operation arriving in the meantime will wait for it to finish.
 	if use {
 		ckzgIniter.Do(ckzgInit)
 	} else {
-		gokzgIniter.Do(gokzgInit)
+		go gokzgIniter.Do(gokzgInit)
 	}
 	return nil
 }
@@ -149,6 +158,31 @@ func VerifyBlobProof(blob *Blob, commitment Commitment, proof Proof) error {
 	return gokzgVerifyBlobProof(blob, commitment, proof)
 }
 
+// ComputeCellProofs returns the KZG cell proofs that are used to verify the blob
+// against the commitment, one for each cell of the extended blob (EIP-7594).
+func ComputeCellProofs(blob *Blob) ([]Proof, error) {
+	if useCKZG.Load() {
+		return ckzgComputeCellProofs(blob)
+	}
+	return gokzgComputeCellProofs(blob)
+}
+
+// VerifyCellProofs verifies a batch of cell proofs against the given blobs and
+// commitments. The proofs are expected to be flattened, CellProofsPerBlob of them
+// for each blob, in the order of the blobs.
+func VerifyCellProofs(blobs []Blob, commitments []Commitment, proofs []Proof) error {
+	if len(blobs) != len(commitments) {
+		return fmt.Errorf("blob and commitment count mismatch: %d != %d", len(blobs), len(commitments))
+	}
+	if len(proofs) != len(blobs)*CellProofsPerBlob {
+		return fmt.Errorf("cell proof count mismatch: have %d, want %d", len(proofs), len(blobs)*CellProofsPerBlob)
+	}
+	if useCKZG.Load() {
+		return ckzgVerifyCellProofs(blobs, commitments, proofs)
+	}
+	return gokzgVerifyCellProofs(blobs, commitments, proofs)
+}
+
 // CalcBlobHashV1 calculates the 'versioned blob hash' of a commitment.
 // The given hasher must be a sha256 hash instance, otherwise the result will be invalid!
 func CalcBlobHashV1(hasher hash.Hash, commit *Commitment) (vh [32]byte) {
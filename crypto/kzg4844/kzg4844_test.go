@@ -21,7 +21,7 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
-	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	gokzg4844 "github.com/crate-crypto/go-eth-kzg"
 )
 
 func randFieldElement() [32]byte {
@@ -94,6 +94,52 @@ func testKZGWithBlob(t *testing.T, ckzg bool) {
 	}
 }
 
+func TestCKZGCells(t *testing.T)  { testKZGCells(t, true) }
+func TestGoKZGCells(t *testing.T) { testKZGCells(t, false) }
+func testKZGCells(t *testing.T, ckzg bool) {
+	if ckzg && !ckzgAvailable {
+		t.Skip("CKZG unavailable in this test build")
+	}
+	defer func(old bool) { useCKZG.Store(old) }(useCKZG.Load())
+	useCKZG.Store(ckzg)
+
+	blob1 := randBlob()
+	blob2 := randBlob()
+
+	commitment1, err := BlobToCommitment(blob1)
+	if err != nil {
+		t.Fatalf("failed to create KZG commitment from blob: %v", err)
+	}
+	commitment2, err := BlobToCommitment(blob2)
+	if err != nil {
+		t.Fatalf("failed to create KZG commitment from blob: %v", err)
+	}
+	proofs1, err := ComputeCellProofs(blob1)
+	if err != nil {
+		t.Fatalf("failed to create KZG cell proofs for blob: %v", err)
+	}
+	if len(proofs1) != CellProofsPerBlob {
+		t.Fatalf("cell proof count mismatch: have %d, want %d", len(proofs1), CellProofsPerBlob)
+	}
+	proofs2, err := ComputeCellProofs(blob2)
+	if err != nil {
+		t.Fatalf("failed to create KZG cell proofs for blob: %v", err)
+	}
+	var (
+		blobs       = []Blob{*blob1, *blob2}
+		commitments = []Commitment{commitment1, commitment2}
+		proofs      = append(proofs1, proofs2...)
+	)
+	if err := VerifyCellProofs(blobs, commitments, proofs); err != nil {
+		t.Fatalf("failed to verify KZG cell proofs: %v", err)
+	}
+	// Swap two proofs around and ensure verification fails
+	proofs[0], proofs[CellProofsPerBlob] = proofs[CellProofsPerBlob], proofs[0]
+	if err := VerifyCellProofs(blobs, commitments, proofs); err == nil {
+		t.Fatalf("invalid KZG cell proofs verified")
+	}
+}
+
 func BenchmarkCKZGBlobToCommitment(b *testing.B)  { benchmarkBlobToCommitment(b, true) }
 func BenchmarkGoKZGBlobToCommitment(b *testing.B) { benchmarkBlobToCommitment(b, false) }
 func benchmarkBlobToCommitment(b *testing.B, ckzg bool) {
@@ -26,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
@@ -33,6 +34,9 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
 )
 
 // DebugAPI is the collection of Ethereum full node APIs for debugging the
@@ -265,6 +269,117 @@ func storageRangeAt(statedb *state.StateDB, root common.Hash, address common.Add
 	return result, nil
 }
 
+// RangeProofMaxResults is the maximum number of trie entries returned per range
+// proof call.
+const RangeProofMaxResults = 4096
+
+// RangeProofResult is the result of a debug_getAccountRange or a
+// debug_getStorageRangeProof API call. It holds a contiguous range of trie
+// entries along with the merkle proofs of its boundaries, which can be verified
+// against the root with trie.VerifyRangeProof.
+type RangeProofResult struct {
+	Root    common.Hash     `json:"root"`    // Root hash of the trie holding the range
+	Keys    []common.Hash   `json:"keys"`    // Hashed keys of the range, in ascending order
+	Values  []hexutil.Bytes `json:"values"`  // RLP encoded values, as stored in the trie
+	Proof   []hexutil.Bytes `json:"proof"`   // Proof nodes of the range origin and last key
+	NextKey *common.Hash    `json:"nextKey"` // nil if Keys includes the last key in the trie
+}
+
+// GetAccountRange returns a contiguous range of the accounts in the state of
+// the given block from the given hashed origin onwards, along with the merkle
+// proofs of the range boundaries.
+func (api *DebugAPI) GetAccountRange(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, origin common.Hash, maxResults int) (*RangeProofResult, error) {
+	header, err := api.eth.APIBackend.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block %v not found", blockNrOrHash)
+	}
+	tr, err := trie.NewStateTrie(trie.StateTrieID(header.Root), api.eth.blockchain.TrieDB())
+	if err != nil {
+		return nil, err
+	}
+	return rangeProof(tr, header.Root, origin, maxResults)
+}
+
+// GetStorageRangeProof returns a contiguous range of the storage slots of the
+// given account in the state of the given block from the given hashed origin
+// onwards, along with the merkle proofs of the range boundaries. Paging through
+// the whole storage allows to download it trustlessly.
+func (api *DebugAPI) GetStorageRangeProof(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, address common.Address, origin common.Hash, maxResults int) (*RangeProofResult, error) {
+	header, err := api.eth.APIBackend.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block %v not found", blockNrOrHash)
+	}
+	return storageRangeProof(api.eth.blockchain.TrieDB(), header.Root, address, origin, maxResults)
+}
+
+func storageRangeProof(db *triedb.Database, root common.Hash, address common.Address, origin common.Hash, maxResults int) (*RangeProofResult, error) {
+	tr, err := trie.NewStateTrie(trie.StateTrieID(root), db)
+	if err != nil {
+		return nil, err
+	}
+	account, err := tr.GetAccount(address)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil || account.Root == types.EmptyRootHash {
+		// Empty storage, nothing to prove
+		return &RangeProofResult{Root: types.EmptyRootHash, Keys: []common.Hash{}, Values: []hexutil.Bytes{}, Proof: []hexutil.Bytes{}}, nil
+	}
+	id := trie.StorageTrieID(root, crypto.Keccak256Hash(address.Bytes()), account.Root)
+	st, err := trie.NewStateTrie(id, db)
+	if err != nil {
+		return nil, err
+	}
+	return rangeProof(st, account.Root, origin, maxResults)
+}
+
+// rangeProof collects a contiguous range of the trie entries from the origin
+// onwards, and proves the origin and the last collected key.
+func rangeProof(tr *trie.StateTrie, root common.Hash, origin common.Hash, maxResults int) (*RangeProofResult, error) {
+	if maxResults > RangeProofMaxResults || maxResults <= 0 {
+		maxResults = RangeProofMaxResults
+	}
+	nodeIt, err := tr.NodeIterator(origin[:])
+	if err != nil {
+		return nil, err
+	}
+	var (
+		it     = trie.NewIterator(nodeIt)
+		result = &RangeProofResult{Root: root, Keys: []common.Hash{}, Values: []hexutil.Bytes{}, Proof: []hexutil.Bytes{}}
+	)
+	for len(result.Keys) < maxResults && it.Next() {
+		result.Keys = append(result.Keys, common.BytesToHash(it.Key))
+		result.Values = append(result.Values, common.CopyBytes(it.Value))
+	}
+	// Add the 'next key' so clients can continue downloading.
+	if it.Next() {
+		next := common.BytesToHash(it.Key)
+		result.NextKey = &next
+	}
+	if it.Err != nil {
+		return nil, it.Err
+	}
+	proof := trienode.NewProofSet()
+	if err := tr.Prove(origin[:], proof); err != nil {
+		return nil, err
+	}
+	if len(result.Keys) > 0 {
+		if err := tr.Prove(result.Keys[len(result.Keys)-1][:], proof); err != nil {
+			return nil, err
+		}
+	}
+	for _, node := range proof.List() {
+		result.Proof = append(result.Proof, node)
+	}
+	return result, nil
+}
+
 // GetModifiedAccountsByNumber returns all accounts that have changed between the
 // two blocks specified. A change is defined as a difference in nonce, balance,
 // code hash, or storage hash.
@@ -443,3 +558,70 @@ func (api *DebugAPI) GetTrieFlushInterval() (string, error) {
 	}
 	return api.eth.blockchain.GetTrieFlushInterval().String(), nil
 }
+
+// PruneState starts deleting the stale state in the background. Only the state
+// of the current head, of the blocks imported afterwards and of the genesis is
+// retained. It is only supported by the hash-based scheme.
+func (api *DebugAPI) PruneState() error {
+	return api.eth.PruneState()
+}
+
+// GetBlockAccessList returns the EIP-7928 access list recorded while importing
+// the given block. Access lists are only recorded if enabled, or if the block
+// header commits to one.
+func (api *DebugAPI) GetBlockAccessList(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.BlockAccessList, error) {
+	header, err := api.eth.APIBackend.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block %v not found", blockNrOrHash)
+	}
+	bal := rawdb.ReadBlockAccessList(api.eth.chainDb, header.Hash(), header.Number.Uint64())
+	if bal == nil {
+		return nil, fmt.Errorf("access list of block %#x not available", header.Hash())
+	}
+	return bal, nil
+}
+
+// WitnessStats re-executes the given block to build the witness required to
+// execute it statelessly, and returns its size and composition breakdown. The
+// state of the parent block must be available.
+func (api *DebugAPI) WitnessStats(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*stateless.Stats, error) {
+	block, err := api.eth.APIBackend.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %v not found", blockNrOrHash)
+	}
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not executable")
+	}
+	return api.eth.blockchain.WitnessStats(block)
+}
+
+// TrieScrubResult is the result of a debug_trieScrubReport call.
+type TrieScrubResult struct {
+	*pathdb.ScrubReport
+	Progress    float64  `json:"progress"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// TrieScrubReport returns the progress and the findings of the trie node
+// integrity scrubber, along with the suggested repairs if corruptions were
+// found. It is only supported by the path-based scheme.
+func (api *DebugAPI) TrieScrubReport() (*TrieScrubResult, error) {
+	report, err := api.eth.blockchain.TrieDB().ScrubReport()
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, errors.New("trie scrubber was never run")
+	}
+	return &TrieScrubResult{
+		ScrubReport: report,
+		Progress:    report.Progress(),
+		Suggestions: report.Suggestions(),
+	}, nil
+}
@@ -31,6 +31,9 @@ import (
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/holiman/uint256"
 )
@@ -224,3 +227,75 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+func TestStorageRangeProof(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mdb    = rawdb.NewMemoryDatabase()
+		tdb    = triedb.NewDatabase(mdb, nil)
+		db     = state.NewDatabase(tdb, nil)
+		sdb, _ = state.New(types.EmptyRootHash, db)
+		addr   = common.Address{0x01}
+		slots  = make(map[common.Hash]common.Hash)
+	)
+	for i := 0; i < 100; i++ {
+		key, val := common.Hash{byte(i), 0x01}, common.Hash{0x01, byte(i)}
+		sdb.SetState(addr, key, val)
+		slots[crypto.Keccak256Hash(key.Bytes())] = val
+	}
+	sdb.SetBalance(common.Address{0x02}, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+	root, _ := sdb.Commit(0, false, false)
+
+	// Page through the whole storage, verifying every range
+	var (
+		origin common.Hash
+		seen   int
+	)
+	for {
+		result, err := storageRangeProof(tdb, root, addr, origin, 30)
+		if err != nil {
+			t.Fatalf("Failed to retrieve storage range: %v", err)
+		}
+		keys := make([][]byte, len(result.Keys))
+		vals := make([][]byte, len(result.Values))
+		for i, key := range result.Keys {
+			keys[i], vals[i] = key.Bytes(), result.Values[i]
+
+			_, content, _, err := rlp.Split(vals[i])
+			if err != nil {
+				t.Fatalf("Invalid slot value: %v", err)
+			}
+			if common.BytesToHash(content) != slots[key] {
+				t.Fatalf("Slot %x mismatch: have %x, want %x", key, content, slots[key])
+			}
+		}
+		proof := trienode.NewProofSet()
+		for _, node := range result.Proof {
+			proof.Put(crypto.Keccak256(node), node)
+		}
+		more, err := trie.VerifyRangeProof(result.Root, origin[:], keys, vals, proof)
+		if err != nil {
+			t.Fatalf("Failed to verify storage range from %x: %v", origin, err)
+		}
+		if more != (result.NextKey != nil) {
+			t.Fatalf("Continuation mismatch: proof %t, next key %v", more, result.NextKey)
+		}
+		seen += len(keys)
+		if result.NextKey == nil {
+			break
+		}
+		origin = *result.NextKey
+	}
+	if seen != len(slots) {
+		t.Fatalf("Slot count mismatch: have %d, want %d", seen, len(slots))
+	}
+	// Accounts without storage have an empty range
+	result, err := storageRangeProof(tdb, root, common.Address{0x02}, common.Hash{}, 30)
+	if err != nil {
+		t.Fatalf("Failed to retrieve empty storage range: %v", err)
+	}
+	if result.Root != types.EmptyRootHash || len(result.Keys) != 0 || result.NextKey != nil {
+		t.Fatalf("Unexpected empty storage range: %v", result)
+	}
+}
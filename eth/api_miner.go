@@ -19,7 +19,10 @@ package eth
 import (
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/miner"
 )
 
 // MinerAPI provides an API to control the miner.
@@ -56,3 +59,102 @@ func (api *MinerAPI) SetGasLimit(gasLimit hexutil.Uint64) bool {
 	api.e.Miner().SetGasCeil(uint64(gasLimit))
 	return true
 }
+
+// BundleArgs represents the arguments to submit or simulate a transaction bundle.
+type BundleArgs struct {
+	Txs               []hexutil.Bytes `json:"txs"`
+	BlockNumber       hexutil.Uint64  `json:"blockNumber"`
+	RevertingTxHashes []common.Hash   `json:"revertingTxHashes"`
+}
+
+// toBundle decodes the transactions of the bundle arguments.
+func (args *BundleArgs) toBundle() (*miner.Bundle, error) {
+	bundle := &miner.Bundle{
+		Txs:          make(types.Transactions, len(args.Txs)),
+		BlockNumber:  uint64(args.BlockNumber),
+		RevertingTxs: args.RevertingTxHashes,
+	}
+	for i, input := range args.Txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			return nil, err
+		}
+		bundle.Txs[i] = tx
+	}
+	return bundle, nil
+}
+
+// BundleTxResult is the RPC representation of a bundled transaction's execution.
+type BundleTxResult struct {
+	TxHash   common.Hash    `json:"txHash"`
+	GasUsed  hexutil.Uint64 `json:"gasUsed"`
+	Reverted bool           `json:"reverted"`
+}
+
+// BundleResult is the RPC representation of a bundle simulation outcome.
+type BundleResult struct {
+	BundleHash  common.Hash      `json:"bundleHash"`
+	BlockNumber hexutil.Uint64   `json:"blockNumber"`
+	Profit      *hexutil.Big     `json:"profit"`
+	GasUsed     hexutil.Uint64   `json:"gasUsed"`
+	Txs         []BundleTxResult `json:"txs"`
+	Included    bool             `json:"included"`
+	Error       string           `json:"error,omitempty"`
+}
+
+func newBundleResult(result *miner.BundleResult) *BundleResult {
+	res := &BundleResult{
+		BundleHash:  result.Hash,
+		BlockNumber: hexutil.Uint64(result.BlockNumber),
+		Profit:      (*hexutil.Big)(result.Profit),
+		GasUsed:     hexutil.Uint64(result.GasUsed),
+		Txs:         make([]BundleTxResult, len(result.Txs)),
+		Included:    result.Included,
+	}
+	for i, tx := range result.Txs {
+		res.Txs[i] = BundleTxResult{
+			TxHash:   tx.Hash,
+			GasUsed:  hexutil.Uint64(tx.GasUsed),
+			Reverted: tx.Reverted,
+		}
+	}
+	if result.Err != nil {
+		res.Error = result.Err.Error()
+	}
+	return res
+}
+
+// SendBundle submits a bundle of transactions to be included atomically at the
+// top of the target block. The returned hash can be used to query the outcome
+// of the bundle simulations via GetBundleStatus.
+func (api *MinerAPI) SendBundle(args BundleArgs) (common.Hash, error) {
+	bundle, err := args.toBundle()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return api.e.Miner().AddBundle(bundle)
+}
+
+// CallBundle simulates a bundle of transactions on top of the current chain head
+// without submitting it for inclusion.
+func (api *MinerAPI) CallBundle(args BundleArgs) (*BundleResult, error) {
+	bundle, err := args.toBundle()
+	if err != nil {
+		return nil, err
+	}
+	result, err := api.e.Miner().SimulateBundle(bundle)
+	if err != nil {
+		return nil, err
+	}
+	return newBundleResult(result), nil
+}
+
+// GetBundleStatus returns the outcome of the latest simulation of a submitted
+// bundle during block building, or nil if it was not simulated yet.
+func (api *MinerAPI) GetBundleStatus(hash common.Hash) *BundleResult {
+	result := api.e.Miner().BundleResult(hash)
+	if result == nil {
+		return nil
+	}
+	return newBundleResult(result)
+}
@@ -19,6 +19,7 @@ package eth
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"runtime"
@@ -100,6 +101,10 @@ type Ethereum struct {
 
 	lock sync.RWMutex // Protects the variadic fields (e.g. gas price and etherbase)
 
+	statePruner *pruner.OnlinePruner // State pruner running in the background, if any
+	prunerLock  sync.Mutex           // Protects the state pruner field
+	prunerWg    sync.WaitGroup       // Tracks the lifetime of the state pruner
+
 	shutdownTracker *shutdowncheck.ShutdownTracker // Tracks if and when the node has shutdown ungracefully
 }
 
@@ -149,6 +154,17 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Serve the pruned chain history from the era files of the network, named
+	// the same way as by the history export.
+	if config.HistoryEraDir != "" {
+		network := "unknown"
+		if name, ok := params.NetworkNames[chainConfig.ChainID.String()]; ok {
+			network = name
+		}
+		if chainDb, err = rawdb.NewDatabaseWithEra(chainDb, stack.ResolvePath(config.HistoryEraDir), network); err != nil {
+			return nil, err
+		}
+	}
 	engine, err := ethconfig.CreateConsensusEngine(chainConfig, chainDb)
 	if err != nil {
 		return nil, err
@@ -163,6 +179,20 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		}
 		historyPruningCutoff = prunecfg.BlockNumber
 	}
+	var historyRetention uint64
+	if config.HistoryMode == ethconfig.RollingHistory {
+		if config.HistoryRetention == 0 {
+			return nil, errors.New("rolling history mode requires a non-zero retention")
+		}
+		historyRetention = config.HistoryRetention
+
+		// Transactions can only be indexed while their bodies are available,
+		// so cap the transaction index to the retained history.
+		if config.TransactionHistory == 0 || config.TransactionHistory > historyRetention {
+			log.Warn("Limiting transaction index to retained history", "provided", config.TransactionHistory, "updated", historyRetention)
+			config.TransactionHistory = historyRetention
+		}
+	}
 
 	// Set networkID to chainID by default.
 	networkID := config.NetworkId
@@ -210,14 +240,20 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		cacheConfig = &core.CacheConfig{
 			TrieCleanLimit:       config.TrieCleanCache,
 			TrieCleanNoPrefetch:  config.NoPrefetch,
+			HintPrefetch:         config.HintPrefetch,
 			TrieDirtyLimit:       config.TrieDirtyCache,
 			TrieDirtyDisabled:    config.NoPruning,
 			TrieTimeLimit:        config.TrieTimeout,
 			SnapshotLimit:        config.SnapshotCache,
 			Preimages:            config.Preimages,
 			StateHistory:         config.StateHistory,
+			StateScrub:           config.StateScrub,
 			StateScheme:          scheme,
 			HistoryPruningCutoff: historyPruningCutoff,
+			HistoryRetention:     historyRetention,
+			BlockAccessLists:     config.BlockAccessLists,
+			ParallelExecution:    config.ParallelExecution,
+			ParallelVerify:       config.ParallelExecutionVerify,
 		}
 	)
 	if config.VMTrace != "" {
@@ -373,6 +409,39 @@ func (s *Ethereum) SetSynced()                         { s.handler.enableSyncedF
 func (s *Ethereum) ArchiveMode() bool                  { return s.config.NoPruning }
 func (s *Ethereum) BloomIndexer() *core.ChainIndexer   { return s.bloomIndexer }
 
+// PruneState starts deleting the stale state of a hash-based database in the
+// background, while the node keeps running.
+func (s *Ethereum) PruneState() error {
+	if s.config.NoPruning {
+		return errors.New("state pruning is not available in archive mode")
+	}
+	if !s.Synced() {
+		return errors.New("state pruning is not available while syncing")
+	}
+	s.prunerLock.Lock()
+	defer s.prunerLock.Unlock()
+
+	if s.statePruner != nil {
+		return errors.New("state pruning already in progress")
+	}
+	statePruner, err := pruner.NewOnlinePruner(s.chainDb, s.blockchain, pruner.OnlineDefaults)
+	if err != nil {
+		return err
+	}
+	s.statePruner = statePruner
+	s.prunerWg.Add(1)
+	go func() {
+		defer s.prunerWg.Done()
+		if err := statePruner.Prune(); err != nil {
+			log.Error("Failed to prune state", "err", err)
+		}
+		s.prunerLock.Lock()
+		s.statePruner = nil
+		s.prunerLock.Unlock()
+	}()
+	return nil
+}
+
 // Protocols returns all the currently configured
 // network protocols to start.
 func (s *Ethereum) Protocols() []p2p.Protocol {
@@ -441,6 +510,13 @@ func (s *Ethereum) Stop() error {
 	s.handler.Stop()
 
 	// Then stop everything else.
+	s.prunerLock.Lock()
+	if s.statePruner != nil {
+		s.statePruner.Stop()
+	}
+	s.prunerLock.Unlock()
+	s.prunerWg.Wait()
+
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
 	s.txPool.Close()
@@ -38,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/params/forks"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -94,10 +95,14 @@ var caps = []string{
 	"engine_getPayloadV3",
 	"engine_getPayloadV4",
 	"engine_getBlobsV1",
+	"engine_getBlobsV2",
 	"engine_newPayloadV1",
 	"engine_newPayloadV2",
 	"engine_newPayloadV3",
 	"engine_newPayloadV4",
+	"engine_newPayloadV5",
+	"engine_getInclusionListV1",
+	"engine_updatePayloadWithInclusionListV1",
 	"engine_newPayloadWithWitnessV1",
 	"engine_newPayloadWithWitnessV2",
 	"engine_newPayloadWithWitnessV3",
@@ -535,18 +540,47 @@ func (api *ConsensusAPI) GetBlobsV1(hashes []common.Hash) ([]*engine.BlobAndProo
 	}
 	res := make([]*engine.BlobAndProofV1, len(hashes))
 
-	blobs, proofs := api.eth.TxPool().GetBlobs(hashes)
+	blobs, proofs := api.eth.TxPool().GetBlobs(hashes, types.BlobSidecarVersion0)
 	for i := 0; i < len(blobs); i++ {
 		if blobs[i] != nil {
 			res[i] = &engine.BlobAndProofV1{
 				Blob:  (*blobs[i])[:],
-				Proof: (*proofs[i])[:],
+				Proof: proofs[i][0][:],
 			}
 		}
 	}
 	return res, nil
 }
 
+// GetBlobsV2 returns a blob along with its cell proofs from the transaction pool.
+// Contrary to V1, the response is all or nothing: if any of the requested blobs
+// is unavailable, nil is returned.
+func (api *ConsensusAPI) GetBlobsV2(hashes []common.Hash) ([]*engine.BlobAndProofV2, error) {
+	if len(hashes) > 128 {
+		return nil, engine.TooLargeRequest.With(fmt.Errorf("requested blob count too large: %v", len(hashes)))
+	}
+	res := make([]*engine.BlobAndProofV2, len(hashes))
+
+	blobs, proofs := api.eth.TxPool().GetBlobs(hashes, types.BlobSidecarVersion1)
+	if len(blobs) != len(hashes) {
+		return nil, nil
+	}
+	for i := 0; i < len(blobs); i++ {
+		if blobs[i] == nil {
+			return nil, nil
+		}
+		cellProofs := make([]hexutil.Bytes, len(proofs[i]))
+		for j := range proofs[i] {
+			cellProofs[j] = proofs[i][j][:]
+		}
+		res[i] = &engine.BlobAndProofV2{
+			Blob:       (*blobs[i])[:],
+			CellProofs: cellProofs,
+		}
+	}
+	return res, nil
+}
+
 // NewPayloadV1 creates an Eth1 block, inserts it in the chain, and returns the status of the chain.
 func (api *ConsensusAPI) NewPayloadV1(params engine.ExecutableData) (engine.PayloadStatusV1, error) {
 	if params.Withdrawals != nil {
@@ -625,7 +659,8 @@ func (api *ConsensusAPI) NewPayloadV4(params engine.ExecutableData, versionedHas
 		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil executionRequests post-prague"))
 	}
 
-	if api.eth.BlockChain().Config().LatestFork(params.Timestamp) != forks.Prague {
+	config := api.eth.BlockChain().Config()
+	if config.LatestFork(params.Timestamp) != forks.Prague || config.IsFOCIL(config.LondonBlock, params.Timestamp) {
 		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.UnsupportedFork.With(errors.New("newPayloadV4 must only be called for prague payloads"))
 	}
 	requests := convertRequests(executionRequests)
@@ -635,6 +670,123 @@ func (api *ConsensusAPI) NewPayloadV4(params engine.ExecutableData, versionedHas
 	return api.newPayload(params, versionedHashes, beaconRoot, requests, false)
 }
 
+// NewPayloadV5 is analogous to NewPayloadV4, only it also checks the payload
+// against an inclusion list (EIP-7805). If the payload is valid but omits an
+// includable transaction of the list, INCLUSION_LIST_UNSATISFIED is returned.
+func (api *ConsensusAPI) NewPayloadV5(params engine.ExecutableData, versionedHashes []common.Hash, beaconRoot *common.Hash, executionRequests []hexutil.Bytes, inclusionList []hexutil.Bytes) (engine.PayloadStatusV1, error) {
+	if params.Withdrawals == nil {
+		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil withdrawals post-shanghai"))
+	}
+	if params.ExcessBlobGas == nil {
+		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil excessBlobGas post-cancun"))
+	}
+	if params.BlobGasUsed == nil {
+		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil blobGasUsed post-cancun"))
+	}
+
+	if versionedHashes == nil {
+		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil versionedHashes post-cancun"))
+	}
+	if beaconRoot == nil {
+		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil beaconRoot post-cancun"))
+	}
+	if executionRequests == nil {
+		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil executionRequests post-prague"))
+	}
+	if inclusionList == nil {
+		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil inclusionList post-focil"))
+	}
+
+	config := api.eth.BlockChain().Config()
+	if !config.IsPrague(config.LondonBlock, params.Timestamp) || !config.IsFOCIL(config.LondonBlock, params.Timestamp) {
+		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.UnsupportedFork.With(errors.New("newPayloadV5 must only be called for focil payloads"))
+	}
+	requests := convertRequests(executionRequests)
+	if err := validateRequests(requests); err != nil {
+		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(err)
+	}
+	list, err := decodeInclusionList(inclusionList)
+	if err != nil {
+		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(err)
+	}
+	status, err := api.newPayload(params, versionedHashes, beaconRoot, requests, false)
+	if err != nil || status.Status != engine.VALID {
+		return status, err
+	}
+	switch verdict, err := api.verifyInclusionList(params.BlockHash, list); verdict {
+	case engine.VALID:
+		return status, nil
+	case engine.INCLUSION_LIST_UNSATISFIED:
+		log.Warn("Payload does not satisfy inclusion list", "number", params.Number, "hash", params.BlockHash, "err", err)
+		return engine.PayloadStatusV1{Status: engine.INCLUSION_LIST_UNSATISFIED}, nil
+	default:
+		return engine.PayloadStatusV1{Status: verdict}, nil
+	}
+}
+
+// verifyInclusionList checks a previously imported block against an inclusion
+// list, returning the resulting payload status. If the block or its post-state
+// is not available, the list can't be checked and the payload can't be deemed
+// valid: SYNCING or ACCEPTED is returned respectively.
+func (api *ConsensusAPI) verifyInclusionList(hash common.Hash, list types.Transactions) (string, error) {
+	if len(list) == 0 {
+		return engine.VALID, nil
+	}
+	block := api.eth.BlockChain().GetBlockByHash(hash)
+	if block == nil {
+		return engine.SYNCING, nil
+	}
+	statedb, err := api.eth.BlockChain().StateAt(block.Root())
+	if err != nil {
+		log.Warn("Deferring inclusion list check, state unavailable", "number", block.Number(), "hash", hash, "err", err)
+		return engine.ACCEPTED, nil
+	}
+	if err := core.VerifyInclusionList(api.eth.BlockChain().Config(), block, statedb, list); err != nil {
+		return engine.INCLUSION_LIST_UNSATISFIED, err
+	}
+	return engine.VALID, nil
+}
+
+// GetInclusionListV1 assembles an inclusion list (EIP-7805) of transactions from
+// the local pool for the block to be built on top of the given parent.
+func (api *ConsensusAPI) GetInclusionListV1(parentHash common.Hash) ([]hexutil.Bytes, error) {
+	list, err := api.eth.Miner().BuildInclusionList(parentHash)
+	if err != nil {
+		return nil, engine.InvalidParams.With(err)
+	}
+	enc := make([]hexutil.Bytes, 0, len(list))
+	for _, tx := range list {
+		bin, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, engine.GenericServerError.With(err)
+		}
+		enc = append(enc, bin)
+	}
+	return enc, nil
+}
+
+// UpdatePayloadWithInclusionListV1 sets the inclusion list (EIP-7805) of a payload
+// being built. Its transactions are force-included in the subsequently built
+// versions of the payload, as far as they are valid.
+func (api *ConsensusAPI) UpdatePayloadWithInclusionListV1(payloadID engine.PayloadID, inclusionList []hexutil.Bytes) (*engine.PayloadID, error) {
+	log.Trace("Engine API request received", "method", "UpdatePayloadWithInclusionList", "id", payloadID, "txs", len(inclusionList))
+
+	payload := api.localBlocks.payload(payloadID)
+	if payload == nil {
+		return nil, engine.UnknownPayload
+	}
+	config := api.eth.BlockChain().Config()
+	if !config.IsFOCIL(config.LondonBlock, payload.Timestamp()) {
+		return nil, engine.UnsupportedFork.With(errors.New("inclusion lists are only supported for focil payloads"))
+	}
+	list, err := decodeInclusionList(inclusionList)
+	if err != nil {
+		return nil, engine.InvalidParams.With(err)
+	}
+	payload.UpdateInclusionList(list)
+	return &payloadID, nil
+}
+
 // NewPayloadWithWitnessV1 is analogous to NewPayloadV1, only it also generates
 // and returns a stateless witness after running the payload.
 func (api *ConsensusAPI) NewPayloadWithWitnessV1(params engine.ExecutableData) (engine.PayloadStatusV1, error) {
@@ -1270,6 +1422,29 @@ func convertRequests(hex []hexutil.Bytes) [][]byte {
 	return req
 }
 
+// decodeInclusionList decodes the raw transactions of an inclusion list, making
+// sure it does not exceed the maximum size and contains no blob transactions.
+func decodeInclusionList(raw []hexutil.Bytes) (types.Transactions, error) {
+	var (
+		list = make(types.Transactions, 0, len(raw))
+		size int
+	)
+	for i, enc := range raw {
+		if size += len(enc); size > params.MaxBytesPerInclusionList {
+			return nil, fmt.Errorf("inclusion list exceeds %d bytes", params.MaxBytesPerInclusionList)
+		}
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(enc); err != nil {
+			return nil, fmt.Errorf("invalid inclusion list transaction %d: %v", i, err)
+		}
+		if tx.Type() == types.BlobTxType {
+			return nil, fmt.Errorf("blob transaction %d in inclusion list", i)
+		}
+		list = append(list, tx)
+	}
+	return list, nil
+}
+
 // validateRequests checks that requests are ordered by their type and are not empty.
 func validateRequests(requests [][]byte) error {
 	for i, req := range requests {
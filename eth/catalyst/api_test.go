@@ -35,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
@@ -1801,3 +1802,108 @@ func TestValidateRequests(t *testing.T) {
 		})
 	}
 }
+
+// Tests that inclusion lists are force-included into built payloads, and that
+// payloads omitting includable transactions are reported as such.
+func TestInclusionList(t *testing.T) {
+	genesis, blocks := generateMergeChain(10, true)
+
+	time := blocks[len(blocks)-1].Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	genesis.Config.CancunTime = &time
+	genesis.Config.PragueTime = &time
+	genesis.Config.FOCILTime = &time
+	genesis.Config.BlobScheduleConfig = params.DefaultBlobSchedule
+
+	n, ethservice := startEthService(t, genesis, blocks)
+	defer n.Close()
+
+	var (
+		api    = NewConsensusAPI(ethservice)
+		parent = ethservice.BlockChain().CurrentHeader()
+		signer = types.LatestSigner(ethservice.BlockChain().Config())
+	)
+	statedb, _ := ethservice.BlockChain().StateAt(parent.Root)
+	nonce := statedb.GetNonce(testAddr)
+
+	// Create a transaction which is not known by the local pool
+	tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x01}, big.NewInt(1), params.TxGas, big.NewInt(2*params.InitialBaseFee), nil), signer, testKey)
+	enc, _ := tx.MarshalBinary()
+	list := []hexutil.Bytes{enc}
+
+	build := func(recipient common.Address, list []hexutil.Bytes) *engine.ExecutionPayloadEnvelope {
+		attrs := engine.PayloadAttributes{
+			Timestamp:             parent.Time + 5,
+			SuggestedFeeRecipient: recipient,
+			Withdrawals:           make([]*types.Withdrawal, 0),
+			BeaconRoot:            &common.Hash{42},
+		}
+		resp, err := api.ForkchoiceUpdatedV3(engine.ForkchoiceStateV1{HeadBlockHash: parent.Hash()}, &attrs)
+		if err != nil {
+			t.Fatalf("failed to start payload building: %v", err)
+		}
+		if list != nil {
+			if _, err := api.UpdatePayloadWithInclusionListV1(*resp.PayloadID, list); err != nil {
+				t.Fatalf("failed to update inclusion list: %v", err)
+			}
+		}
+		return api.localBlocks.get(*resp.PayloadID, true)
+	}
+	newPayload := func(envelope *engine.ExecutionPayloadEnvelope, list []hexutil.Bytes) string {
+		requests := make([]hexutil.Bytes, len(envelope.Requests))
+		for i, req := range envelope.Requests {
+			requests[i] = req
+		}
+		status, err := api.NewPayloadV5(*envelope.ExecutionPayload, []common.Hash{}, &common.Hash{42}, requests, list)
+		if err != nil {
+			t.Fatalf("failed to validate payload: %v", err)
+		}
+		return status.Status
+	}
+	// Build a payload with the inclusion list and ensure it contains the transaction
+	withList := build(common.Address{0xaa}, list)
+	if txs := withList.ExecutionPayload.Transactions; len(txs) != 1 || !bytes.Equal(txs[0], enc) {
+		t.Fatalf("inclusion list transaction not included: %d txs", len(txs))
+	}
+	if status := newPayload(withList, list); status != engine.VALID {
+		t.Fatalf("payload status mismatch: have %s, want %s", status, engine.VALID)
+	}
+	// Build a payload without the inclusion list and ensure it's reported
+	withoutList := build(common.Address{0xbb}, nil)
+	if txs := withoutList.ExecutionPayload.Transactions; len(txs) != 0 {
+		t.Fatalf("unexpected transactions included: %d txs", len(txs))
+	}
+	if status := newPayload(withoutList, list); status != engine.INCLUSION_LIST_UNSATISFIED {
+		t.Fatalf("payload status mismatch: have %s, want %s", status, engine.INCLUSION_LIST_UNSATISFIED)
+	}
+	if status := newPayload(withoutList, []hexutil.Bytes{}); status != engine.VALID {
+		t.Fatalf("payload status mismatch: have %s, want %s", status, engine.VALID)
+	}
+	// Ensure the pre-focil method rejects focil payloads
+	if _, err := api.NewPayloadV4(*withList.ExecutionPayload, []common.Hash{}, &common.Hash{42}, []hexutil.Bytes{}); err == nil || err.(*engine.EngineAPIError).ErrorCode() != engine.UnsupportedFork.ErrorCode() {
+		t.Fatalf("focil payload accepted by newPayloadV4: %v", err)
+	}
+	// Ensure the list is not deemed satisfied if it can't be checked
+	if status, _ := api.verifyInclusionList(common.Hash{0x01}, types.Transactions{tx}); status != engine.SYNCING {
+		t.Fatalf("unknown block status mismatch: have %s, want %s", status, engine.SYNCING)
+	}
+	stateless, _ := engine.ExecutableDataToBlockNoHash(*withoutList.ExecutionPayload, nil, &common.Hash{42}, nil)
+	header := stateless.Header()
+	header.Root = common.Hash{0x02}
+	stateless = stateless.WithSeal(header)
+	rawdb.WriteBlock(ethservice.ChainDb(), stateless)
+	if status, _ := api.verifyInclusionList(stateless.Hash(), types.Transactions{tx}); status != engine.ACCEPTED {
+		t.Fatalf("stateless block status mismatch: have %s, want %s", status, engine.ACCEPTED)
+	}
+	// Add the transaction to the pool and ensure it's proposed for inclusion lists
+	if errs := ethservice.TxPool().Add([]*types.Transaction{tx}, true); errs[0] != nil {
+		t.Fatalf("failed to add transaction: %v", errs[0])
+	}
+	proposed, err := api.GetInclusionListV1(parent.Hash())
+	if err != nil {
+		t.Fatalf("failed to get inclusion list: %v", err)
+	}
+	if len(proposed) != 1 || !bytes.Equal(proposed[0], enc) {
+		t.Fatalf("inclusion list mismatch: have %d txs, want 1", len(proposed))
+	}
+}
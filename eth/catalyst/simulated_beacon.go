@@ -38,12 +38,16 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
-const devEpochLength = 32
+const (
+	devEpochLength = 32 // Number of blocks per epoch, used for finalization
+	devSlotTime    = 12 // Seconds per slot when producing blocks on demand
+)
 
 // withdrawalQueue implements a FIFO queue which holds withdrawals that are
 // pending inclusion.
 type withdrawalQueue struct {
 	pending types.Withdrawals
+	script  []types.Withdrawals // Scripted withdrawals for the upcoming blocks
 	mu      sync.Mutex
 	feed    event.Feed
 	subs    event.SubscriptionScope
@@ -61,11 +65,29 @@ func (w *withdrawalQueue) add(withdrawal *types.Withdrawal) error {
 	return nil
 }
 
-// pop dequeues the specified number of withdrawals from the queue.
+// setScript replaces the scripted withdrawals, the entries of which are used,
+// in order, for the upcoming blocks instead of the queued ones.
+func (w *withdrawalQueue) setScript(script []types.Withdrawals) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.script = script
+}
+
+// pop dequeues the specified number of withdrawals from the queue. If there are
+// scripted withdrawals left, the next entry of the script is returned instead.
 func (w *withdrawalQueue) pop(count int) types.Withdrawals {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	if len(w.script) > 0 {
+		popped := w.script[0]
+		w.script = w.script[1:]
+		if popped == nil {
+			popped = types.Withdrawals{}
+		}
+		return popped
+	}
 	count = min(count, len(w.pending))
 	popped := w.pending[0:count]
 	w.pending = w.pending[count:]
@@ -95,11 +117,16 @@ type SimulatedBeacon struct {
 	engineAPI          *ConsensusAPI
 	curForkchoiceState engine.ForkchoiceStateV1
 	lastBlockTime      uint64
+	sealLock           sync.Mutex // lock serializes block production and reorgs
+
+	missedSlots     uint64        // Number of upcoming slots to leave empty
+	forkchoiceDelay time.Duration // Delay between importing a payload and making it canonical
+	scenarioLock    sync.Mutex    // lock gates concurrent access to the simulated edge cases
 }
 
 func payloadVersion(config *params.ChainConfig, time uint64) engine.PayloadVersion {
 	switch config.LatestFork(time) {
-	case forks.Prague, forks.Cancun:
+	case forks.Osaka, forks.Prague, forks.Cancun:
 		return engine.PayloadV3
 	case forks.Paris, forks.Shanghai:
 		return engine.PayloadV2
@@ -158,9 +185,71 @@ func (c *SimulatedBeacon) Stop() error {
 	return nil
 }
 
+// SkipSlots makes the given number of upcoming slots be missed, as if their
+// proposers were offline. In period mode, no blocks are produced for them; on
+// demand, the timestamp of the next block is moved past them.
+func (c *SimulatedBeacon) SkipSlots(slots uint64) {
+	c.scenarioLock.Lock()
+	defer c.scenarioLock.Unlock()
+
+	c.missedSlots += slots
+}
+
+// SetForkchoiceDelay sets the delay between importing a new payload and sending
+// the forkchoice update making it canonical.
+func (c *SimulatedBeacon) SetForkchoiceDelay(delay time.Duration) {
+	c.scenarioLock.Lock()
+	defer c.scenarioLock.Unlock()
+
+	c.forkchoiceDelay = delay
+}
+
+// ScriptWithdrawals sets the withdrawals of the upcoming blocks, one entry of
+// the script per block. Queued withdrawals are only included again once the
+// script is exhausted.
+func (c *SimulatedBeacon) ScriptWithdrawals(script []types.Withdrawals) {
+	c.withdrawals.setScript(script)
+}
+
+// skipSlot reports whether the current slot is to be missed, consuming it.
+func (c *SimulatedBeacon) skipSlot() bool {
+	c.scenarioLock.Lock()
+	defer c.scenarioLock.Unlock()
+
+	if c.missedSlots == 0 {
+		return false
+	}
+	c.missedSlots--
+	return true
+}
+
+// takeMissedSlots returns the number of slots to be missed before the next
+// block, consuming them.
+func (c *SimulatedBeacon) takeMissedSlots() uint64 {
+	c.scenarioLock.Lock()
+	defer c.scenarioLock.Unlock()
+
+	missed := c.missedSlots
+	c.missedSlots = 0
+	return missed
+}
+
+// slotTime returns the number of seconds per slot.
+func (c *SimulatedBeacon) slotTime() uint64 {
+	if c.period > 0 {
+		return c.period
+	}
+	return devSlotTime
+}
+
 // sealBlock initiates payload building for a new block and creates a new block
-// with the completed payload.
-func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal, timestamp uint64) error {
+// with the completed payload. Withdrawals are included from the queue if the
+// block is post-shanghai.
+func (c *SimulatedBeacon) sealBlock(timestamp uint64) error {
+	if missed := c.takeMissedSlots(); missed > 0 {
+		timestamp = max(timestamp, c.lastBlockTime+(missed+1)*c.slotTime())
+		log.Info("Simulating missed slots", "slots", missed, "timestamp", timestamp)
+	}
 	if timestamp <= c.lastBlockTime {
 		timestamp = c.lastBlockTime + 1
 	}
@@ -184,8 +273,13 @@ func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal, timestamp u
 		return fmt.Errorf("failed to sync txpool: %w", err)
 	}
 
-	version := payloadVersion(c.eth.BlockChain().Config(), timestamp)
+	config := c.eth.BlockChain().Config()
+	version := payloadVersion(config, timestamp)
 
+	var withdrawals []*types.Withdrawal
+	if config.IsShanghai(config.LondonBlock, timestamp) {
+		withdrawals = c.withdrawals.pop(10)
+	}
 	var random [32]byte
 	rand.Read(random[:])
 	fcResponse, err := c.engineAPI.forkchoiceUpdated(c.curForkchoiceState, &engine.PayloadAttributes{
@@ -250,6 +344,19 @@ func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal, timestamp u
 	}
 	c.setCurrentState(payload.BlockHash, finalizedHash)
 
+	// Simulate a late forkchoice update if requested
+	c.scenarioLock.Lock()
+	delay := c.forkchoiceDelay
+	c.scenarioLock.Unlock()
+
+	if delay > 0 {
+		log.Info("Delaying forkchoice update", "number", payload.Number, "hash", payload.BlockHash, "delay", delay)
+		select {
+		case <-time.After(delay):
+		case <-c.shutdownCh:
+			return errors.New("simulated beacon stopped")
+		}
+	}
 	// Mark the block containing the payload as canonical
 	if _, err = c.engineAPI.forkchoiceUpdated(c.curForkchoiceState, nil, version, false); err != nil {
 		return err
@@ -266,7 +373,16 @@ func (c *SimulatedBeacon) loop() {
 		case <-c.shutdownCh:
 			return
 		case <-timer.C:
-			if err := c.sealBlock(c.withdrawals.pop(10), uint64(time.Now().Unix())); err != nil {
+			if c.skipSlot() {
+				log.Info("Simulating missed slot")
+				timer.Reset(time.Second * time.Duration(c.period))
+				continue
+			}
+			c.sealLock.Lock()
+			err := c.sealBlock(uint64(time.Now().Unix()))
+			c.sealLock.Unlock()
+
+			if err != nil {
 				log.Warn("Error performing sealing work", "err", err)
 			} else {
 				timer.Reset(time.Second * time.Duration(c.period))
@@ -302,8 +418,10 @@ func (c *SimulatedBeacon) setCurrentState(headHash, finalizedHash common.Hash) {
 
 // Commit seals a block on demand.
 func (c *SimulatedBeacon) Commit() common.Hash {
-	withdrawals := c.withdrawals.pop(10)
-	if err := c.sealBlock(withdrawals, uint64(time.Now().Unix())); err != nil {
+	c.sealLock.Lock()
+	defer c.sealLock.Unlock()
+
+	if err := c.sealBlock(uint64(time.Now().Unix())); err != nil {
 		log.Warn("Error performing sealing work", "err", err)
 	}
 	return c.eth.BlockChain().CurrentBlock().Hash()
@@ -335,12 +453,45 @@ func (c *SimulatedBeacon) AdjustTime(adjustment time.Duration) error {
 	if len(c.eth.TxPool().Pending(txpool.PendingFilter{})) != 0 {
 		return errors.New("could not adjust time on non-empty block")
 	}
+	c.sealLock.Lock()
+	defer c.sealLock.Unlock()
+
 	parent := c.eth.BlockChain().CurrentBlock()
 	if parent == nil {
 		return errors.New("parent not found")
 	}
-	withdrawals := c.withdrawals.pop(10)
-	return c.sealBlock(withdrawals, parent.Time+uint64(adjustment/time.Second))
+	return c.sealBlock(parent.Time + uint64(adjustment/time.Second))
+}
+
+// Reorg replaces the last depth blocks of the canonical chain with new sibling
+// blocks, as if the consensus layer switched to a competing fork. Transactions
+// of the dropped blocks are returned to the pool and included again. Finalized
+// blocks cannot be reorged.
+func (c *SimulatedBeacon) Reorg(depth uint64) error {
+	c.sealLock.Lock()
+	defer c.sealLock.Unlock()
+
+	head := c.eth.BlockChain().CurrentBlock()
+	if depth == 0 || depth > head.Number.Uint64() {
+		return fmt.Errorf("invalid reorg depth %d at head %d", depth, head.Number)
+	}
+	ancestor := c.eth.BlockChain().GetBlockByNumber(head.Number.Uint64() - depth)
+	if ancestor == nil {
+		return errors.New("reorg ancestor not found")
+	}
+	if final := c.eth.BlockChain().CurrentFinalBlock(); final != nil && ancestor.NumberU64() < final.Number.Uint64() {
+		return fmt.Errorf("reorg to %d beyond finalized block %d", ancestor.NumberU64(), final.Number)
+	}
+	if _, err := c.eth.BlockChain().SetCanonical(ancestor); err != nil {
+		return err
+	}
+	for i := uint64(0); i < depth; i++ {
+		if err := c.sealBlock(uint64(time.Now().Unix())); err != nil {
+			return err
+		}
+	}
+	log.Info("Simulated chain reorg", "depth", depth, "ancestor", ancestor.NumberU64(), "head", c.eth.BlockChain().CurrentBlock().Hash())
+	return nil
 }
 
 // RegisterSimulatedBeaconAPIs registers the simulated beacon's API with the
@@ -18,8 +18,10 @@ package catalyst
 
 import (
 	"context"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 )
@@ -103,3 +105,25 @@ func (a *simulatedBeaconAPI) AddWithdrawal(ctx context.Context, withdrawal *type
 func (a *simulatedBeaconAPI) SetFeeRecipient(ctx context.Context, feeRecipient common.Address) {
 	a.sim.setFeeRecipient(feeRecipient)
 }
+
+// SkipSlots makes the given number of upcoming slots be missed.
+func (a *simulatedBeaconAPI) SkipSlots(ctx context.Context, slots hexutil.Uint64) {
+	a.sim.SkipSlots(uint64(slots))
+}
+
+// Reorg replaces the given number of blocks at the head of the chain with new
+// sibling blocks.
+func (a *simulatedBeaconAPI) Reorg(ctx context.Context, depth hexutil.Uint64) error {
+	return a.sim.Reorg(uint64(depth))
+}
+
+// SetForkchoiceDelay sets the delay, in milliseconds, between importing a new
+// payload and making it canonical.
+func (a *simulatedBeaconAPI) SetForkchoiceDelay(ctx context.Context, millis hexutil.Uint64) {
+	a.sim.SetForkchoiceDelay(time.Duration(millis) * time.Millisecond)
+}
+
+// ScriptWithdrawals sets the withdrawals of the upcoming blocks, one entry per block.
+func (a *simulatedBeaconAPI) ScriptWithdrawals(ctx context.Context, script []types.Withdrawals) {
+	a.sim.ScriptWithdrawals(script)
+}
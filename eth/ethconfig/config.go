@@ -49,6 +49,7 @@ var FullNodeGPO = gasprice.Config{
 // Defaults contains default settings for use on the Ethereum main net.
 var Defaults = Config{
 	HistoryMode:        AllHistory,
+	HistoryRetention:   2350000,
 	SyncMode:           SnapSync,
 	NetworkId:          0, // enable auto configuration of networkID == chainID
 	TxLookupLimit:      2350000,
@@ -85,6 +86,14 @@ type Config struct {
 	// HistoryMode configures chain history retention.
 	HistoryMode HistoryMode
 
+	// HistoryEraDir is the directory of era files serving the chain history
+	// which is no longer present in the freezer.
+	HistoryEraDir string `toml:",omitempty"`
+
+	// HistoryRetention is the number of recent blocks whose bodies and receipts
+	// are kept when running with the rolling history mode.
+	HistoryRetention uint64 `toml:",omitempty"`
+
 	// This can be set to list of enrtree:// URLs which will be queried for
 	// nodes to connect to.
 	EthDiscoveryURLs  []string
@@ -94,11 +103,15 @@ type Config struct {
 	NoPruning  bool // Whether to disable pruning and flush everything to disk
 	NoPrefetch bool // Whether to disable prefetching and only load state on demand
 
+	// Whether to prefetch the state hinted by access lists before executing blocks
+	HintPrefetch bool
+
 	// Deprecated: use 'TransactionHistory' instead.
 	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 
 	TransactionHistory uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 	StateHistory       uint64 `toml:",omitempty"` // The maximum number of blocks from head whose state histories are reserved.
+	StateScrub         bool   `toml:",omitempty"` // Whether to verify the integrity of the persisted trie nodes in the background.
 
 	// State scheme represents the scheme used to store ethereum states and trie
 	// nodes on top. It can be 'hash', 'path', or none which means use the scheme
@@ -142,6 +155,14 @@ type Config struct {
 	VMTrace           string
 	VMTraceJsonConfig string
 
+	// Enables recording the EIP-7928 access list of imported blocks
+	BlockAccessLists bool
+
+	// Enables optimistic parallel transaction execution, optionally cross-checked
+	// against the sequential execution
+	ParallelExecution       bool
+	ParallelExecutionVerify bool
+
 	// RPCGasCap is the global gas cap for eth-call variants.
 	RPCGasCap uint64
 
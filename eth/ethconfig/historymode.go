@@ -32,10 +32,14 @@ const (
 
 	// PostMergeHistory sets the history pruning point to the merge activation block.
 	PostMergeHistory
+
+	// RollingHistory keeps the block bodies and receipts of a configurable
+	// number of recent blocks only, continuously pruning older history.
+	RollingHistory
 )
 
 func (m HistoryMode) IsValid() bool {
-	return m <= PostMergeHistory
+	return m <= RollingHistory
 }
 
 func (m HistoryMode) String() string {
@@ -44,6 +48,8 @@ func (m HistoryMode) String() string {
 		return "all"
 	case PostMergeHistory:
 		return "postmerge"
+	case RollingHistory:
+		return "rolling"
 	default:
 		return fmt.Sprintf("invalid HistoryMode(%d)", m)
 	}
@@ -64,8 +70,10 @@ func (m *HistoryMode) UnmarshalText(text []byte) error {
 		*m = AllHistory
 	case "postmerge":
 		*m = PostMergeHistory
+	case "rolling":
+		*m = RollingHistory
 	default:
-		return fmt.Errorf(`unknown sync mode %q, want "all" or "postmerge"`, text)
+		return fmt.Errorf(`unknown sync mode %q, want "all", "postmerge" or "rolling"`, text)
 	}
 	return nil
 }
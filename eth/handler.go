@@ -67,6 +67,14 @@ type txPool interface {
 	// tx hash.
 	Get(hash common.Hash) *types.Transaction
 
+	// IsPrivate returns whether a transaction is withheld from the
+	// network and must not be propagated.
+	IsPrivate(hash common.Hash) bool
+
+	// PrivateNonce returns the lowest nonce of the private transactions pooled
+	// from an account, if any.
+	PrivateNonce(addr common.Address) (uint64, bool)
+
 	// Add should add the given transactions to the pool.
 	Add(txs []*types.Transaction, sync bool) []error
 
@@ -462,6 +470,8 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		blobTxs  int // Number of blob transactions to announce only
 		largeTxs int // Number of large transactions to announce only
 
+		privateTxs int // Number of private transactions (and their followups) to withhold
+
 		directCount int // Number of transactions sent directly to peers (duplicates included)
 		annCount    int // Number of transactions announced across all peers (duplicates included)
 
@@ -481,6 +491,18 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		hash   = make([]byte, 32)
 	)
 	for _, tx := range txs {
+		from, _ := types.Sender(signer, tx) // Ignore error, the addr is only used to withhold followups and split targets
+
+		// Never propagate transactions submitted for private relay, nor the later
+		// ones of the same sender, which can't be executed without them
+		if h.txpool.IsPrivate(tx.Hash()) {
+			privateTxs++
+			continue
+		}
+		if nonce, ok := h.txpool.PrivateNonce(from); ok && tx.Nonce() > nonce {
+			privateTxs++
+			continue
+		}
 		var maybeDirect bool
 		switch {
 		case tx.Type() == types.BlobTxType:
@@ -503,8 +525,6 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 				hasher.Reset()
 				hasher.Write(h.nodeID.Bytes())
 				hasher.Write(peer.Node().ID().Bytes())
-
-				from, _ := types.Sender(signer, tx) // Ignore error, we only use the addr as a propagation target splitter
 				hasher.Write(from.Bytes())
 
 				hasher.Read(hash)
@@ -527,8 +547,8 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		annCount += len(hashes)
 		peer.AsyncSendPooledTransactionHashes(hashes)
 	}
-	log.Debug("Distributed transactions", "plaintxs", len(txs)-blobTxs-largeTxs, "blobtxs", blobTxs, "largetxs", largeTxs,
-		"bcastpeers", len(txset), "bcastcount", directCount, "annpeers", len(annos), "anncount", annCount)
+	log.Debug("Distributed transactions", "plaintxs", len(txs)-blobTxs-largeTxs-privateTxs, "blobtxs", blobTxs, "largetxs", largeTxs,
+		"privatetxs", privateTxs, "bcastpeers", len(txset), "bcastcount", directCount, "annpeers", len(annos), "anncount", annCount)
 }
 
 // txBroadcastLoop announces new transactions to connected peers.
@@ -29,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/event"
@@ -365,6 +366,120 @@ func testSendTransactions(t *testing.T, protocol uint) {
 	}
 }
 
+// Tests that private transactions are neither broadcast nor announced to peers,
+// nor are the later transactions of their senders, whilst public ones submitted
+// alongside them still are.
+func TestPrivateTransactionPropagation68(t *testing.T) {
+	testPrivateTransactionPropagation(t, eth.ETH68)
+}
+
+func testPrivateTransactionPropagation(t *testing.T, protocol uint) {
+	t.Parallel()
+
+	// Create a source handler to send transactions from and a number of sinks
+	// to receive them, so both broadcasts and announcements are exercised.
+	source := newTestHandler()
+	source.handler.snapSync.Store(false) // Avoid requiring snap, otherwise some will be dropped below
+	defer source.close()
+
+	sinks := make([]*testHandler, 10)
+	for i := 0; i < len(sinks); i++ {
+		sinks[i] = newTestHandler()
+		defer sinks[i].close()
+
+		sinks[i].handler.synced.Store(true) // mark synced to accept transactions
+	}
+	// Insert a private transaction before peering to check the initial sync
+	// doesn't leak it either
+	privateKey, _ := crypto.GenerateKey()
+
+	private := make([]*types.Transaction, 16)
+	for i := range private {
+		tx := types.NewTransaction(uint64(i), common.Address{0x01}, big.NewInt(0), 100000, big.NewInt(0), nil)
+		tx, _ = types.SignTx(tx, types.HomesteadSigner{}, privateKey)
+		private[i] = tx
+	}
+	source.txpool.AddPrivate(private[:len(private)/2], false)
+
+	for i, sink := range sinks {
+		sourcePipe, sinkPipe := p2p.MsgPipe()
+		defer sourcePipe.Close()
+		defer sinkPipe.Close()
+
+		sourcePeer := eth.NewPeer(protocol, p2p.NewPeerPipe(enode.ID{byte(i + 1)}, "", nil, sourcePipe), sourcePipe, source.txpool)
+		sinkPeer := eth.NewPeer(protocol, p2p.NewPeerPipe(enode.ID{0}, "", nil, sinkPipe), sinkPipe, sink.txpool)
+		defer sourcePeer.Close()
+		defer sinkPeer.Close()
+
+		go source.handler.runEthPeer(sourcePeer, func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(source.handler), peer)
+		})
+		go sink.handler.runEthPeer(sinkPeer, func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(sink.handler), peer)
+		})
+	}
+	// Subscribe to all the transaction pools
+	txChs := make([]chan core.NewTxsEvent, len(sinks))
+	for i := 0; i < len(sinks); i++ {
+		txChs[i] = make(chan core.NewTxsEvent, 1024)
+
+		sub := sinks[i].txpool.SubscribeTransactions(txChs[i], false)
+		defer sub.Unsubscribe()
+	}
+	// Wait for all peers to register, so the live transactions are propagated
+	// through the broadcast path instead of the initial sync
+	for start := time.Now(); source.handler.peers.len() < len(sinks); time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > 2*time.Second {
+			t.Fatalf("peer registration timed out: have %d, want %d", source.handler.peers.len(), len(sinks))
+		}
+	}
+	// Add the rest of the private transactions live, together with some public
+	// ones following them from the same sender, which must be held back
+	source.txpool.AddPrivate(private[len(private)/2:], false)
+
+	followups := make([]*types.Transaction, 16)
+	for i := range followups {
+		tx := types.NewTransaction(uint64(len(private)+i), common.Address{0x01}, big.NewInt(0), 100000, big.NewInt(0), nil)
+		tx, _ = types.SignTx(tx, types.HomesteadSigner{}, privateKey)
+		followups[i] = tx
+	}
+	source.txpool.Add(followups, false)
+
+	// Add some public transactions from another sender and wait for them at the sinks
+	public := make([]*types.Transaction, 256)
+	for nonce := range public {
+		tx := types.NewTransaction(uint64(nonce), common.Address{0x02}, big.NewInt(0), 100000, big.NewInt(0), nil)
+		tx, _ = types.SignTx(tx, types.HomesteadSigner{}, testKey)
+		public[nonce] = tx
+	}
+	source.txpool.Add(public, false)
+
+	for i := range sinks {
+		for arrived, timeout := 0, false; arrived < len(public) && !timeout; {
+			select {
+			case event := <-txChs[i]:
+				for _, tx := range event.Txs {
+					if from, _ := types.Sender(types.HomesteadSigner{}, tx); from != testAddr {
+						t.Errorf("sink %d: withheld transaction propagated: %x", i, tx.Hash())
+					}
+				}
+				arrived += len(event.Txs)
+			case <-time.After(2 * time.Second):
+				t.Errorf("sink %d: transaction propagation timed out: have %d, want %d", i, arrived, len(public))
+				timeout = true
+			}
+		}
+	}
+	// Ensure none of the withheld transactions made it to any sink
+	for i, sink := range sinks {
+		for _, tx := range append(private, followups...) {
+			if sink.txpool.Has(tx.Hash()) {
+				t.Errorf("sink %d: withheld transaction leaked: %x", i, tx.Hash())
+			}
+		}
+	}
+}
+
 // Tests that transactions get propagated to all attached peers, either via direct
 // broadcasts or via announcements/retrievals.
 func TestTransactionPropagation68(t *testing.T) { testTransactionPropagation(t, eth.ETH68) }
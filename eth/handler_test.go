@@ -48,7 +48,8 @@ var (
 // Its goal is to get around setting up a valid statedb for the balance and nonce
 // checks.
 type testTxPool struct {
-	pool map[common.Hash]*types.Transaction // Hash map of collected transactions
+	pool    map[common.Hash]*types.Transaction // Hash map of collected transactions
+	private map[common.Hash]struct{}           // Set of transactions withheld from the network
 
 	txFeed event.Feed   // Notification feed to allow waiting for inclusion
 	lock   sync.RWMutex // Protects the transaction pool
@@ -57,7 +58,8 @@ type testTxPool struct {
 // newTestTxPool creates a mock transaction pool.
 func newTestTxPool() *testTxPool {
 	return &testTxPool{
-		pool: make(map[common.Hash]*types.Transaction),
+		pool:    make(map[common.Hash]*types.Transaction),
+		private: make(map[common.Hash]struct{}),
 	}
 }
 
@@ -91,6 +93,49 @@ func (p *testTxPool) Add(txs []*types.Transaction, sync bool) []error {
 	return make([]error, len(txs))
 }
 
+// AddPrivate appends a batch of transactions to the pool like Add, but marks
+// them as withheld from the network.
+func (p *testTxPool) AddPrivate(txs []*types.Transaction, sync bool) []error {
+	p.lock.Lock()
+	for _, tx := range txs {
+		p.private[tx.Hash()] = struct{}{}
+	}
+	p.lock.Unlock()
+
+	return p.Add(txs, sync)
+}
+
+// IsPrivate returns whether a transaction is withheld from the network.
+func (p *testTxPool) IsPrivate(hash common.Hash) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	_, ok := p.private[hash]
+	return ok
+}
+
+// PrivateNonce returns the lowest nonce of the private transactions pooled from
+// an account, if any.
+func (p *testTxPool) PrivateNonce(addr common.Address) (uint64, bool) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	var (
+		nonce uint64
+		found bool
+	)
+	for hash := range p.private {
+		tx := p.pool[hash]
+		if from, _ := types.Sender(types.HomesteadSigner{}, tx); from != addr {
+			continue
+		}
+		if !found || tx.Nonce() < nonce {
+			nonce, found = tx.Nonce(), true
+		}
+	}
+	return nonce, found
+}
+
 // Pending returns all the transactions known to the pool
 func (p *testTxPool) Pending(filter txpool.PendingFilter) map[common.Address][]*txpool.LazyTransaction {
 	p.lock.RLock()
@@ -101,8 +146,18 @@ func (p *testTxPool) Pending(filter txpool.PendingFilter) map[common.Address][]*
 		from, _ := types.Sender(types.HomesteadSigner{}, tx)
 		batches[from] = append(batches[from], tx)
 	}
-	for _, batch := range batches {
+	for addr, batch := range batches {
 		sort.Sort(types.TxByNonce(batch))
+
+		// Hide private transactions and anything queued up behind them
+		if filter.NoPrivateTxs {
+			for i, tx := range batch {
+				if _, ok := p.private[tx.Hash()]; ok {
+					batches[addr] = batch[:i]
+					break
+				}
+			}
+		}
 	}
 	pending := make(map[common.Address][]*txpool.LazyTransaction)
 	for addr, batch := range batches {
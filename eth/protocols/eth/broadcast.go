@@ -47,7 +47,7 @@ func (p *Peer) broadcastTransactions() {
 				size        common.StorageSize
 			)
 			for i := 0; i < len(queue) && size < maxTxPacketSize; i++ {
-				if tx := p.txpool.Get(queue[i]); tx != nil {
+				if tx := p.txpool.Get(queue[i]); tx != nil && !p.txpool.IsPrivate(queue[i]) {
 					txs = append(txs, tx)
 					size += common.StorageSize(tx.Size())
 				}
@@ -116,7 +116,7 @@ func (p *Peer) announceTransactions() {
 				size         common.StorageSize
 			)
 			for count = 0; count < len(queue) && size < maxTxPacketSize; count++ {
-				if tx := p.txpool.Get(queue[count]); tx != nil {
+				if tx := p.txpool.Get(queue[count]); tx != nil && !p.txpool.IsPrivate(queue[count]) {
 					pending = append(pending, queue[count])
 					pendingTypes = append(pendingTypes, tx.Type())
 					pendingSizes = append(pendingSizes, uint32(tx.Size()))
@@ -396,9 +396,10 @@ func answerGetPooledTransactions(backend Backend, query GetPooledTransactionsReq
 		if bytes >= softResponseLimit {
 			break
 		}
-		// Retrieve the requested transaction, skipping if unknown to us
+		// Retrieve the requested transaction, skipping if unknown to us or if
+		// it's withheld from the network
 		tx := backend.TxPool().Get(hash)
-		if tx == nil {
+		if tx == nil || backend.TxPool().IsPrivate(hash) {
 			continue
 		}
 		// If known, encode and queue for response packet
@@ -125,6 +125,104 @@ func (ec *Client) GetProof(ctx context.Context, account common.Address, keys []s
 	return &result, err
 }
 
+// MultiProofRequest is an account, along with some of its storage keys, to be
+// proven by a GetMultiProof operation.
+type MultiProofRequest struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []string       `json:"storageKeys"`
+}
+
+// MultiProofResult is the result of a GetMultiProof operation. The proofs of the
+// accounts and storage slots reference the deduplicated trie nodes by index.
+type MultiProofResult struct {
+	Nodes    [][]byte
+	Accounts []MultiAccountResult
+}
+
+// MultiAccountResult is the proof of an account within a MultiProofResult.
+type MultiAccountResult struct {
+	Address      common.Address
+	AccountProof []int
+	Balance      *big.Int
+	CodeHash     common.Hash
+	Nonce        uint64
+	StorageHash  common.Hash
+	StorageProof []MultiStorageResult
+}
+
+// MultiStorageResult is the proof of a storage slot within a MultiProofResult.
+type MultiStorageResult struct {
+	Key   string
+	Value *big.Int
+	Proof []int
+}
+
+// GetMultiProof returns the account and storage values of the specified accounts
+// including the Merkle-proofs, with the trie nodes shared by the proofs included
+// only once. The block number can be nil, in which case the values are taken from
+// the latest known block.
+func (ec *Client) GetMultiProof(ctx context.Context, requests []MultiProofRequest, blockNumber *big.Int) (*MultiProofResult, error) {
+	type storageResult struct {
+		Key   string       `json:"key"`
+		Value *hexutil.Big `json:"value"`
+		Proof []int        `json:"proof"`
+	}
+
+	type accountResult struct {
+		Address      common.Address  `json:"address"`
+		AccountProof []int           `json:"accountProof"`
+		Balance      *hexutil.Big    `json:"balance"`
+		CodeHash     common.Hash     `json:"codeHash"`
+		Nonce        hexutil.Uint64  `json:"nonce"`
+		StorageHash  common.Hash     `json:"storageHash"`
+		StorageProof []storageResult `json:"storageProof"`
+	}
+
+	type multiProofResult struct {
+		Nodes    []hexutil.Bytes `json:"nodes"`
+		Accounts []accountResult `json:"accounts"`
+	}
+
+	// Avoid keys being 'null'.
+	for i := range requests {
+		if requests[i].StorageKeys == nil {
+			requests[i].StorageKeys = []string{}
+		}
+	}
+	var res multiProofResult
+	if err := ec.c.CallContext(ctx, &res, "eth_getMultiProof", requests, toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	// Turn hexutils back to normal datatypes
+	result := &MultiProofResult{
+		Nodes:    make([][]byte, len(res.Nodes)),
+		Accounts: make([]MultiAccountResult, len(res.Accounts)),
+	}
+	for i, node := range res.Nodes {
+		result.Nodes[i] = node
+	}
+	for i, acc := range res.Accounts {
+		storageResults := make([]MultiStorageResult, 0, len(acc.StorageProof))
+		for _, st := range acc.StorageProof {
+			storageResults = append(storageResults, MultiStorageResult{
+				Key:   st.Key,
+				Value: st.Value.ToInt(),
+				Proof: st.Proof,
+			})
+		}
+		result.Accounts[i] = MultiAccountResult{
+			Address:      acc.Address,
+			AccountProof: acc.AccountProof,
+			Balance:      acc.Balance.ToInt(),
+			CodeHash:     acc.CodeHash,
+			Nonce:        uint64(acc.Nonce),
+			StorageHash:  acc.StorageHash,
+			StorageProof: storageResults,
+		}
+	}
+	return result, nil
+}
+
 // CallContract executes a message call transaction, which is directly executed in the VM
 // of the node, but never mined into the blockchain.
 //
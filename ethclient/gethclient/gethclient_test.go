@@ -36,7 +36,9 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
 var (
@@ -124,6 +126,9 @@ func TestGethClient(t *testing.T) {
 		}, {
 			"TestGetProofCanonicalizeKeys",
 			func(t *testing.T) { testGetProofCanonicalizeKeys(t, client) },
+		}, {
+			"TestGetMultiProof",
+			func(t *testing.T) { testGetMultiProof(t, client) },
 		}, {
 			"TestGCStats",
 			func(t *testing.T) { testGCStats(t, client) },
@@ -285,6 +290,72 @@ func testGetProof(t *testing.T, client *rpc.Client, addr common.Address) {
 	}
 }
 
+func testGetMultiProof(t *testing.T, client *rpc.Client) {
+	ec := New(client)
+	ethcl := ethclient.NewClient(client)
+	header, err := ethcl.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs := []common.Address{testAddr, testContract, testEmpty}
+	requests := make([]MultiProofRequest, len(addrs))
+	for i, addr := range addrs {
+		requests[i] = MultiProofRequest{Address: addr, StorageKeys: []string{testSlot.String()}}
+	}
+	result, err := ec.GetMultiProof(context.Background(), requests, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Accounts) != len(addrs) {
+		t.Fatalf("invalid number of accounts, want: %d, got: %d", len(addrs), len(result.Accounts))
+	}
+	// The root node is shared by all the account proofs
+	if result.Accounts[0].AccountProof[0] != result.Accounts[1].AccountProof[0] {
+		t.Fatal("account trie root is not deduplicated")
+	}
+	for i, acc := range result.Accounts {
+		if acc.Address != addrs[i] {
+			t.Fatalf("unexpected address, have: %v want: %v", acc.Address, addrs[i])
+		}
+		blob, err := trie.VerifyMultiProof(header.Root, crypto.Keccak256(acc.Address.Bytes()), result.Nodes, acc.AccountProof)
+		if err != nil {
+			t.Fatalf("addr %x: invalid account proof: %v", acc.Address, err)
+		}
+		if blob == nil {
+			if acc.Nonce != 0 || acc.Balance.Sign() != 0 {
+				t.Fatalf("addr %x: non-empty account proven absent", acc.Address)
+			}
+			continue
+		}
+		var account types.StateAccount
+		if err := rlp.DecodeBytes(blob, &account); err != nil {
+			t.Fatal(err)
+		}
+		if account.Nonce != acc.Nonce || account.Balance.ToBig().Cmp(acc.Balance) != 0 || account.Root != acc.StorageHash {
+			t.Fatalf("addr %x: proven account mismatch: %v", acc.Address, account)
+		}
+		for _, st := range acc.StorageProof {
+			if acc.StorageHash == types.EmptyRootHash {
+				if len(st.Proof) != 0 || st.Value.Sign() != 0 {
+					t.Fatalf("addr %x: unexpected proof of empty storage", acc.Address)
+				}
+				continue
+			}
+			val, err := trie.VerifyMultiProof(acc.StorageHash, crypto.Keccak256(testSlot.Bytes()), result.Nodes, st.Proof)
+			if err != nil {
+				t.Fatalf("addr %x: invalid storage proof: %v", acc.Address, err)
+			}
+			var want []byte
+			if st.Value.Sign() != 0 {
+				want, _ = rlp.EncodeToBytes(st.Value.Bytes())
+			}
+			if !bytes.Equal(val, want) {
+				t.Fatalf("addr %x: proven storage mismatch: have %x, want %x", acc.Address, val, want)
+			}
+		}
+	}
+}
+
 func testGetProofCanonicalizeKeys(t *testing.T, client *rpc.Client) {
 	ec := New(client)
 
@@ -186,6 +186,30 @@ func (n *Backend) AdjustTime(adjustment time.Duration) error {
 	return n.beacon.AdjustTime(adjustment)
 }
 
+// SkipSlots makes the given number of upcoming slots be missed, moving the
+// timestamp of the next committed block past them.
+func (n *Backend) SkipSlots(slots uint64) {
+	n.beacon.SkipSlots(slots)
+}
+
+// Reorg replaces the last depth blocks of the chain with new sibling blocks,
+// simulating a reorg. Transactions of the dropped blocks are included again.
+func (n *Backend) Reorg(depth uint64) error {
+	return n.beacon.Reorg(depth)
+}
+
+// SetForkchoiceDelay delays making committed blocks canonical by the given
+// duration, simulating a late forkchoice update from the consensus layer.
+func (n *Backend) SetForkchoiceDelay(delay time.Duration) {
+	n.beacon.SetForkchoiceDelay(delay)
+}
+
+// ScriptWithdrawals sets the withdrawals of the upcoming committed blocks, one
+// entry of the script per block.
+func (n *Backend) ScriptWithdrawals(script []types.Withdrawals) {
+	n.beacon.ScriptWithdrawals(script)
+}
+
 // Client returns a client that accesses the simulated chain.
 func (n *Backend) Client() Client {
 	return n.client
@@ -369,3 +369,107 @@ func TestCheckSimBackendGoroutineLeak(t *testing.T) {
 	createAndCloseSimBackend()
 	goleak.VerifyNone(t, ignoreCur, ignoreLdb)
 }
+
+func TestSkipSlots(t *testing.T) {
+	sim := NewBackend(types.GenesisAlloc{})
+	defer sim.Close()
+
+	client := sim.Client()
+	sim.Commit()
+	block1, _ := client.BlockByNumber(context.Background(), nil)
+
+	// Miss three slots and ensure the next block is timestamped after them
+	sim.SkipSlots(3)
+	if err := sim.AdjustTime(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	block2, _ := client.BlockByNumber(context.Background(), nil)
+	if block2.NumberU64() != block1.NumberU64()+1 {
+		t.Fatalf("block number mismatch: have %d, want %d", block2.NumberU64(), block1.NumberU64()+1)
+	}
+	if diff := block2.Time() - block1.Time(); diff != 4*12 {
+		t.Errorf("slot gap mismatch: have %d seconds, want %d", diff, 4*12)
+	}
+}
+
+func TestReorg(t *testing.T) {
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	client := sim.Client()
+	ctx := context.Background()
+
+	tx, err := newTx(sim, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	old1 := sim.Commit()
+	old2 := sim.Commit()
+
+	if err := sim.Reorg(3); err == nil {
+		t.Fatal("reorg beyond genesis succeeded")
+	}
+	if err := sim.Reorg(2); err != nil {
+		t.Fatal(err)
+	}
+	head, _ := client.HeaderByNumber(ctx, nil)
+	if head.Number.Uint64() != 2 {
+		t.Fatalf("head number mismatch: have %d, want 2", head.Number.Uint64())
+	}
+	if head.Hash() == old2 {
+		t.Fatal("head not reorged")
+	}
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatalf("reorged transaction not included again: %v", err)
+	}
+	if receipt.BlockHash == old1 {
+		t.Fatal("transaction still included in reorged block")
+	}
+}
+
+func TestForkchoiceDelay(t *testing.T) {
+	sim := NewBackend(types.GenesisAlloc{})
+	defer sim.Close()
+
+	sim.SetForkchoiceDelay(100 * time.Millisecond)
+	start := time.Now()
+	sim.Commit()
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("forkchoice update not delayed: %v", elapsed)
+	}
+	num, _ := sim.Client().BlockNumber(context.Background())
+	if num != 1 {
+		t.Fatalf("block number mismatch: have %d, want 1", num)
+	}
+}
+
+func TestScriptWithdrawals(t *testing.T) {
+	sim := NewBackend(types.GenesisAlloc{})
+	defer sim.Close()
+
+	recipient := common.Address{0xaa}
+	sim.ScriptWithdrawals([]types.Withdrawals{
+		{{Index: 0, Validator: 1, Address: recipient, Amount: 1}},
+		nil,
+	})
+	sim.Commit()
+	sim.Commit()
+
+	client := sim.Client()
+	block1, _ := client.BlockByNumber(context.Background(), big.NewInt(1))
+	if ws := block1.Withdrawals(); len(ws) != 1 || ws[0].Address != recipient {
+		t.Fatalf("scripted withdrawals mismatch: %v", ws)
+	}
+	block2, _ := client.BlockByNumber(context.Background(), big.NewInt(2))
+	if ws := block2.Withdrawals(); ws == nil || len(ws) != 0 {
+		t.Fatalf("empty scripted withdrawals mismatch: %v", ws)
+	}
+	balance, _ := client.BalanceAt(context.Background(), recipient, nil)
+	if balance.Cmp(big.NewInt(params.GWei)) != 0 {
+		t.Fatalf("withdrawal balance mismatch: have %v, want %v", balance, params.GWei)
+	}
+}
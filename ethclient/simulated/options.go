@@ -17,10 +17,13 @@
 package simulated
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/forks"
 )
 
 // WithBlockGasLimit configures the simulated backend to target a specific gas limit
@@ -53,3 +56,31 @@ func WithMinerMinTip(tip *big.Int) func(nodeConf *node.Config, ethConf *ethconfi
 		ethConf.Miner.GasPrice = tip
 	}
 }
+
+// WithForkTime schedules the activation of a post-merge fork at the given block
+// timestamp instead of at genesis. Later forks activating earlier are moved to
+// the same timestamp, keeping the fork order intact.
+//
+// Only Shanghai, Cancun, Prague and Osaka can be scheduled.
+func WithForkTime(fork forks.Fork, time uint64) func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+	if fork < forks.Shanghai || fork > forks.Osaka {
+		panic(fmt.Sprintf("unschedulable fork %d", fork))
+	}
+	return func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		config := *ethConf.Genesis.Config
+		ethConf.Genesis.Config = &config
+
+		schedule := []**uint64{&config.ShanghaiTime, &config.CancunTime, &config.PragueTime, &config.OsakaTime}
+		*schedule[fork-forks.Shanghai] = &time
+		for _, later := range schedule[fork-forks.Shanghai+1:] {
+			if *later != nil && **later < time {
+				*later = &time
+			}
+		}
+		if config.OsakaTime != nil && config.BlobScheduleConfig != nil && config.BlobScheduleConfig.Osaka == nil {
+			blobs := *config.BlobScheduleConfig
+			blobs.Osaka = params.DefaultOsakaBlobConfig
+			config.BlobScheduleConfig = &blobs
+		}
+	}
+}
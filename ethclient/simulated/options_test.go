@@ -21,11 +21,13 @@ import (
 	"math/big"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/forks"
 )
 
 // Tests that the simulator starts with the initial gas limit in the genesis block,
@@ -72,3 +74,34 @@ func TestWithCallGasLimitOption(t *testing.T) {
 		t.Fatalf("error mismatch: have %v, want %v", err, core.ErrIntrinsicGas)
 	}
 }
+
+// Tests that forks can be scheduled to activate after genesis.
+func TestWithForkTimeOption(t *testing.T) {
+	fork := uint64(time.Now().Add(time.Hour).Unix())
+
+	sim := NewBackend(types.GenesisAlloc{}, WithForkTime(forks.Prague, fork))
+	defer sim.Close()
+
+	// Ensure the shared dev config was not modified
+	if params.AllDevChainProtocolChanges.PragueTime == nil || *params.AllDevChainProtocolChanges.PragueTime != 0 {
+		t.Fatalf("dev chain config modified")
+	}
+	client := sim.Client()
+	sim.Commit()
+	head, err := client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve head block: %v", err)
+	}
+	if head.RequestsHash != nil {
+		t.Fatalf("prague activated before scheduled time %d at %d", fork, head.Time)
+	}
+	if err := sim.AdjustTime(2 * time.Hour); err != nil {
+		t.Fatalf("failed to adjust time: %v", err)
+	}
+	if head, err = client.HeaderByNumber(context.Background(), nil); err != nil {
+		t.Fatalf("failed to retrieve head block: %v", err)
+	}
+	if head.RequestsHash == nil {
+		t.Fatalf("prague not activated after scheduled time %d at %d", fork, head.Time)
+	}
+}
@@ -0,0 +1,484 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package eradl implements downloading and verifying era files from a mirror,
+// which is either an HTTP server or a local directory.
+package eradl
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/erae"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// ManifestName is the default name of the checksum manifest of a mirror.
+	ManifestName = "checksums.txt"
+
+	// downloadWorkers is the number of era files downloaded concurrently.
+	downloadWorkers = 4
+
+	// partialSuffix is appended to the name of the files being downloaded,
+	// allowing interrupted downloads to be resumed.
+	partialSuffix = ".partial"
+)
+
+// Entry is an era file listed in the checksum manifest of a mirror.
+type Entry struct {
+	Name     string      // File name, <network>-<epoch>-<root>.<era1|erae>
+	Checksum common.Hash // SHA256 of the file
+}
+
+// Loader downloads the era files of a network from a mirror.
+type Loader struct {
+	mirror   *url.URL
+	manifest *url.URL
+	network  string
+	roots    []common.Hash // Trusted accumulator roots, indexed by epoch
+	client   *http.Client
+}
+
+// New creates a loader for the era files of the given network, hosted at the
+// mirror URL. Both http(s):// and file:// URLs are supported. The manifest is
+// the location of the checksum manifest, defaulting to ManifestName within the
+// mirror if empty, and may be relative to the mirror.
+//
+// The mirror is not trusted: every downloaded file must carry the accumulator
+// root of its epoch among the given trusted roots, which are ordered by epoch,
+// Era1 archives first, as accepted by 'era verify'.
+func New(mirror, manifest, network string, roots []common.Hash) (*Loader, error) {
+	base, err := parseURL(mirror)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	if manifest == "" {
+		manifest = ManifestName
+	}
+	ref, err := url.Parse(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest URL %q: %w", manifest, err)
+	}
+	return &Loader{
+		mirror:   base,
+		manifest: base.ResolveReference(ref),
+		network:  network,
+		roots:    roots,
+		client:   http.DefaultClient,
+	}, nil
+}
+
+// parseURL parses a mirror URL, interpreting plain paths as local directories.
+func parseURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mirror URL %q: %w", raw, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "file":
+		return u, nil
+	case "":
+		abs, err := filepath.Abs(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported mirror URL scheme %q", u.Scheme)
+	}
+}
+
+// Entries retrieves the checksum manifest of the mirror, returning the era
+// files of the network in import order: Era1 archives followed by post-merge
+// archives, each ordered by epoch.
+//
+// Every line of the manifest holds the hex checksum of a file followed by its
+// name, as produced by sha256sum. Manifests made of checksums only, as written
+// by export-history, are supported for local mirrors, the file names being
+// taken from the mirror directory instead.
+func (l *Loader) Entries(ctx context.Context) ([]Entry, error) {
+	r, _, err := l.open(ctx, l.manifest, 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve manifest: %w", err)
+	}
+	defer r.Close()
+
+	var (
+		entries []Entry
+		bare    []common.Hash
+		scanner = bufio.NewScanner(r)
+	)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			bare = append(bare, common.HexToHash(fields[0]))
+		case 2:
+			name := strings.TrimPrefix(fields[1], "*") // binary mode marker
+			if !strings.HasPrefix(name, l.network+"-") {
+				continue
+			}
+			if name != path.Base(name) {
+				return nil, fmt.Errorf("invalid file name in manifest: %q", name)
+			}
+			entries = append(entries, Entry{Name: name, Checksum: common.HexToHash(fields[0])})
+		default:
+			return nil, fmt.Errorf("invalid manifest line: %q", scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read manifest: %w", err)
+	}
+	if len(bare) > 0 {
+		if len(entries) > 0 {
+			return nil, errors.New("manifest mixes named and unnamed checksums")
+		}
+		return l.localEntries(bare)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		ei, ej := path.Ext(entries[i].Name), path.Ext(entries[j].Name)
+		if ei != ej {
+			return ei == ".era1"
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// localEntries pairs the checksums of a manifest lacking file names with the
+// era files of a local mirror.
+func (l *Loader) localEntries(checksums []common.Hash) ([]Entry, error) {
+	if l.mirror.Scheme != "file" {
+		return nil, errors.New("manifest lacks file names")
+	}
+	dir := filepath.FromSlash(l.mirror.Path)
+	names, err := era.ReadDir(dir, l.network)
+	if err != nil {
+		return nil, err
+	}
+	postMerge, err := erae.ReadDir(dir, l.network)
+	if err != nil {
+		return nil, err
+	}
+	names = append(names, postMerge...)
+	if len(names) != len(checksums) {
+		return nil, fmt.Errorf("expected equal number of checksums and entries, have: %d checksums, %d entries", len(checksums), len(names))
+	}
+	entries := make([]Entry, len(names))
+	for i, name := range names {
+		entries[i] = Entry{Name: name, Checksum: checksums[i]}
+	}
+	return entries, nil
+}
+
+// ReadRoots reads a file of newline-delimited accumulator roots.
+func ReadRoots(filename string) ([]common.Hash, error) {
+	blob, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var roots []common.Hash
+	for _, line := range strings.Fields(string(blob)) {
+		var root common.Hash
+		if err := root.UnmarshalText([]byte(line)); err != nil {
+			return nil, fmt.Errorf("invalid accumulator root %q: %w", line, err)
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// trustedRoot returns the trusted accumulator root of an era file.
+func (l *Loader) trustedRoot(name string) (common.Hash, error) {
+	parts := strings.Split(strings.TrimSuffix(name, path.Ext(name)), "-")
+	if len(parts) != 3 {
+		return common.Hash{}, fmt.Errorf("invalid era file name %q", name)
+	}
+	epoch, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("invalid era file name %q", name)
+	}
+	if epoch >= uint64(len(l.roots)) {
+		return common.Hash{}, fmt.Errorf("no trusted accumulator root for epoch %d", epoch)
+	}
+	return l.roots[epoch], nil
+}
+
+// Download fetches the era files of the network missing from the given
+// directory, verifying each of them, and writes the checksums of all files
+// into the directory for the history import. Interrupted downloads are resumed
+// where they left off.
+func (l *Loader) Download(ctx context.Context, dir string) error {
+	entries, err := l.Entries(ctx)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no era files of network %s found on mirror", l.network)
+	}
+	// Refuse to download anything if some of the files can't be verified.
+	for _, entry := range entries {
+		if _, err := l.trustedRoot(entry.Name); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name, err)
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	// Skip the files which are already present.
+	var (
+		missing   []Entry
+		checksums = make(map[string]common.Hash)
+	)
+	for _, entry := range entries {
+		checksums[entry.Name] = entry.Checksum
+		if sum, err := checksum(filepath.Join(dir, entry.Name)); err == nil && sum == entry.Checksum {
+			continue
+		}
+		missing = append(missing, entry)
+	}
+	log.Info("Downloading era files", "mirror", l.mirror.Redacted(), "total", len(entries), "missing", len(missing))
+
+	var (
+		tasks = make(chan Entry)
+		errs  = make(chan error, len(missing))
+		wg    sync.WaitGroup
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for i := 0; i < downloadWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range tasks {
+				if err := l.fetch(ctx, dir, entry); err != nil {
+					errs <- fmt.Errorf("%s: %w", entry.Name, err)
+					cancel()
+					continue
+				}
+				log.Info("Downloaded era file", "name", entry.Name)
+			}
+		}()
+	}
+loop:
+	for _, entry := range missing {
+		select {
+		case tasks <- entry:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(tasks)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// Write the checksums in the order the history import reads the files of
+	// the directory in: Era1 archives followed by post-merge ones.
+	names, err := era.ReadDir(dir, l.network)
+	if err != nil {
+		return err
+	}
+	postMerge, err := erae.ReadDir(dir, l.network)
+	if err != nil {
+		return err
+	}
+	var lines []string
+	for _, name := range append(names, postMerge...) {
+		sum, ok := checksums[name]
+		if !ok {
+			return fmt.Errorf("era file %s not listed by the mirror", name)
+		}
+		lines = append(lines, sum.Hex())
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestName), []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// fetch downloads an era file into the directory, resuming any previously
+// interrupted download of it, and moves it into place once verified.
+func (l *Loader) fetch(ctx context.Context, dir string, entry Entry) error {
+	partial := filepath.Join(dir, entry.Name+partialSuffix)
+	f, err := os.OpenFile(partial, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	r, offset, err := l.open(ctx, l.mirror.JoinPath(entry.Name), offset)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	// Discard the previously downloaded data if the mirror can't resume.
+	if err := f.Truncate(offset); err != nil {
+		return err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	root, err := l.trustedRoot(entry.Name)
+	if err != nil {
+		return err
+	}
+	if err := verify(partial, entry, root); err != nil {
+		os.Remove(partial)
+		return err
+	}
+	return os.Rename(partial, filepath.Join(dir, entry.Name))
+}
+
+// open retrieves the content at the given URL, starting at the given offset
+// if the mirror supports it. The offset the content actually starts at is
+// returned along with it.
+func (l *Loader) open(ctx context.Context, u *url.URL, offset int64) (io.ReadCloser, int64, error) {
+	if u.Scheme == "file" {
+		f, err := os.Open(filepath.FromSlash(u.Path))
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			f.Close()
+			return nil, 0, err
+		}
+		return f, offset, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, 0, nil
+	case http.StatusPartialContent:
+		return resp.Body, offset, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is as long as, or longer than, the remote one,
+		// start over.
+		resp.Body.Close()
+		return l.open(ctx, u, 0)
+	default:
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+}
+
+// verify checks a downloaded era file against its manifest entry and trusted
+// accumulator root: the checksum must match, as well as the accumulator root,
+// which is recomputed from the contained blocks.
+func verify(filename string, entry Entry, trusted common.Hash) error {
+	sum, err := checksum(filename)
+	if err != nil {
+		return err
+	}
+	if sum != entry.Checksum {
+		return fmt.Errorf("checksum mismatch: have %s, want %s", sum, entry.Checksum)
+	}
+	var root common.Hash
+	switch path.Ext(entry.Name) {
+	case ".era1":
+		e, err := era.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer e.Close()
+		if root, err = e.Accumulator(); err != nil {
+			return err
+		}
+		if err := e.Verify(trie.NewStackTrie(nil)); err != nil {
+			return err
+		}
+	case ".erae":
+		e, err := erae.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer e.Close()
+		if root, err = e.Accumulator(); err != nil {
+			return err
+		}
+		if err := e.Verify(trie.NewStackTrie(nil), nil, 0); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid era file name %q", entry.Name)
+	}
+	if root != trusted {
+		return fmt.Errorf("accumulator root mismatch: have %s, want %s", root, trusted)
+	}
+	return nil
+}
+
+// checksum computes the SHA256 checksum of a file.
+func checksum(filename string) (common.Hash, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(h.Sum(nil)), nil
+}
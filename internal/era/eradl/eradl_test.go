@@ -0,0 +1,270 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eradl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/era"
+)
+
+// makeMirror writes a number of Era1 files of the given network into a
+// directory, returning their manifest entries and accumulator roots.
+func makeMirror(t *testing.T, dir, network string, epochs int) ([]Entry, []common.Hash) {
+	var (
+		entries []Entry
+		roots   []common.Hash
+		parent  common.Hash
+		td      = new(big.Int)
+	)
+	for epoch := 0; epoch < epochs; epoch++ {
+		f, err := os.CreateTemp(dir, "tmp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		builder := era.NewBuilder(f)
+		for i := 0; i < 16; i++ {
+			header := &types.Header{
+				ParentHash:  parent,
+				Number:      big.NewInt(int64(epoch*16 + i)),
+				Difficulty:  big.NewInt(1),
+				TxHash:      types.EmptyTxsHash,
+				ReceiptHash: types.EmptyReceiptsHash,
+				UncleHash:   types.EmptyUncleHash,
+			}
+			block := types.NewBlockWithHeader(header)
+			td.Add(td, header.Difficulty)
+			if err := builder.Add(block, types.Receipts{}, new(big.Int).Set(td)); err != nil {
+				t.Fatal(err)
+			}
+			parent = block.Hash()
+		}
+		root, err := builder.Finalize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+
+		name := era.Filename(network, epoch, root)
+		if err := os.Rename(f.Name(), filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+		sum, err := checksum(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, Entry{Name: name, Checksum: sum})
+		roots = append(roots, root)
+	}
+	return entries, roots
+}
+
+// writeManifest writes the checksum manifest of the entries, with or without
+// the file names.
+func writeManifest(t *testing.T, filename string, entries []Entry, named bool) {
+	var lines []string
+	for _, entry := range entries {
+		if named {
+			lines = append(lines, fmt.Sprintf("%x  %s", entry.Checksum, entry.Name))
+		} else {
+			lines = append(lines, entry.Checksum.Hex())
+		}
+	}
+	if err := os.WriteFile(filename, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkDownload ensures the destination directory holds the mirrored files
+// along with their checksums.
+func checkDownload(t *testing.T, src, dst string, entries []Entry) {
+	t.Helper()
+
+	var checksums []string
+	for _, entry := range entries {
+		want, _ := os.ReadFile(filepath.Join(src, entry.Name))
+		have, err := os.ReadFile(filepath.Join(dst, entry.Name))
+		if err != nil {
+			t.Fatalf("missing era file %s: %v", entry.Name, err)
+		}
+		if !bytes.Equal(have, want) {
+			t.Fatalf("era file %s mismatch", entry.Name)
+		}
+		checksums = append(checksums, entry.Checksum.Hex())
+	}
+	blob, err := os.ReadFile(filepath.Join(dst, ManifestName))
+	if err != nil {
+		t.Fatalf("missing checksums: %v", err)
+	}
+	if have, want := string(blob), strings.Join(checksums, "\n"); have != want {
+		t.Fatalf("checksums mismatch: have %q, want %q", have, want)
+	}
+}
+
+func TestDownloadHTTP(t *testing.T) {
+	t.Parallel()
+
+	var (
+		src            = t.TempDir()
+		dst            = t.TempDir()
+		entries, roots = makeMirror(t, src, "mainnet", 3)
+	)
+	writeManifest(t, filepath.Join(src, ManifestName), entries, true)
+	makeMirror(t, src, "sepolia", 1) // not listed, not downloaded
+
+	// Seed the destination with a complete and a partially downloaded file.
+	blob, _ := os.ReadFile(filepath.Join(src, entries[0].Name))
+	os.WriteFile(filepath.Join(dst, entries[0].Name), blob, 0644)
+	blob, _ = os.ReadFile(filepath.Join(src, entries[1].Name))
+	os.WriteFile(filepath.Join(dst, entries[1].Name+partialSuffix), blob[:len(blob)/2], 0644)
+
+	var (
+		lock      sync.Mutex
+		requested = make(map[string]string)
+		files     = http.FileServer(http.Dir(src))
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requested[strings.TrimPrefix(r.URL.Path, "/mirror/")] = r.Header.Get("Range")
+		lock.Unlock()
+		http.StripPrefix("/mirror", files).ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	l, err := New(srv.URL+"/mirror", "", "mainnet", roots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Download(context.Background(), dst); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	checkDownload(t, src, dst, entries)
+
+	if _, ok := requested[entries[0].Name]; ok {
+		t.Errorf("present file %s downloaded again", entries[0].Name)
+	}
+	if have, want := requested[entries[1].Name], fmt.Sprintf("bytes=%d-", len(blob)/2); have != want {
+		t.Errorf("partial file %s not resumed: have range %q, want %q", entries[1].Name, have, want)
+	}
+	if _, err := os.Stat(filepath.Join(dst, entries[1].Name+partialSuffix)); !os.IsNotExist(err) {
+		t.Errorf("partial file left behind: %v", err)
+	}
+}
+
+func TestDownloadLocal(t *testing.T) {
+	t.Parallel()
+
+	var (
+		src            = t.TempDir()
+		dst            = t.TempDir()
+		entries, roots = makeMirror(t, src, "mainnet", 2)
+	)
+	// Local mirrors also accept the manifests written by export-history.
+	writeManifest(t, filepath.Join(src, ManifestName), entries, false)
+
+	l, err := New("file://"+filepath.ToSlash(src), "", "mainnet", roots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Download(context.Background(), dst); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	checkDownload(t, src, dst, entries)
+}
+
+func TestDownloadInvalid(t *testing.T) {
+	t.Parallel()
+
+	var (
+		src            = t.TempDir()
+		entries, roots = makeMirror(t, src, "mainnet", 2)
+	)
+	writeManifest(t, filepath.Join(src, ManifestName), entries, true)
+
+	// Corrupt the checksum of the file.
+	bad := []Entry{{Name: entries[0].Name, Checksum: common.Hash{0x01}}}
+	writeManifest(t, filepath.Join(src, "bad-checksum.txt"), bad, true)
+
+	// Trust a root differing from the one of the file, even if matching the
+	// prefix embedded in its name.
+	badRoot := roots[0]
+	badRoot[31] ^= 0x01
+
+	tests := []struct {
+		manifest string
+		roots    []common.Hash
+		want     string
+	}{
+		{"bad-checksum.txt", roots, "checksum mismatch"},
+		{ManifestName, []common.Hash{badRoot, roots[1]}, "accumulator root mismatch"},
+		{ManifestName, roots[:1], "no trusted accumulator root for epoch 1"},
+	}
+	for _, tt := range tests {
+		dst := t.TempDir()
+		l, err := New(src, tt.manifest, "mainnet", tt.roots)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = l.Download(context.Background(), dst)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("%s: unexpected error: have %v, want %q", tt.manifest, err, tt.want)
+		}
+		if _, err := os.Stat(filepath.Join(dst, entries[0].Name)); !os.IsNotExist(err) {
+			t.Fatalf("%s: invalid file kept: %v", tt.manifest, err)
+		}
+	}
+}
+
+func TestDownloadUnlisted(t *testing.T) {
+	t.Parallel()
+
+	var (
+		src            = t.TempDir()
+		dst            = t.TempDir()
+		entries, roots = makeMirror(t, src, "mainnet", 2)
+	)
+	writeManifest(t, filepath.Join(src, ManifestName), entries[1:], true)
+
+	// A file of the network the mirror doesn't vouch for must not end up in
+	// the checksums written for the history import.
+	blob, _ := os.ReadFile(filepath.Join(src, entries[0].Name))
+	os.WriteFile(filepath.Join(dst, entries[0].Name), blob, 0644)
+
+	l, err := New(src, "", "mainnet", roots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = l.Download(context.Background(), dst)
+	if err == nil || !strings.Contains(err.Error(), "not listed by the mirror") {
+		t.Fatalf("unexpected error: have %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, ManifestName)); !os.IsNotExist(err) {
+		t.Fatalf("checksums written: %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package erae
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/testrand"
+)
+
+// makeProof creates a random proof for the given execution block hash, returning
+// it along with the block summary root it is proven against.
+func makeProof(slot uint64, blockHash common.Hash, payloadDepth int) (*BlockProof, common.Hash) {
+	hash := func(index uint64, value common.Hash, branch []common.Hash) common.Hash {
+		for _, sibling := range branch {
+			if index&1 == 0 {
+				value = sha256.Sum256(append(value.Bytes(), sibling.Bytes()...))
+			} else {
+				value = sha256.Sum256(append(sibling.Bytes(), value.Bytes()...))
+			}
+			index >>= 1
+		}
+		return value
+	}
+	proof := &BlockProof{Slot: slot}
+	for i := 0; i < beaconBlockDepth+bodyDepth+payloadDepth; i++ {
+		proof.ExecutionBlockProof = append(proof.ExecutionBlockProof, testrand.Hash())
+	}
+	for i := 0; i < blockRootsDepth; i++ {
+		proof.BeaconBlockProof = append(proof.BeaconBlockProof, testrand.Hash())
+	}
+	index := uint64(beaconBodyIndex)<<(bodyDepth+payloadDepth) | bodyPayloadIndex<<payloadDepth | payloadBlockHashIndex
+	proof.BeaconBlockRoot = hash(index, blockHash, proof.ExecutionBlockProof)
+	return proof, hash(slot%SlotsPerHistoricalRoot, proof.BeaconBlockRoot, proof.BeaconBlockProof)
+}
+
+func TestBlockProof(t *testing.T) {
+	t.Parallel()
+
+	for _, depth := range []int{payloadCapellaDepth, payloadDenebDepth} {
+		blockHash := testrand.Hash()
+		proof, root := makeProof(3*SlotsPerHistoricalRoot+17, blockHash, depth)
+		if proof.Period() != 3 {
+			t.Fatalf("unexpected period: have %d, want 3", proof.Period())
+		}
+		if err := proof.Verify(blockHash, root); err != nil {
+			t.Fatalf("failed to verify proof: %v", err)
+		}
+		if err := proof.Verify(testrand.Hash(), root); err == nil {
+			t.Fatal("proof verified for wrong block")
+		}
+		if err := proof.Verify(blockHash, testrand.Hash()); err == nil {
+			t.Fatal("proof verified against wrong summary")
+		}
+		proof.Slot++
+		if err := proof.Verify(blockHash, root); err == nil {
+			t.Fatal("proof verified for wrong slot")
+		}
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.CreateTemp(t.TempDir(), "erae-test")
+	if err != nil {
+		t.Fatalf("error creating temp file: %v", err)
+	}
+	defer f.Close()
+
+	var (
+		builder  = NewBuilder(f)
+		blocks   []*types.Block
+		receipts []types.Receipts
+		proofs   []*BlockProof
+		hashes   []common.Hash
+	)
+	for i := 0; i < 128; i++ {
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:     big.NewInt(int64(1000 + i)),
+			Difficulty: new(big.Int),
+			Extra:      []byte{byte(i)},
+		})
+		receipt := types.Receipts{{CumulativeGasUsed: uint64(i), Logs: []*types.Log{}}}
+
+		// Only prove every other block
+		var proof *BlockProof
+		if i%2 == 0 {
+			proof, _ = makeProof(uint64(i), block.Hash(), payloadDenebDepth)
+		}
+		if err := builder.Add(block, receipt, proof); err != nil {
+			t.Fatalf("error adding block %d: %v", i, err)
+		}
+		blocks = append(blocks, block)
+		receipts = append(receipts, receipt)
+		proofs = append(proofs, proof)
+		hashes = append(hashes, block.Hash())
+	}
+	if err := builder.Add(blocks[0], receipts[0], nil); err == nil {
+		t.Fatal("non-contiguous block added")
+	}
+	root, err := builder.Finalize()
+	if err != nil {
+		t.Fatalf("error finalizing: %v", err)
+	}
+	if want, _ := ComputeAccumulator(hashes); root != want {
+		t.Fatalf("accumulator mismatch: have %x, want %x", root, want)
+	}
+	e, err := Open(f.Name())
+	if err != nil {
+		t.Fatalf("failed to open era: %v", err)
+	}
+	defer e.Close()
+
+	if e.Start() != 1000 || e.Count() != 128 {
+		t.Fatalf("unexpected range: start %d, count %d", e.Start(), e.Count())
+	}
+	if have, err := e.Accumulator(); err != nil || have != root {
+		t.Fatalf("accumulator mismatch: have %x, want %x, err %v", have, root, err)
+	}
+	// Check the random access to the entries
+	for i, block := range blocks {
+		number := block.NumberU64()
+		have, err := e.GetBlockByNumber(number)
+		if err != nil || have.Hash() != block.Hash() {
+			t.Fatalf("block %d mismatch: %v", number, err)
+		}
+		haveReceipts, err := e.GetReceiptsByNumber(number)
+		if err != nil || len(haveReceipts) != 1 || haveReceipts[0].CumulativeGasUsed != uint64(i) {
+			t.Fatalf("receipts %d mismatch: %v", number, err)
+		}
+		proof, err := e.GetProofByNumber(number)
+		if err != nil || !reflect.DeepEqual(proof, proofs[i]) {
+			t.Fatalf("proof %d mismatch: have %v, want %v, err %v", number, proof, proofs[i], err)
+		}
+	}
+	if _, err := e.GetBlockByNumber(1128); err == nil {
+		t.Fatal("out-of-bounds block returned")
+	}
+	// Check the iteration over the entries
+	it, err := NewIterator(e)
+	if err != nil {
+		t.Fatalf("failed to make iterator: %v", err)
+	}
+	var i int
+	for ; it.Next(); i++ {
+		if it.Error() != nil {
+			t.Fatalf("unexpected error %v", it.Error())
+		}
+		block, err := it.Block()
+		if err != nil || block.Hash() != blocks[i].Hash() || it.Number() != block.NumberU64() {
+			t.Fatalf("block %d mismatch: %v", i, err)
+		}
+		if _, err := it.Receipts(); err != nil {
+			t.Fatalf("error reading receipts: %v", err)
+		}
+		proof, err := it.Proof()
+		if err != nil || !reflect.DeepEqual(proof, proofs[i]) {
+			t.Fatalf("proof %d mismatch: have %v, want %v, err %v", i, proof, proofs[i], err)
+		}
+	}
+	if it.Error() != nil || i != len(blocks) {
+		t.Fatalf("iteration stopped at %d: %v", i, it.Error())
+	}
+}
+
+func TestFilename(t *testing.T) {
+	t.Parallel()
+
+	if have, want := Filename("mainnet", 1, common.Hash{1}), "mainnet-00001-01000000.erae"; have != want {
+		t.Errorf("invalid filename: want %s, got %s", want, have)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package erae
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/beacon/merkle"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// SlotsPerHistoricalRoot is the number of beacon block roots accumulated in
+	// a historical summary of the beacon state.
+	SlotsPerHistoricalRoot = 8192
+
+	// Positions and tree depths of the fields leading to the execution block
+	// hash from the root of a beacon block.
+	beaconBodyIndex       = 4  // BeaconBlock.body
+	beaconBlockDepth      = 3  // BeaconBlock, 5 fields
+	bodyPayloadIndex      = 9  // BeaconBlockBody.execution_payload
+	bodyDepth             = 4  // BeaconBlockBody, up to 16 fields
+	payloadBlockHashIndex = 12 // ExecutionPayload.block_hash
+	payloadCapellaDepth   = 4  // ExecutionPayload in Capella, 15 fields
+	payloadDenebDepth     = 5  // ExecutionPayload since Deneb, 17 fields
+	blockRootsDepth       = 13 // Block roots vector, 8192 items
+)
+
+// BlockProof proves that an execution block is part of the canonical chain by
+// linking its hash to the root of the beacon block embedding it, and that root
+// to the block summary root of a historical summary of the beacon state.
+type BlockProof struct {
+	Slot                uint64        // Slot of the beacon block embedding the execution payload
+	BeaconBlockRoot     common.Hash   // Root of the beacon block
+	BeaconBlockProof    []common.Hash // Branch of the beacon block root in the block summary root
+	ExecutionBlockProof []common.Hash // Branch of the execution block hash in the beacon block
+}
+
+// Period returns the index of the historical summary accumulating the beacon
+// block of the proof.
+func (p *BlockProof) Period() uint64 {
+	return p.Slot / SlotsPerHistoricalRoot
+}
+
+// Verify checks that the execution block with the given hash is proven to be
+// part of the historical summary with the given block summary root.
+func (p *BlockProof) Verify(blockHash common.Hash, blockSummaryRoot common.Hash) error {
+	var depth int
+	switch len(p.ExecutionBlockProof) - beaconBlockDepth - bodyDepth {
+	case payloadCapellaDepth:
+		depth = payloadCapellaDepth
+	case payloadDenebDepth:
+		depth = payloadDenebDepth
+	default:
+		return fmt.Errorf("invalid execution block proof length %d", len(p.ExecutionBlockProof))
+	}
+	index := uint64(1)<<beaconBlockDepth | beaconBodyIndex
+	index = index<<bodyDepth | bodyPayloadIndex
+	index = index<<depth | payloadBlockHashIndex
+	if err := merkle.VerifyProof(p.BeaconBlockRoot, index, toValues(p.ExecutionBlockProof), merkle.Value(blockHash)); err != nil {
+		return fmt.Errorf("invalid execution block proof: %w", err)
+	}
+	index = uint64(SlotsPerHistoricalRoot) + p.Slot%SlotsPerHistoricalRoot
+	if len(p.BeaconBlockProof) != blockRootsDepth {
+		return fmt.Errorf("invalid beacon block proof length %d", len(p.BeaconBlockProof))
+	}
+	if err := merkle.VerifyProof(blockSummaryRoot, index, toValues(p.BeaconBlockProof), merkle.Value(p.BeaconBlockRoot)); err != nil {
+		return fmt.Errorf("invalid beacon block proof: %w", err)
+	}
+	return nil
+}
+
+// toValues converts a proof branch to the merkle package representation.
+func toValues(branch []common.Hash) merkle.Values {
+	values := make(merkle.Values, len(branch))
+	for i, h := range branch {
+		values[i] = merkle.Value(h)
+	}
+	return values
+}
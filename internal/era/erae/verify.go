@@ -0,0 +1,95 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package erae
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Verify checks the Era is well-formed and that its accumulator matches the
+// data it holds, using the given hasher to derive the transaction and receipt
+// roots. The proofs of the blocks are verified too if the historical
+// summaries are given, starting at the given period.
+func (e *Era) Verify(hasher types.TrieHasher, summaries []common.Hash, firstPeriod uint64) error {
+	want, err := e.Accumulator()
+	if err != nil {
+		return fmt.Errorf("error reading accumulator: %w", err)
+	}
+	it, err := NewIterator(e)
+	if err != nil {
+		return fmt.Errorf("error making era iterator: %w", err)
+	}
+	// On top of the per-block checks of Era1 files, the blocks must be linked
+	// together, and proven against the historical summaries if they are known.
+	var (
+		hashes []common.Hash
+		parent common.Hash
+	)
+	for it.Next() {
+		if it.Error() != nil {
+			return fmt.Errorf("error reading block %d: %w", it.Number(), it.Error())
+		}
+		block, err := it.Block()
+		if err != nil {
+			return fmt.Errorf("error reading block %d: %w", it.Number(), err)
+		}
+		receipts, err := it.Receipts()
+		if err != nil {
+			return fmt.Errorf("error reading receipts %d: %w", it.Number(), err)
+		}
+		if tr := types.DeriveSha(block.Transactions(), hasher); tr != block.TxHash() {
+			return fmt.Errorf("tx root in block %d mismatch: want %s, got %s", block.NumberU64(), block.TxHash(), tr)
+		}
+		if rr := types.DeriveSha(receipts, hasher); rr != block.ReceiptHash() {
+			return fmt.Errorf("receipt root in block %d mismatch: want %s, got %s", block.NumberU64(), block.ReceiptHash(), rr)
+		}
+		if len(hashes) > 0 && block.ParentHash() != parent {
+			return fmt.Errorf("block %d not linked to parent: want %s, got %s", block.NumberU64(), parent, block.ParentHash())
+		}
+		parent = block.Hash()
+		hashes = append(hashes, parent)
+
+		proof, err := it.Proof()
+		if err != nil {
+			return fmt.Errorf("error reading proof %d: %w", it.Number(), err)
+		}
+		if proof == nil || summaries == nil {
+			continue
+		}
+		period := proof.Period()
+		if period < firstPeriod || period-firstPeriod >= uint64(len(summaries)) {
+			return fmt.Errorf("no historical summary for block %d at period %d", block.NumberU64(), period)
+		}
+		if err := proof.Verify(parent, summaries[period-firstPeriod]); err != nil {
+			return fmt.Errorf("invalid proof for block %d: %w", block.NumberU64(), err)
+		}
+	}
+	if it.Error() != nil {
+		return fmt.Errorf("error reading block %d: %w", it.Number(), it.Error())
+	}
+	got, err := ComputeAccumulator(hashes)
+	if err != nil {
+		return fmt.Errorf("error computing accumulator: %w", err)
+	}
+	if got != want {
+		return fmt.Errorf("expected accumulator root does not match calculated: got %s, want %s", got, want)
+	}
+	return nil
+}
@@ -395,16 +395,7 @@ func (api *BlockChainAPI) GetProof(ctx context.Context, address common.Address,
 		}
 		// Create the proofs for the storageKeys.
 		for i, key := range keys {
-			// Output key encoding is a bit special: if the input was a 32-byte hash, it is
-			// returned as such. Otherwise, we apply the QUANTITY encoding mandated by the
-			// JSON-RPC spec for getProof. This behavior exists to preserve backwards
-			// compatibility with older client versions.
-			var outputKey string
-			if keyLengths[i] != 32 {
-				outputKey = hexutil.EncodeBig(key.Big())
-			} else {
-				outputKey = hexutil.Encode(key[:])
-			}
+			outputKey := encodeProofKey(key, keyLengths[i])
 			if storageTrie == nil {
 				storageProof[i] = StorageResult{outputKey, &hexutil.Big{}, []string{}}
 				continue
@@ -438,6 +429,139 @@ func (api *BlockChainAPI) GetProof(ctx context.Context, address common.Address,
 	}, statedb.Error()
 }
 
+// maxMultiProofKeys is the maximum number of accounts and storage slots which
+// can be proven by a single eth_getMultiProof call.
+const maxMultiProofKeys = 10000
+
+// MultiProofRequest is an account, along with some of its storage keys, to be
+// proven by eth_getMultiProof.
+type MultiProofRequest struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []string       `json:"storageKeys"`
+}
+
+// MultiProofResult is the result of eth_getMultiProof. The trie nodes shared
+// by the proofs are only included once, the proofs of the accounts and storage
+// slots referencing them by their index, root first.
+type MultiProofResult struct {
+	Nodes    []hexutil.Bytes      `json:"nodes"`
+	Accounts []MultiAccountResult `json:"accounts"`
+}
+
+type MultiAccountResult struct {
+	Address      common.Address       `json:"address"`
+	AccountProof []int                `json:"accountProof"`
+	Balance      *hexutil.Big         `json:"balance"`
+	CodeHash     common.Hash          `json:"codeHash"`
+	Nonce        hexutil.Uint64       `json:"nonce"`
+	StorageHash  common.Hash          `json:"storageHash"`
+	StorageProof []MultiStorageResult `json:"storageProof"`
+}
+
+type MultiStorageResult struct {
+	Key   string       `json:"key"`
+	Value *hexutil.Big `json:"value"`
+	Proof []int        `json:"proof"`
+}
+
+// GetMultiProof returns the Merkle-proofs for a batch of accounts and optionally
+// some of their storage keys, with the trie nodes deduplicated across all of them.
+func (api *BlockChainAPI) GetMultiProof(ctx context.Context, requests []MultiProofRequest, blockNrOrHash rpc.BlockNumberOrHash) (*MultiProofResult, error) {
+	var (
+		keys       = make([][]common.Hash, len(requests))
+		keyLengths = make([][]int, len(requests))
+		total      int
+	)
+	// Deserialize all keys. This prevents state access on invalid input.
+	for i, req := range requests {
+		total += 1 + len(req.StorageKeys)
+		if total > maxMultiProofKeys {
+			return nil, fmt.Errorf("too many keys requested, want at most %d", maxMultiProofKeys)
+		}
+		keys[i] = make([]common.Hash, len(req.StorageKeys))
+		keyLengths[i] = make([]int, len(req.StorageKeys))
+		for j, hexKey := range req.StorageKeys {
+			var err error
+			keys[i][j], keyLengths[i][j], err = decodeHash(hexKey)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	statedb, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	tr, err := trie.NewStateTrie(trie.StateTrieID(header.Root), statedb.Database().TrieDB())
+	if err != nil {
+		return nil, err
+	}
+	var (
+		proof    = trie.NewMultiProof()
+		accounts = make([]MultiAccountResult, len(requests))
+	)
+	for i, req := range requests {
+		var (
+			address      = req.Address
+			storageRoot  = statedb.GetStorageRoot(address)
+			storageProof = make([]MultiStorageResult, len(keys[i]))
+		)
+		if len(keys[i]) > 0 {
+			var storageTrie state.Trie
+			if storageRoot != types.EmptyRootHash && storageRoot != (common.Hash{}) {
+				id := trie.StorageTrieID(header.Root, crypto.Keccak256Hash(address.Bytes()), storageRoot)
+				st, err := trie.NewStateTrie(id, statedb.Database().TrieDB())
+				if err != nil {
+					return nil, err
+				}
+				storageTrie = st
+			}
+			for j, key := range keys[i] {
+				outputKey := encodeProofKey(key, keyLengths[i][j])
+				if storageTrie == nil {
+					storageProof[j] = MultiStorageResult{outputKey, &hexutil.Big{}, []int{}}
+					continue
+				}
+				keyProof := proof.NewKeyProof()
+				if err := storageTrie.Prove(crypto.Keccak256(key.Bytes()), keyProof); err != nil {
+					return nil, err
+				}
+				value := (*hexutil.Big)(statedb.GetState(address, key).Big())
+				storageProof[j] = MultiStorageResult{outputKey, value, keyProof.Indices}
+			}
+		}
+		accountProof := proof.NewKeyProof()
+		if err := tr.Prove(crypto.Keccak256(address.Bytes()), accountProof); err != nil {
+			return nil, err
+		}
+		accounts[i] = MultiAccountResult{
+			Address:      address,
+			AccountProof: accountProof.Indices,
+			Balance:      (*hexutil.Big)(statedb.GetBalance(address).ToBig()),
+			CodeHash:     statedb.GetCodeHash(address),
+			Nonce:        hexutil.Uint64(statedb.GetNonce(address)),
+			StorageHash:  storageRoot,
+			StorageProof: storageProof,
+		}
+	}
+	nodes := make([]hexutil.Bytes, len(proof.Nodes))
+	for i, node := range proof.Nodes {
+		nodes[i] = node
+	}
+	return &MultiProofResult{Nodes: nodes, Accounts: accounts}, statedb.Error()
+}
+
+// encodeProofKey encodes a storage key of a proof for the output. If the input
+// was a 32-byte hash, it is returned as such. Otherwise, we apply the QUANTITY
+// encoding mandated by the JSON-RPC spec for getProof. This behavior exists to
+// preserve backwards compatibility with older client versions.
+func encodeProofKey(key common.Hash, inputLength int) string {
+	if inputLength != 32 {
+		return hexutil.EncodeBig(key.Big())
+	}
+	return hexutil.Encode(key[:])
+}
+
 // decodeHash parses a hex-encoded 32-byte hash. The input may optionally
 // be prefixed by 0x and can have a byte length up to 32.
 func decodeHash(s string) (h common.Hash, inputLength int, err error) {
@@ -596,6 +720,9 @@ func (api *BlockChainAPI) GetStorageAt(ctx context.Context, address common.Addre
 func (api *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
 	block, err := api.b.BlockByNumberOrHash(ctx, blockNrOrHash)
 	if block == nil || err != nil {
+		if errors.As(err, new(*PrunedHistoryError)) {
+			return nil, err
+		}
 		// When the block doesn't exist, the RPC method should return JSON null
 		// as per specification.
 		return nil, nil
@@ -1413,6 +1540,12 @@ func (api *TransactionAPI) sign(addr common.Address, tx *types.Transaction) (*ty
 
 // SubmitTransaction is a helper function that submits tx to txPool and logs a message.
 func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
+	return submitTransaction(ctx, b, tx, false)
+}
+
+// submitTransaction is a helper function that submits tx to txPool either for
+// public propagation or for private inclusion, and logs a message.
+func submitTransaction(ctx context.Context, b Backend, tx *types.Transaction, private bool) (common.Hash, error) {
 	// If the transaction fee cap is already specified, ensure the
 	// fee of the given transaction is _reasonable_.
 	if err := checkTxFee(tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
@@ -1422,8 +1555,14 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 		// Ensure only eip155 signed transactions are submitted if EIP155Required is set.
 		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
 	}
-	if err := b.SendTx(ctx, tx); err != nil {
-		return common.Hash{}, err
+	if private {
+		if err := b.SendPrivateTx(ctx, tx); err != nil {
+			return common.Hash{}, err
+		}
+	} else {
+		if err := b.SendTx(ctx, tx); err != nil {
+			return common.Hash{}, err
+		}
 	}
 	// Print a log with full tx details for manual investigations and interventions
 	head := b.CurrentBlock()
@@ -1435,9 +1574,9 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 
 	if tx.To() == nil {
 		addr := crypto.CreateAddress(from, tx.Nonce())
-		log.Info("Submitted contract creation", "hash", tx.Hash().Hex(), "from", from, "nonce", tx.Nonce(), "contract", addr.Hex(), "value", tx.Value())
+		log.Info("Submitted contract creation", "hash", tx.Hash().Hex(), "from", from, "nonce", tx.Nonce(), "contract", addr.Hex(), "value", tx.Value(), "private", private)
 	} else {
-		log.Info("Submitted transaction", "hash", tx.Hash().Hex(), "from", from, "nonce", tx.Nonce(), "recipient", tx.To(), "value", tx.Value())
+		log.Info("Submitted transaction", "hash", tx.Hash().Hex(), "from", from, "nonce", tx.Nonce(), "recipient", tx.To(), "value", tx.Value(), "private", private)
 	}
 	return tx.Hash(), nil
 }
@@ -1506,6 +1645,30 @@ func (api *TransactionAPI) SendRawTransaction(ctx context.Context, input hexutil
 	return SubmitTransaction(ctx, api.b, tx)
 }
 
+// PrivateTransactionAPI offers the submission of transactions withheld from the
+// network. It lives in its own namespace, so operators have to explicitly expose
+// it over HTTP and WebSocket.
+type PrivateTransactionAPI struct {
+	b Backend
+}
+
+// NewPrivateTransactionAPI creates a new RPC service for private transactions.
+func NewPrivateTransactionAPI(b Backend) *PrivateTransactionAPI {
+	return &PrivateTransactionAPI{b}
+}
+
+// SendRawTransaction will add the signed transaction to the transaction pool,
+// but withhold it from the network: it will only be included in blocks built by
+// the local node. Once the configured private lifetime expires, the transaction
+// is either dropped or made public, depending on the pool config.
+func (api *PrivateTransactionAPI) SendRawTransaction(ctx context.Context, input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	return submitTransaction(ctx, api.b, tx, true)
+}
+
 // Sign calculates an ECDSA signature for:
 // keccak256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
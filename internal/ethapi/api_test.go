@@ -585,6 +585,9 @@ func (b testBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) even
 func (b testBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 	panic("implement me")
 }
+func (b testBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction) error {
+	panic("implement me")
+}
 func (b testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64, error) {
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(b.db, txHash)
 	return true, tx, blockHash, blockNumber, index, nil
@@ -2507,6 +2510,48 @@ func TestSendBlobTransaction(t *testing.T) {
 	}
 }
 
+// Tests that private transactions are submitted through their own namespace,
+// and not through the always enabled eth one.
+func TestPrivateTransactionNamespace(t *testing.T) {
+	t.Parallel()
+
+	genesis := &core.Genesis{
+		Config: params.MergedTestChainConfig,
+		Alloc:  types.GenesisAlloc{},
+	}
+	b := newTestBackend(t, 1, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+	})
+	server := rpc.NewServer()
+	defer server.Stop()
+	for _, api := range GetAPIs(b) {
+		if err := server.RegisterName(api.Namespace, api.Service); err != nil {
+			t.Fatalf("failed to register %s API: %v", api.Namespace, err)
+		}
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	const methodNotFound = -32601
+	for method, exists := range map[string]bool{
+		"private_sendRawTransaction":    true,
+		"eth_sendPrivateRawTransaction": false,
+	} {
+		var (
+			hash   common.Hash
+			err    = client.Call(&hash, method, hexutil.Bytes{0x01})
+			rpcErr rpc.Error
+		)
+		if err == nil {
+			t.Fatalf("%s: invalid transaction accepted", method)
+		}
+		missing := errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFound
+		if missing == exists {
+			t.Fatalf("%s: availability mismatch: have %v, want %v (%v)", method, !missing, exists, err)
+		}
+	}
+}
+
 func TestFillBlobTransaction(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
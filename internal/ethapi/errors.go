@@ -168,3 +168,20 @@ type blockGasLimitReachedError struct{ message string }
 
 func (e *blockGasLimitReachedError) Error() string  { return e.message }
 func (e *blockGasLimitReachedError) ErrorCode() int { return errCodeBlockGasLimitReached }
+
+// PrunedHistoryError is an API error that indicates the requested chain history
+// was pruned from the node, as specified by EIP-4444.
+type PrunedHistoryError struct{}
+
+// NewPrunedHistoryError creates a PrunedHistoryError instance.
+func NewPrunedHistoryError() *PrunedHistoryError { return &PrunedHistoryError{} }
+
+// Error implement error interface, returning the error message.
+func (e *PrunedHistoryError) Error() string {
+	return "pruned history unavailable"
+}
+
+// ErrorCode returns the JSON error code for pruned history.
+func (e *PrunedHistoryError) ErrorCode() int {
+	return 4444
+}
@@ -18,15 +18,16 @@
 package web3ext
 
 var Modules = map[string]string{
-	"admin":  AdminJs,
-	"clique": CliqueJs,
-	"debug":  DebugJs,
-	"eth":    EthJs,
-	"miner":  MinerJs,
-	"net":    NetJs,
-	"rpc":    RpcJs,
-	"txpool": TxpoolJs,
-	"dev":    DevJs,
+	"admin":   AdminJs,
+	"clique":  CliqueJs,
+	"debug":   DebugJs,
+	"eth":     EthJs,
+	"miner":   MinerJs,
+	"net":     NetJs,
+	"rpc":     RpcJs,
+	"txpool":  TxpoolJs,
+	"private": PrivateJs,
+	"dev":     DevJs,
 }
 
 const CliqueJs = `
@@ -468,6 +469,40 @@ web3._extend({
 			call: 'debug_getTrieFlushInterval',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'pruneState',
+			call: 'debug_pruneState',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getBlockAccessList',
+			call: 'debug_getBlockAccessList',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'witnessStats',
+			call: 'debug_witnessStats',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getAccountRange',
+			call: 'debug_getAccountRange',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getStorageRangeProof',
+			call: 'debug_getStorageRangeProof',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'trieScrubReport',
+			call: 'debug_trieScrubReport',
+			params: 0
+		}),
 	],
 	properties: []
 });
@@ -561,6 +596,12 @@ web3._extend({
 			params: 3,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getMultiProof',
+			call: 'eth_getMultiProof',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'createAccessList',
 			call: 'eth_createAccessList',
@@ -639,6 +680,21 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.utils.fromDecimal]
 		}),
+		new web3._extend.Method({
+			name: 'sendBundle',
+			call: 'miner_sendBundle',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'callBundle',
+			call: 'miner_callBundle',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getBundleStatus',
+			call: 'miner_getBundleStatus',
+			params: 1
+		}),
 	],
 	properties: []
 });
@@ -702,6 +758,19 @@ web3._extend({
 });
 `
 
+const PrivateJs = `
+web3._extend({
+	property: 'private',
+	methods: [
+		new web3._extend.Method({
+			name: 'sendRawTransaction',
+			call: 'private_sendRawTransaction',
+			params: 1
+		}),
+	]
+});
+`
+
 const DevJs = `
 web3._extend({
 	property: 'dev',
@@ -717,6 +786,29 @@ web3._extend({
 			call: 'dev_setFeeRecipient',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'skipSlots',
+			call: 'dev_skipSlots',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'reorg',
+			call: 'dev_reorg',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'setForkchoiceDelay',
+			call: 'dev_setForkchoiceDelay',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'scriptWithdrawals',
+			call: 'dev_scriptWithdrawals',
+			params: 1
+		}),
 	],
 });
 `
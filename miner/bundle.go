@@ -0,0 +1,344 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// maxBundles is the maximum number of bundles tracked by the miner across
+	// all target blocks.
+	maxBundles = 1024
+
+	// maxBundleTxs is the maximum number of transactions in a single bundle.
+	maxBundleTxs = 64
+
+	// bundleResultsCache is the number of bundle simulation results retained
+	// for status queries.
+	bundleResultsCache = 4096
+)
+
+var (
+	errBundleEmpty       = errors.New("bundle contains no transactions")
+	errBundleTooLarge    = errors.New("bundle contains too many transactions")
+	errBundleBlobTx      = errors.New("bundle contains blob transaction")
+	errBundleStale       = errors.New("bundle target block already passed")
+	errBundlePoolFull    = errors.New("bundle pool is full")
+	errBundleTxReverted  = errors.New("bundle transaction reverted")
+	errBundleTxDuplicate = errors.New("bundle transaction already included")
+)
+
+// Bundle is an ordered list of transactions which must be included atomically
+// and in order at the top of a specific block, or not at all.
+type Bundle struct {
+	Txs          types.Transactions // Transactions to include, in order
+	BlockNumber  uint64             // Number of the block the bundle targets
+	RevertingTxs []common.Hash      // Transactions allowed to revert without invalidating the bundle
+}
+
+// Hash returns the identifier of the bundle, derived from the target block and
+// the hashes of the contained transactions.
+func (b *Bundle) Hash() common.Hash {
+	hasher := crypto.NewKeccakState()
+	hasher.Write(new(big.Int).SetUint64(b.BlockNumber).Bytes())
+	for _, tx := range b.Txs {
+		hasher.Write(tx.Hash().Bytes())
+	}
+	var hash common.Hash
+	hasher.Read(hash[:])
+	return hash
+}
+
+// BundleTxResult is the outcome of executing a single transaction of a bundle.
+type BundleTxResult struct {
+	Hash     common.Hash // Hash of the executed transaction
+	GasUsed  uint64      // Gas consumed by the transaction
+	Reverted bool        // Whether the execution reverted
+}
+
+// BundleResult is the outcome of simulating a bundle on top of a block being
+// built.
+type BundleResult struct {
+	Hash        common.Hash      // Identifier of the simulated bundle
+	BlockNumber uint64           // Number of the block the bundle was simulated in
+	Profit      *big.Int         // Balance increase of the fee recipient caused by the bundle
+	GasUsed     uint64           // Total gas consumed by the bundle
+	Txs         []BundleTxResult // Execution results of the individual transactions
+	Included    bool             // Whether the bundle was included in the built block
+	Err         error            // Reason why the bundle was rejected, if any
+}
+
+// bundlePool tracks the bundles submitted for inclusion and the latest results
+// of simulating them.
+type bundlePool struct {
+	bundles map[common.Hash]*Bundle                // Bundles waiting for inclusion
+	results *lru.Cache[common.Hash, *BundleResult] // Latest simulation results by bundle hash
+	lock    sync.Mutex
+}
+
+func newBundlePool() *bundlePool {
+	return &bundlePool{
+		bundles: make(map[common.Hash]*Bundle),
+		results: lru.NewCache[common.Hash, *BundleResult](bundleResultsCache),
+	}
+}
+
+// add inserts a bundle into the pool if it is well formed and still targets
+// a future block.
+func (p *bundlePool) add(bundle *Bundle, head uint64) (common.Hash, error) {
+	if len(bundle.Txs) == 0 {
+		return common.Hash{}, errBundleEmpty
+	}
+	if len(bundle.Txs) > maxBundleTxs {
+		return common.Hash{}, fmt.Errorf("%w: have %d, max %d", errBundleTooLarge, len(bundle.Txs), maxBundleTxs)
+	}
+	for _, tx := range bundle.Txs {
+		if tx.Type() == types.BlobTxType {
+			return common.Hash{}, errBundleBlobTx
+		}
+	}
+	if bundle.BlockNumber <= head {
+		return common.Hash{}, fmt.Errorf("%w: target %d, head %d", errBundleStale, bundle.BlockNumber, head)
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	hash := bundle.Hash()
+	if _, ok := p.bundles[hash]; ok {
+		return hash, nil
+	}
+	if len(p.bundles) >= maxBundles {
+		p.prune(head + 1)
+		if len(p.bundles) >= maxBundles {
+			return common.Hash{}, errBundlePoolFull
+		}
+	}
+	p.bundles[hash] = bundle
+	return hash, nil
+}
+
+// pending returns the bundles targeting the given block number, dropping all
+// the ones whose target block already passed.
+func (p *bundlePool) pending(number uint64) []*Bundle {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.prune(number)
+
+	var bundles []*Bundle
+	for _, bundle := range p.bundles {
+		if bundle.BlockNumber == number {
+			bundles = append(bundles, bundle)
+		}
+	}
+	return bundles
+}
+
+// prune drops all bundles targeting blocks before the given number. The lock
+// must be held by the caller.
+func (p *bundlePool) prune(number uint64) {
+	for hash, bundle := range p.bundles {
+		if bundle.BlockNumber < number {
+			delete(p.bundles, hash)
+		}
+	}
+}
+
+// setResult records the latest simulation result of a bundle.
+func (p *bundlePool) setResult(result *BundleResult) {
+	p.results.Add(result.Hash, result)
+}
+
+// result retrieves the latest simulation result of a bundle.
+func (p *bundlePool) result(hash common.Hash) *BundleResult {
+	result, _ := p.results.Get(hash)
+	return result
+}
+
+// AddBundle submits a bundle for inclusion at the top of its target block. The
+// returned hash can be used to query the simulation results of the bundle.
+func (miner *Miner) AddBundle(bundle *Bundle) (common.Hash, error) {
+	return miner.bundles.add(bundle, miner.chain.CurrentBlock().Number.Uint64())
+}
+
+// BundleResult returns the outcome of the latest simulation of a bundle during
+// block building, or nil if the bundle was not simulated yet.
+func (miner *Miner) BundleResult(hash common.Hash) *BundleResult {
+	return miner.bundles.result(hash)
+}
+
+// SimulateBundle executes a bundle on top of the current chain head, without
+// submitting it for inclusion.
+func (miner *Miner) SimulateBundle(bundle *Bundle) (*BundleResult, error) {
+	if len(bundle.Txs) == 0 {
+		return nil, errBundleEmpty
+	}
+	miner.confMu.RLock()
+	coinbase := miner.config.PendingFeeRecipient
+	miner.confMu.RUnlock()
+
+	env, err := miner.prepareWork(&generateParams{
+		timestamp: miner.chain.CurrentBlock().Time + 1,
+		coinbase:  coinbase,
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+	env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+
+	return miner.simulateBundle(env, bundle), nil
+}
+
+// commitBundles simulates all bundles targeting the block being built, then
+// commits the most profitable ones on top of each other, skipping any bundle
+// which conflicts with the ones already included.
+func (miner *Miner) commitBundles(env *environment) {
+	bundles := miner.bundles.pending(env.header.Number.Uint64())
+	if len(bundles) == 0 {
+		return
+	}
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+	// Simulate all the bundles individually to rank them by profitability
+	var (
+		results = make(map[*Bundle]*BundleResult)
+		valid   []*Bundle
+	)
+	for _, bundle := range bundles {
+		result := miner.simulateBundle(env, bundle)
+		results[bundle] = result
+		if result.Err == nil {
+			valid = append(valid, bundle)
+		}
+	}
+	sort.SliceStable(valid, func(i, j int) bool {
+		return results[valid[i]].Profit.Cmp(results[valid[j]].Profit) > 0
+	})
+	// Commit the bundles in order of profitability, re-executing them on top of
+	// the previously committed ones to detect conflicts
+	for _, bundle := range valid {
+		result, err := miner.commitBundle(env, bundle)
+		if err != nil {
+			results[bundle].Err = err
+			log.Debug("Bundle conflicts with previous ones", "hash", results[bundle].Hash, "err", err)
+			continue
+		}
+		results[bundle] = result
+	}
+	for _, result := range results {
+		miner.bundles.setResult(result)
+	}
+}
+
+// simulateBundle executes a bundle on a copy of the given environment, leaving
+// the original untouched.
+func (miner *Miner) simulateBundle(env *environment, bundle *Bundle) *BundleResult {
+	result, _ := miner.executeBundle(env.copy(), bundle)
+	return result
+}
+
+// commitBundle executes a bundle on top of the given environment, committing
+// its transactions into the block if all of them are valid. On failure, the
+// environment is left unmodified.
+func (miner *Miner) commitBundle(env *environment, bundle *Bundle) (*BundleResult, error) {
+	work := env.copy()
+
+	result, receipts := miner.executeBundle(work, bundle)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	work.txs = append(work.txs, bundle.Txs...)
+	work.receipts = append(work.receipts, receipts...)
+	work.tcount += len(bundle.Txs)
+	*env = *work
+
+	result.Included = true
+	return result, nil
+}
+
+// executeBundle runs the transactions of a bundle on top of the given environment
+// and returns the execution results. The environment is modified even if the
+// bundle turns out invalid, so it should be a throwaway copy.
+func (miner *Miner) executeBundle(env *environment, bundle *Bundle) (*BundleResult, []*types.Receipt) {
+	var (
+		result = &BundleResult{
+			Hash:        bundle.Hash(),
+			BlockNumber: env.header.Number.Uint64(),
+			Profit:      new(big.Int),
+		}
+		receipts []*types.Receipt
+		balance  = env.state.GetBalance(env.coinbase).ToBig()
+	)
+	for i, tx := range bundle.Txs {
+		if slices.ContainsFunc(env.txs, func(included *types.Transaction) bool { return included.Hash() == tx.Hash() }) {
+			result.Err = fmt.Errorf("%w: %x", errBundleTxDuplicate, tx.Hash())
+			return result, nil
+		}
+		env.state.SetTxContext(tx.Hash(), env.tcount+i)
+
+		receipt, err := core.ApplyTransaction(env.evm, env.gasPool, env.state, env.header, tx, &env.header.GasUsed)
+		if err != nil {
+			result.Err = fmt.Errorf("tx %x: %w", tx.Hash(), err)
+			return result, nil
+		}
+		reverted := receipt.Status == types.ReceiptStatusFailed
+		result.Txs = append(result.Txs, BundleTxResult{
+			Hash:     tx.Hash(),
+			GasUsed:  receipt.GasUsed,
+			Reverted: reverted,
+		})
+		result.GasUsed += receipt.GasUsed
+		if reverted && !slices.Contains(bundle.RevertingTxs, tx.Hash()) {
+			result.Err = fmt.Errorf("%w: %x", errBundleTxReverted, tx.Hash())
+			return result, nil
+		}
+		receipts = append(receipts, receipt)
+	}
+	result.Profit.Sub(env.state.GetBalance(env.coinbase).ToBig(), balance)
+	return result, receipts
+}
+
+// copy creates a copy of the environment, deep enough for executing transactions
+// speculatively on it without affecting the original. State changes cannot be
+// rolled back via snapshots across transactions, since those finalise the state.
+func (env *environment) copy() *environment {
+	cpy := *env
+	cpy.state = env.state.Copy()
+	cpy.gasPool = new(core.GasPool).AddGas(env.gasPool.Gas())
+	cpy.header = types.CopyHeader(env.header)
+	cpy.evm = vm.NewEVM(env.evm.Context, cpy.state, env.evm.ChainConfig(), env.evm.Config)
+	cpy.txs = slices.Clone(env.txs)
+	cpy.receipts = slices.Clone(env.receipts)
+	cpy.sidecars = slices.Clone(env.sidecars)
+	cpy.witness = cpy.state.Witness()
+	return &cpy
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newBundleTx creates a transfer from the test bank with the given nonce and
+// gas price.
+func newBundleTx(nonce uint64, gasPrice int64) *types.Transaction {
+	return types.MustSignNewTx(testBankKey, types.LatestSigner(params.TestChainConfig), &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &testUserAddress,
+		Value:    big.NewInt(1000),
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(gasPrice),
+	})
+}
+
+// Tests that the most profitable bundles are placed at the top of the block,
+// that conflicting ones are skipped and that the results are reported back.
+func TestBuildPayloadWithBundles(t *testing.T) {
+	var (
+		db        = rawdb.NewMemoryDatabase()
+		recipient = common.HexToAddress("0xdeadbeef")
+	)
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), db, 0)
+
+	// Create two conflicting bundles (same nonce) with different profits, and
+	// one for a future block which must be left alone
+	var (
+		cheap = &Bundle{
+			Txs:         types.Transactions{newBundleTx(0, params.InitialBaseFee), newBundleTx(1, params.InitialBaseFee)},
+			BlockNumber: 1,
+		}
+		pricey = &Bundle{
+			Txs:         types.Transactions{newBundleTx(0, 3*params.InitialBaseFee)},
+			BlockNumber: 1,
+		}
+		future = &Bundle{
+			Txs:         types.Transactions{newBundleTx(5, 5*params.InitialBaseFee)},
+			BlockNumber: 2,
+		}
+	)
+	for _, bundle := range []*Bundle{cheap, pricey, future} {
+		if _, err := w.AddBundle(bundle); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+	if _, err := w.AddBundle(&Bundle{Txs: cheap.Txs, BlockNumber: 0}); !errors.Is(err, errBundleStale) {
+		t.Fatalf("stale bundle error mismatch: have %v, want %v", err, errBundleStale)
+	}
+	payload, err := w.buildPayload(&BuildPayloadArgs{
+		Parent:       b.chain.CurrentBlock().Hash(),
+		Timestamp:    uint64(time.Now().Unix()),
+		FeeRecipient: recipient,
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to build payload: %v", err)
+	}
+	full := payload.ResolveFull().ExecutionPayload
+
+	// The pricier bundle must be included alone, the pool transaction with the
+	// same nonce is invalidated by it
+	if len(full.Transactions) != 1 {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(full.Transactions), 1)
+	}
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(full.Transactions[0]); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if tx.Hash() != pricey.Txs[0].Hash() {
+		t.Fatalf("included transaction mismatch: have %x, want %x", tx.Hash(), pricey.Txs[0].Hash())
+	}
+	// Check that the simulation results reflect the outcome
+	if res := w.BundleResult(pricey.Hash()); res == nil || !res.Included || res.Err != nil {
+		t.Fatalf("profitable bundle result mismatch: %+v", res)
+	} else if want := new(big.Int).Mul(new(big.Int).Sub(pricey.Txs[0].GasPrice(), full.BaseFeePerGas), big.NewInt(int64(params.TxGas))); res.Profit.Cmp(want) != 0 {
+		t.Fatalf("profit mismatch: have %v, want %v", res.Profit, want)
+	}
+	if res := w.BundleResult(cheap.Hash()); res == nil || res.Included || res.Err == nil {
+		t.Fatalf("conflicting bundle result mismatch: %+v", res)
+	}
+	if res := w.BundleResult(future.Hash()); res != nil {
+		t.Fatalf("future bundle simulated: %+v", res)
+	}
+}
+
+// Tests that reverting transactions invalidate a bundle unless explicitly allowed.
+func TestSimulateBundleReverts(t *testing.T) {
+	w, _ := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+
+	// Deploy a contract whose init code hits an invalid opcode
+	revert := types.MustSignNewTx(testBankKey, types.LatestSigner(params.TestChainConfig), &types.LegacyTx{
+		Nonce:    0,
+		Gas:      100000,
+		GasPrice: big.NewInt(2 * params.InitialBaseFee),
+		Data:     []byte{0xfe},
+	})
+	bundle := &Bundle{Txs: types.Transactions{revert}, BlockNumber: 1}
+
+	res, err := w.SimulateBundle(bundle)
+	if err != nil {
+		t.Fatalf("failed to simulate bundle: %v", err)
+	}
+	if !errors.Is(res.Err, errBundleTxReverted) {
+		t.Fatalf("simulation error mismatch: have %v, want %v", res.Err, errBundleTxReverted)
+	}
+	bundle.RevertingTxs = []common.Hash{revert.Hash()}
+	if res, err = w.SimulateBundle(bundle); err != nil {
+		t.Fatalf("failed to simulate bundle: %v", err)
+	}
+	if res.Err != nil {
+		t.Fatalf("allowed revert rejected: %v", res.Err)
+	}
+	if len(res.Txs) != 1 || !res.Txs[0].Reverted {
+		t.Fatalf("transaction results mismatch: %+v", res.Txs)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// BuildInclusionList assembles an inclusion list (EIP-7805) for the block built
+// on top of the given parent, from the best paying pending pool transactions.
+// Private and blob transactions are never put into inclusion lists.
+func (miner *Miner) BuildInclusionList(parentHash common.Hash) (types.Transactions, error) {
+	parent := miner.chain.GetHeaderByHash(parentHash)
+	if parent == nil {
+		return nil, fmt.Errorf("unknown parent %x", parentHash)
+	}
+	miner.confMu.RLock()
+	tip := miner.config.GasPrice
+	miner.confMu.RUnlock()
+
+	filter := txpool.PendingFilter{
+		MinTip:       uint256.MustFromBig(tip),
+		OnlyPlainTxs: true,
+		NoPrivateTxs: true,
+	}
+	var baseFee *uint256.Int
+	if miner.chainConfig.IsLondon(parent.Number) {
+		baseFee = uint256.MustFromBig(eip1559.CalcBaseFee(miner.chainConfig, parent))
+		filter.BaseFee = baseFee
+	}
+	var (
+		signer = types.LatestSigner(miner.chainConfig)
+		txs    = newTransactionsByPriceAndNonce(signer, miner.txpool.Pending(filter), baseFee.ToBig())
+		list   types.Transactions
+		size   uint64
+	)
+	for {
+		ltx, _ := txs.Peek()
+		if ltx == nil {
+			break
+		}
+		tx := ltx.Resolve()
+		if tx == nil {
+			txs.Pop()
+			continue
+		}
+		if size+tx.Size() > params.MaxBytesPerInclusionList {
+			txs.Pop()
+			continue
+		}
+		list = append(list, tx)
+		size += tx.Size()
+		txs.Shift()
+	}
+	return list, nil
+}
+
+// commitInclusionList places the transactions of an inclusion list on top of
+// the block, skipping the ones which cannot be included.
+func (miner *Miner) commitInclusionList(env *environment, list types.Transactions) {
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+	for _, tx := range list {
+		if tx.Type() == types.BlobTxType {
+			continue
+		}
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+		if err := miner.commitTransaction(env, tx); err != nil {
+			log.Trace("Skipping inclusion list transaction", "hash", tx.Hash(), "err", err)
+		}
+	}
+}
@@ -48,6 +48,10 @@ type Config struct {
 	GasCeil             uint64         // Target gas ceiling for mined blocks.
 	GasPrice            *big.Int       // Minimum gas price for mining a transaction
 	Recommit            time.Duration  // The time interval for miner to re-create mining work.
+
+	Strategies        []string         `toml:",omitempty"` // Block building strategies competing for each payload
+	StrategyGasCap    uint64           `toml:",omitempty"` // Gas limit used by the gascap strategy
+	StrategyAllowlist []common.Address `toml:",omitempty"` // Senders included by the allowlist strategy
 }
 
 // DefaultConfig contains default settings for miner.
@@ -73,7 +77,9 @@ type Miner struct {
 	prio        []common.Address // A list of senders to prioritize
 	chain       *core.BlockChain
 	pending     *pending
-	pendingMu   sync.Mutex // Lock protects the pending block
+	pendingMu   sync.Mutex      // Lock protects the pending block
+	bundles     *bundlePool     // Bundles submitted for inclusion at the top of blocks
+	strategy    []BuildStrategy // Strategies competing for building each payload
 }
 
 // New creates a new miner with provided config.
@@ -85,6 +91,8 @@ func New(eth Backend, config Config, engine consensus.Engine) *Miner {
 		txpool:      eth.TxPool(),
 		chain:       eth.BlockChain(),
 		pending:     &pending{},
+		bundles:     newBundlePool(),
+		strategy:    makeStrategies(&config),
 	}
 }
 
@@ -117,6 +125,27 @@ func (miner *Miner) SetPrioAddresses(prio []common.Address) {
 	miner.confMu.Unlock()
 }
 
+// SetStrategies sets the block building strategies competing for each payload.
+// The first one is also used to build the pending block. If no strategies are
+// given, the configured ones are restored.
+func (miner *Miner) SetStrategies(strategies ...BuildStrategy) {
+	miner.confMu.Lock()
+	defer miner.confMu.Unlock()
+
+	if len(strategies) == 0 {
+		strategies = makeStrategies(miner.config)
+	}
+	miner.strategy = strategies
+}
+
+// strategies returns the block building strategies competing for each payload.
+func (miner *Miner) strategies() []BuildStrategy {
+	miner.confMu.RLock()
+	defer miner.confMu.RUnlock()
+
+	return miner.strategy
+}
+
 // SetGasCeil sets the gaslimit to strive for when mining blocks post 1559.
 // For pre-1559 blocks, it sets the ceiling.
 func (miner *Miner) SetGasCeil(ceil uint64) {
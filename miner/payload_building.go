@@ -78,9 +78,14 @@ type Payload struct {
 	emptyRequests [][]byte
 	requests      [][]byte
 	fullFees      *big.Int
-	stop          chan struct{}
-	lock          sync.Mutex
-	cond          *sync.Cond
+
+	inclusionList    types.Transactions // Inclusion list to satisfy (EIP-7805)
+	inclusionVersion uint64             // Number of inclusion list updates so far
+	rebuild          chan struct{}      // Notification channel to rebuild immediately
+
+	stop chan struct{}
+	lock sync.Mutex
+	cond *sync.Cond
 }
 
 // newPayload initializes the payload object.
@@ -90,6 +95,7 @@ func newPayload(empty *types.Block, emptyRequests [][]byte, witness *stateless.W
 		empty:         empty,
 		emptyRequests: emptyRequests,
 		emptyWitness:  witness,
+		rebuild:       make(chan struct{}, 1),
 		stop:          make(chan struct{}),
 	}
 	log.Info("Starting work on payload", "id", payload.id)
@@ -97,20 +103,29 @@ func newPayload(empty *types.Block, emptyRequests [][]byte, witness *stateless.W
 	return payload
 }
 
-// update updates the full-block with latest built version.
-func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
+// update updates the full-block with latest built version. It reports whether
+// the block became the best candidate of the payload.
+//
+// The version is the one of the inclusion list the block was built with, blocks
+// built with an outdated inclusion list are rejected.
+func (payload *Payload) update(r *newPayloadResult, version uint64, strategy string, elapsed time.Duration) bool {
 	payload.lock.Lock()
 	defer payload.lock.Unlock()
 
 	select {
 	case <-payload.stop:
-		return // reject stale update
+		return false // reject stale update
 	default:
 	}
 	// Ensure the newly provided full block has a higher transaction fee.
 	// In post-merge stage, there is no uncle reward anymore and transaction
 	// fee(apart from the mev revenue) is the only indicator for comparison.
+	if version != payload.inclusionVersion {
+		return false // reject block built with an outdated inclusion list
+	}
+	var best bool
 	if payload.full == nil || r.fees.Cmp(payload.fullFees) > 0 {
+		best = true
 		payload.full = r.block
 		payload.fullFees = r.fees
 		payload.sidecars = r.sidecars
@@ -127,10 +142,41 @@ func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
 			"gas", r.block.GasUsed(),
 			"fees", feesInEther,
 			"root", r.block.Root(),
+			"strategy", strategy,
 			"elapsed", common.PrettyDuration(elapsed),
 		)
 	}
 	payload.cond.Broadcast() // fire signal for notifying full block
+	return best
+}
+
+// Timestamp returns the timestamp of the block being built.
+func (payload *Payload) Timestamp() uint64 {
+	return payload.empty.Time()
+}
+
+// UpdateInclusionList sets the inclusion list (EIP-7805) which the payload has
+// to satisfy, triggering an immediate rebuild. Full blocks built beforehand are
+// discarded, the empty block is delivered until a new one is available.
+func (payload *Payload) UpdateInclusionList(list types.Transactions) {
+	payload.lock.Lock()
+	payload.inclusionList = list
+	payload.inclusionVersion++
+	payload.full = nil
+	payload.lock.Unlock()
+
+	select {
+	case payload.rebuild <- struct{}{}:
+	default:
+	}
+}
+
+// currentInclusionList returns the inclusion list to satisfy and its version.
+func (payload *Payload) currentInclusionList() (types.Transactions, uint64) {
+	payload.lock.Lock()
+	defer payload.lock.Unlock()
+
+	return payload.inclusionList, payload.inclusionVersion
 }
 
 // Resolve returns the latest built payload and also terminates the background
@@ -254,13 +300,10 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs, witness bool) (*Payload
 		for {
 			select {
 			case <-timer.C:
-				start := time.Now()
-				r := miner.generateWork(fullParams, witness)
-				if r.err == nil {
-					payload.update(r, time.Since(start))
-				} else {
-					log.Info("Error while generating work", "id", payload.id, "err", r.err)
-				}
+				miner.buildWithStrategies(payload, fullParams, witness)
+				timer.Reset(miner.config.Recommit)
+			case <-payload.rebuild:
+				miner.buildWithStrategies(payload, fullParams, witness)
 				timer.Reset(miner.config.Recommit)
 			case <-payload.stop:
 				log.Info("Stopping work on payload", "id", payload.id, "reason", "delivery")
@@ -273,3 +316,55 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs, witness bool) (*Payload
 	}()
 	return payload, nil
 }
+
+// buildWithStrategies builds a full block with each of the configured strategies
+// in parallel and updates the payload with the most valuable one once they have
+// all finished.
+func (miner *Miner) buildWithStrategies(payload *Payload, params *generateParams, witness bool) {
+	list, version := payload.currentInclusionList()
+
+	var (
+		start      = time.Now()
+		strategies = miner.strategies()
+		results    = make([]*newPayloadResult, len(strategies))
+		wg         sync.WaitGroup
+	)
+	for i, strategy := range strategies {
+		wg.Add(1)
+		go func(i int, strategy BuildStrategy) {
+			defer wg.Done()
+
+			var (
+				start  = time.Now()
+				meters = newStrategyMetrics(strategy.Name())
+			)
+			work := *params
+			work.strategy = strategy
+			work.inclusionList = list
+
+			r := miner.generateWork(&work, witness)
+			meters.timer.UpdateSince(start)
+			if r.err != nil {
+				meters.failed.Mark(1)
+				log.Info("Error while generating work", "id", payload.id, "strategy", strategy.Name(), "err", r.err)
+				return
+			}
+			meters.built.Mark(1)
+			results[i] = r
+		}(i, strategy)
+	}
+	wg.Wait()
+
+	best := -1
+	for i, r := range results {
+		if r != nil && (best == -1 || r.fees.Cmp(results[best].fees) > 0) {
+			best = i
+		}
+	}
+	if best == -1 {
+		return
+	}
+	if payload.update(results[best], version, strategies[best].Name(), time.Since(start)) {
+		newStrategyMetrics(strategies[best].Name()).best.Mark(1)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// Names of the built-in block building strategies.
+const (
+	GreedyStrategyName    = "greedy"
+	BundleStrategyName    = "bundle"
+	GasCapStrategyName    = "gascap"
+	AllowlistStrategyName = "allowlist"
+)
+
+// BuildStrategy is a policy for filling a block with transactions. Multiple
+// strategies may compete for the same payload, in which case they are run in
+// parallel and the most valuable block produced by any of them is delivered.
+type BuildStrategy interface {
+	// Name returns the identifier of the strategy, used in logs and metrics.
+	Name() string
+
+	// Fill populates the block being built with transactions.
+	Fill(ctx *BuildContext) error
+}
+
+// BuildContext is the handle through which a strategy inspects and fills the
+// block being built. It is only valid for the duration of a Fill call.
+type BuildContext struct {
+	miner     *Miner
+	env       *environment
+	interrupt *atomic.Int32
+}
+
+// Header returns a copy of the header of the block being built.
+func (ctx *BuildContext) Header() *types.Header {
+	return types.CopyHeader(ctx.env.header)
+}
+
+// GasLeft returns the amount of gas still available in the block.
+func (ctx *BuildContext) GasLeft() uint64 {
+	ctx.ensureGasPool()
+	return ctx.env.gasPool.Gas()
+}
+
+// CapGas restricts the total gas usable by the block to the given limit. It
+// cannot be used to raise the gas available above the block gas limit.
+func (ctx *BuildContext) CapGas(limit uint64) {
+	ctx.ensureGasPool()
+
+	used := ctx.env.header.GasUsed
+	if limit < used {
+		limit = used
+	}
+	if left := limit - used; left < ctx.env.gasPool.Gas() {
+		ctx.env.gasPool.SetGas(left)
+	}
+}
+
+// CommitBundles includes the most profitable non-conflicting bundles submitted
+// for the block being built.
+func (ctx *BuildContext) CommitBundles() {
+	ctx.miner.commitBundles(ctx.env)
+}
+
+// CommitPending fills the remaining space of the block with transactions from
+// the pool, ordered by price and nonce. If accept is non-nil, only the senders
+// it allows are considered.
+func (ctx *BuildContext) CommitPending(accept func(common.Address) bool) error {
+	return ctx.miner.fillTransactions(ctx.interrupt, ctx.env, accept)
+}
+
+// ensureGasPool initializes the gas pool of the block if no transactions were
+// committed yet.
+func (ctx *BuildContext) ensureGasPool() {
+	if ctx.env.gasPool == nil {
+		ctx.env.gasPool = new(core.GasPool).AddGas(ctx.env.header.GasLimit)
+	}
+}
+
+// greedyStrategy fills the block with pool transactions ordered by their tip.
+type greedyStrategy struct{}
+
+// NewGreedyStrategy creates a strategy filling blocks with the pool transactions
+// paying the highest tips.
+func NewGreedyStrategy() BuildStrategy { return greedyStrategy{} }
+
+func (greedyStrategy) Name() string { return GreedyStrategyName }
+
+func (greedyStrategy) Fill(ctx *BuildContext) error {
+	return ctx.CommitPending(nil)
+}
+
+// bundleStrategy places the submitted bundles on top of the block and fills the
+// rest with pool transactions ordered by their tip.
+type bundleStrategy struct{}
+
+// NewBundleStrategy creates a strategy placing the most profitable bundles at
+// the top of blocks, filling the rest with pool transactions.
+func NewBundleStrategy() BuildStrategy { return bundleStrategy{} }
+
+func (bundleStrategy) Name() string { return BundleStrategyName }
+
+func (bundleStrategy) Fill(ctx *BuildContext) error {
+	ctx.CommitBundles()
+	return ctx.CommitPending(nil)
+}
+
+// gasCapStrategy fills the block greedily, but up to a gas limit below the one
+// of the block.
+type gasCapStrategy struct {
+	limit uint64
+}
+
+// NewGasCapStrategy creates a strategy filling blocks greedily with at most the
+// given amount of gas.
+func NewGasCapStrategy(limit uint64) BuildStrategy { return gasCapStrategy{limit: limit} }
+
+func (gasCapStrategy) Name() string { return GasCapStrategyName }
+
+func (s gasCapStrategy) Fill(ctx *BuildContext) error {
+	ctx.CapGas(s.limit)
+	return ctx.CommitPending(nil)
+}
+
+// allowlistStrategy fills the block greedily, but only with transactions from
+// a set of allowed senders.
+type allowlistStrategy struct {
+	allowed map[common.Address]struct{}
+}
+
+// NewAllowlistStrategy creates a strategy filling blocks greedily, considering
+// only the transactions sent by the given accounts.
+func NewAllowlistStrategy(senders []common.Address) BuildStrategy {
+	allowed := make(map[common.Address]struct{}, len(senders))
+	for _, sender := range senders {
+		allowed[sender] = struct{}{}
+	}
+	return allowlistStrategy{allowed: allowed}
+}
+
+func (allowlistStrategy) Name() string { return AllowlistStrategyName }
+
+func (s allowlistStrategy) Fill(ctx *BuildContext) error {
+	return ctx.CommitPending(func(addr common.Address) bool {
+		_, ok := s.allowed[addr]
+		return ok
+	})
+}
+
+// makeStrategies creates the block building strategies listed in the config.
+// Unknown or misconfigured strategies are reported and skipped; if none remain,
+// the blocks are built with bundles on top, followed by the best paying pool
+// transactions.
+func makeStrategies(config *Config) []BuildStrategy {
+	var strategies []BuildStrategy
+	for _, name := range config.Strategies {
+		switch name {
+		case GreedyStrategyName:
+			strategies = append(strategies, NewGreedyStrategy())
+		case BundleStrategyName:
+			strategies = append(strategies, NewBundleStrategy())
+		case GasCapStrategyName:
+			if config.StrategyGasCap == 0 {
+				log.Error("Gas cap block building strategy without gas cap", "name", name)
+				continue
+			}
+			strategies = append(strategies, NewGasCapStrategy(config.StrategyGasCap))
+		case AllowlistStrategyName:
+			strategies = append(strategies, NewAllowlistStrategy(config.StrategyAllowlist))
+		default:
+			log.Error("Unknown block building strategy", "name", name)
+		}
+	}
+	if len(strategies) == 0 {
+		strategies = append(strategies, NewBundleStrategy())
+	}
+	return strategies
+}
+
+// strategyMetrics is the set of metrics tracked for a block building strategy.
+type strategyMetrics struct {
+	built  *metrics.Meter // Blocks successfully built by the strategy
+	failed *metrics.Meter // Block building attempts that failed
+	best   *metrics.Meter // Blocks that became the best candidate of their payload
+	timer  *metrics.Timer // Time spent building blocks
+}
+
+// newStrategyMetrics retrieves or registers the metrics of a named strategy.
+func newStrategyMetrics(name string) *strategyMetrics {
+	prefix := fmt.Sprintf("miner/strategy/%s/", name)
+	return &strategyMetrics{
+		built:  metrics.GetOrRegisterMeter(prefix+"built", nil),
+		failed: metrics.GetOrRegisterMeter(prefix+"failed", nil),
+		best:   metrics.GetOrRegisterMeter(prefix+"best", nil),
+		timer:  metrics.GetOrRegisterTimer(prefix+"time", nil),
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that competing strategies are all run for a payload and that the most
+// valuable block built by any of them is delivered.
+func TestBuildPayloadWithStrategies(t *testing.T) {
+	tests := []struct {
+		name       string
+		strategies []BuildStrategy
+		txs        int
+	}{
+		{"greedy", []BuildStrategy{NewGreedyStrategy()}, len(pendingTxs)},
+		{"allowlist-empty", []BuildStrategy{NewAllowlistStrategy(nil)}, 0},
+		{"allowlist-bank", []BuildStrategy{NewAllowlistStrategy([]common.Address{testBankAddress})}, len(pendingTxs)},
+		{"gascap-zero", []BuildStrategy{NewGasCapStrategy(0)}, 0},
+		{"competing", []BuildStrategy{NewAllowlistStrategy(nil), NewGasCapStrategy(0), NewGreedyStrategy()}, len(pendingTxs)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+			w.SetStrategies(tt.strategies...)
+
+			payload, err := w.buildPayload(&BuildPayloadArgs{
+				Parent:       b.chain.CurrentBlock().Hash(),
+				Timestamp:    uint64(time.Now().Unix()),
+				FeeRecipient: common.HexToAddress("0xdeadbeef"),
+			}, false)
+			if err != nil {
+				t.Fatalf("failed to build payload: %v", err)
+			}
+			full := payload.ResolveFull().ExecutionPayload
+			if len(full.Transactions) != tt.txs {
+				t.Fatalf("transaction count mismatch: have %d, want %d", len(full.Transactions), tt.txs)
+			}
+		})
+	}
+}
+
+// Tests that the configured strategy names are resolved, skipping unknown and
+// misconfigured ones.
+func TestMakeStrategies(t *testing.T) {
+	strategies := makeStrategies(&Config{Strategies: []string{"greedy", "unknown", "gascap", "allowlist"}, StrategyGasCap: 1_000_000})
+	if len(strategies) != 3 {
+		t.Fatalf("strategy count mismatch: have %d, want %d", len(strategies), 3)
+	}
+	for i, want := range []string{GreedyStrategyName, GasCapStrategyName, AllowlistStrategyName} {
+		if have := strategies[i].Name(); have != want {
+			t.Errorf("strategy %d name mismatch: have %s, want %s", i, have, want)
+		}
+	}
+	if strategies = makeStrategies(&Config{}); len(strategies) != 1 || strategies[0].Name() != BundleStrategyName {
+		t.Fatalf("default strategies mismatch: %v", strategies)
+	}
+	// A gas cap strategy without a cap would build empty blocks
+	if strategies = makeStrategies(&Config{Strategies: []string{"gascap"}}); len(strategies) != 1 || strategies[0].Name() != BundleStrategyName {
+		t.Fatalf("uncapped gas cap strategy not skipped: %v", strategies)
+	}
+}
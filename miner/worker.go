@@ -91,6 +91,9 @@ type generateParams struct {
 	withdrawals types.Withdrawals // List of withdrawals to include in block (shanghai field)
 	beaconRoot  *common.Hash      // The beacon root (cancun field).
 	noTxs       bool              // Flag whether an empty block without any transaction is expected
+	strategy    BuildStrategy     // Strategy filling the block, nil means the default one
+
+	inclusionList types.Transactions // Transactions to force-include (EIP-7805)
 }
 
 // generateWork generates a sealing block based on the given parameters.
@@ -106,9 +109,17 @@ func (miner *Miner) generateWork(params *generateParams, witness bool) *newPaylo
 		})
 		defer timer.Stop()
 
-		err := miner.fillTransactions(interrupt, work)
+		// Inclusion list transactions are mandatory, place them before anything else
+		if len(params.inclusionList) > 0 && miner.chainConfig.IsFOCIL(work.header.Number, work.header.Time) {
+			miner.commitInclusionList(work, params.inclusionList)
+		}
+		strategy := params.strategy
+		if strategy == nil {
+			strategy = miner.strategies()[0]
+		}
+		err := strategy.Fill(&BuildContext{miner: miner, env: work, interrupt: interrupt})
 		if errors.Is(err, errBlockInterruptedByTimeout) {
-			log.Warn("Block building is interrupted", "allowance", common.PrettyDuration(miner.config.Recommit))
+			log.Warn("Block building is interrupted", "strategy", strategy.Name(), "allowance", common.PrettyDuration(miner.config.Recommit))
 		}
 	}
 
@@ -277,6 +288,15 @@ func (miner *Miner) commitBlobTransaction(env *environment, tx *types.Transactio
 	if sc == nil {
 		panic("blob transaction without blobs in miner")
 	}
+	// The consensus layer expects cell proofs once Osaka is active and plain blob
+	// proofs before that, so transactions with a mismatching sidecar are skipped.
+	version := types.BlobSidecarVersion0
+	if miner.chainConfig.IsOsaka(env.header.Number, env.header.Time) {
+		version = types.BlobSidecarVersion1
+	}
+	if sc.Version != version {
+		return fmt.Errorf("blob sidecar version mismatch: have %d, want %d", sc.Version, version)
+	}
 	// Checking against blob gas limit: It's kind of ugly to perform this check here, but there
 	// isn't really a better place right now. The blob gas limit is checked at block validation time
 	// and not during execution. This means core.ApplyTransaction will not return an error if the
@@ -422,9 +442,9 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 }
 
 // fillTransactions retrieves the pending transactions from the txpool and fills them
-// into the given sealing block. The transaction selection and ordering strategy can
-// be customized with the plugin in the future.
-func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment) error {
+// into the given sealing block. If accept is non-nil, only the transactions of the
+// senders it allows are considered.
+func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment, accept func(common.Address) bool) error {
 	miner.confMu.RLock()
 	tip := miner.config.GasPrice
 	prio := miner.prio
@@ -446,6 +466,18 @@ func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment)
 	filter.OnlyPlainTxs, filter.OnlyBlobTxs = false, true
 	pendingBlobTxs := miner.txpool.Pending(filter)
 
+	if accept != nil {
+		for account := range pendingPlainTxs {
+			if !accept(account) {
+				delete(pendingPlainTxs, account)
+			}
+		}
+		for account := range pendingBlobTxs {
+			if !accept(account) {
+				delete(pendingBlobTxs, account)
+			}
+		}
+	}
 	// Split the pending transactions into locals and remotes.
 	prioPlainTxs, normalPlainTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingPlainTxs
 	prioBlobTxs, normalBlobTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingBlobTxs
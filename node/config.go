@@ -211,6 +211,11 @@ type Config struct {
 	EnablePersonal bool `toml:"-"`
 
 	DBEngine string `toml:",omitempty"`
+
+	// AncientTier is the directory of the secondary storage tier the sealed
+	// ancient data files of the chain database are offloaded to. The tier is
+	// disabled if empty. Relative paths are resolved in the instance directory.
+	AncientTier string `toml:",omitempty"`
 }
 
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
@@ -729,8 +729,9 @@ func (n *Node) OpenDatabase(name string, cache, handles int, namespace string, r
 // OpenDatabaseWithFreezer opens an existing database with the given name (or
 // creates one if no previous can be found) from within the node's data directory,
 // also attaching a chain freezer to it that moves ancient chain data from the
-// database to immutable append-only files. If the node is an ephemeral one, a
-// memory database is returned.
+// database to immutable append-only files. The sealed files are offloaded to the
+// configured storage tier, if any. If the node is an ephemeral one, a memory
+// database is returned.
 func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient string, namespace string, readonly bool) (ethdb.Database, error) {
 	n.lock.Lock()
 	defer n.lock.Unlock()
@@ -746,6 +747,7 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient
 			Type:              n.config.DBEngine,
 			Directory:         n.ResolvePath(name),
 			AncientsDirectory: n.ResolveAncient(name, ancient),
+			TierDirectory:     n.ResolveAncientTier(),
 			Namespace:         namespace,
 			Cache:             cache,
 			Handles:           handles,
@@ -774,6 +776,15 @@ func (n *Node) ResolveAncient(name string, ancient string) string {
 	return ancient
 }
 
+// ResolveAncientTier returns the absolute path of the secondary storage tier of
+// the ancient data, or the empty string if none is configured.
+func (n *Node) ResolveAncientTier() string {
+	if n.config.AncientTier == "" {
+		return ""
+	}
+	return n.ResolvePath(n.config.AncientTier)
+}
+
 // closeTrackingDB wraps the Close method of a database. When the database is closed by the
 // service, the wrapper removes it from the node's database map. This ensures that Node
 // won't auto-close the database if it is closed by the service that opened it.
@@ -377,6 +377,11 @@ type ChainConfig struct {
 	OsakaTime    *uint64 `json:"osakaTime,omitempty"`    // Osaka switch time (nil = no fork, 0 = already on osaka)
 	VerkleTime   *uint64 `json:"verkleTime,omitempty"`   // Verkle switch time (nil = no fork, 0 = already on verkle)
 
+	// FOCILTime is the switch time of the experimental fork-choice enforced
+	// inclusion lists (EIP-7805). It is meant for devnets and is not part of
+	// any scheduled network upgrade (nil = no fork, 0 = already on focil).
+	FOCILTime *uint64 `json:"focilTime,omitempty"`
+
 	// TerminalTotalDifficulty is the amount of total difficulty reached by
 	// the network that triggers the consensus upgrade.
 	TerminalTotalDifficulty *big.Int `json:"terminalTotalDifficulty,omitempty"`
@@ -396,6 +401,14 @@ type ChainConfig struct {
 	// those cases.
 	EnableVerkleAtGenesis bool `json:"enableVerkleAtGenesis,omitempty"`
 
+	// EnableBinaryTrie is a flag that specifies whether the state of a network
+	// with verkle enabled at genesis is stored in the binary merkle tree of
+	// EIP-7864 instead of the verkle tree.
+	//
+	// This is a temporary flag only for devnets testing the proposed tree
+	// transition, it has no effect without EnableVerkleAtGenesis.
+	EnableBinaryTrie bool `json:"enableBinaryTrie,omitempty"`
+
 	// Various consensus engines
 	Ethash             *EthashConfig       `json:"ethash,omitempty"`
 	Clique             *CliqueConfig       `json:"clique,omitempty"`
@@ -496,6 +509,9 @@ func (c *ChainConfig) Description() string {
 	if c.VerkleTime != nil {
 		banner += fmt.Sprintf(" - Verkle:                      @%-10v\n", *c.VerkleTime)
 	}
+	if c.FOCILTime != nil {
+		banner += fmt.Sprintf(" - FOCIL (experimental):        @%-10v\n", *c.FOCILTime)
+	}
 	return banner
 }
 
@@ -619,6 +635,11 @@ func (c *ChainConfig) IsVerkle(num *big.Int, time uint64) bool {
 	return c.IsLondon(num) && isTimestampForked(c.VerkleTime, time)
 }
 
+// IsFOCIL returns whether time is either equal to the FOCIL fork time or greater.
+func (c *ChainConfig) IsFOCIL(num *big.Int, time uint64) bool {
+	return c.IsLondon(num) && isTimestampForked(c.FOCILTime, time)
+}
+
 // IsVerkleGenesis checks whether the verkle fork is activated at the genesis block.
 //
 // Verkle mode is considered enabled if the verkle fork time is configured,
@@ -633,6 +654,12 @@ func (c *ChainConfig) IsVerkleGenesis() bool {
 	return c.EnableVerkleAtGenesis
 }
 
+// IsBinaryGenesis checks whether the state is stored in the binary merkle tree
+// of EIP-7864 instead of the verkle tree from the genesis block.
+func (c *ChainConfig) IsBinaryGenesis() bool {
+	return c.EnableVerkleAtGenesis && c.EnableBinaryTrie
+}
+
 // IsEIP4762 returns whether eip 4762 has been activated at given block.
 func (c *ChainConfig) IsEIP4762(num *big.Int, time uint64) bool {
 	return c.IsVerkle(num, time)
@@ -843,6 +870,9 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.VerkleTime, newcfg.VerkleTime, headTimestamp) {
 		return newTimestampCompatError("Verkle fork timestamp", c.VerkleTime, newcfg.VerkleTime)
 	}
+	if isForkTimestampIncompatible(c.FOCILTime, newcfg.FOCILTime, headTimestamp) {
+		return newTimestampCompatError("FOCIL fork timestamp", c.FOCILTime, newcfg.FOCILTime)
+	}
 	return nil
 }
 
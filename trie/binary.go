@@ -0,0 +1,563 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/trie/utils"
+	"github.com/ethereum/go-ethereum/triedb/database"
+	"github.com/holiman/uint256"
+)
+
+// BinaryTrie is a binary merkle tree (EIP-7864) implementing the state.Trie
+// interface. Like in the verkle tree, the accounts, storage slots and code
+// chunks are all stored in a single tree, grouped by stem, but the nodes are
+// hashed with sha256.
+//
+// The nodes are persisted by their path in the tree, the path being the bits
+// of the stem leading to them, one byte per bit.
+type BinaryTrie struct {
+	root   binaryNode
+	reader *trieReader
+	tracer *tracer
+}
+
+// NewBinaryTrie constructs a binary tree based on the specified root hash.
+func NewBinaryTrie(root common.Hash, db database.NodeDatabase) (*BinaryTrie, error) {
+	reader, err := newTrieReader(root, common.Hash{}, db)
+	if err != nil {
+		return nil, err
+	}
+	t := &BinaryTrie{
+		root:   binaryEmpty{},
+		reader: reader,
+		tracer: newTracer(),
+	}
+	// Resolve the root node if it's not empty.
+	if root != (common.Hash{}) && root != types.EmptyRootHash {
+		if t.root, err = t.resolve(binaryHashed(root), nil); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// GetKey returns the sha3 preimage of a hashed key that was previously used
+// to store a value.
+func (t *BinaryTrie) GetKey(key []byte) []byte {
+	return key
+}
+
+// GetAccount implements state.Trie, retrieving the account with the specified
+// account address. If the specified account is not in the binary tree, nil will
+// be returned. If the tree is corrupted, an error will be returned.
+func (t *BinaryTrie) GetAccount(addr common.Address) (*types.StateAccount, error) {
+	values, err := t.getStem(utils.BinaryBasicDataKey(addr[:])[:utils.BinaryStemSize])
+	if err != nil {
+		return nil, fmt.Errorf("GetAccount (%x) error: %v", addr, err)
+	}
+	if values == nil || values[utils.BasicDataLeafKey] == nil {
+		return nil, nil
+	}
+	basicData := values[utils.BasicDataLeafKey]
+	return &types.StateAccount{
+		Nonce:    binary.BigEndian.Uint64(basicData[utils.BasicDataNonceOffset:]),
+		Balance:  new(uint256.Int).SetBytes(basicData[utils.BasicDataBalanceOffset : utils.BasicDataBalanceOffset+16]),
+		CodeHash: common.CopyBytes(values[utils.CodeHashLeafKey]),
+	}, nil
+}
+
+// GetStorage implements state.Trie, retrieving the storage slot with the specified
+// account address and storage key. If the specified slot is not in the binary tree,
+// nil will be returned. If the tree is corrupted, an error will be returned.
+func (t *BinaryTrie) GetStorage(addr common.Address, key []byte) ([]byte, error) {
+	k := utils.BinaryStorageSlotKey(addr[:], key)
+	values, err := t.getStem(k[:utils.BinaryStemSize])
+	if err != nil || values == nil {
+		return nil, err
+	}
+	return common.TrimLeftZeroes(values[k[utils.BinaryStemSize]]), nil
+}
+
+// UpdateAccount implements state.Trie, writing the provided account into the tree.
+// If the tree is corrupted, an error will be returned.
+func (t *BinaryTrie) UpdateAccount(addr common.Address, acc *types.StateAccount, codeLen int) error {
+	var (
+		basicData [32]byte
+		values    = make([][]byte, binaryNodeWidth)
+	)
+	// The code size is encoded as a 3-byte big-endian integer, preceded by spare
+	// bytes for future extension, the offset is shifted to fit a uint32.
+	binary.BigEndian.PutUint32(basicData[utils.BasicDataCodeSizeOffset-1:], uint32(codeLen))
+	binary.BigEndian.PutUint64(basicData[utils.BasicDataNonceOffset:], acc.Nonce)
+	if acc.Balance.ByteLen() > 16 {
+		return fmt.Errorf("UpdateAccount (%x) error: balance %v too large", addr, acc.Balance)
+	}
+	acc.Balance.WriteToSlice(basicData[utils.BasicDataBalanceOffset : utils.BasicDataBalanceOffset+16])
+	values[utils.BasicDataLeafKey] = basicData[:]
+	values[utils.CodeHashLeafKey] = acc.CodeHash
+
+	if err := t.updateStem(utils.BinaryBasicDataKey(addr[:])[:utils.BinaryStemSize], values); err != nil {
+		return fmt.Errorf("UpdateAccount (%x) error: %v", addr, err)
+	}
+	return nil
+}
+
+// UpdateStorage implements state.Trie, writing the provided storage slot into
+// the tree. If the tree is corrupted, an error will be returned.
+func (t *BinaryTrie) UpdateStorage(addr common.Address, key, value []byte) error {
+	// Left padding the slot value to 32 bytes.
+	var v [32]byte
+	if len(value) >= 32 {
+		copy(v[:], value[:32])
+	} else {
+		copy(v[32-len(value):], value)
+	}
+	k := utils.BinaryStorageSlotKey(addr[:], key)
+	values := make([][]byte, binaryNodeWidth)
+	values[k[utils.BinaryStemSize]] = v[:]
+	return t.updateStem(k[:utils.BinaryStemSize], values)
+}
+
+// DeleteAccount implements state.Trie, removing the basic data and the code hash
+// of the account from the tree. The code chunks and the storage slots are left
+// untouched, as they can't be enumerated.
+func (t *BinaryTrie) DeleteAccount(addr common.Address) error {
+	k := utils.BinaryBasicDataKey(addr[:])
+	if err := t.deleteLeaves(k[:utils.BinaryStemSize], utils.BasicDataLeafKey, utils.CodeHashLeafKey); err != nil {
+		return fmt.Errorf("DeleteAccount (%x) error: %v", addr, err)
+	}
+	return nil
+}
+
+// DeleteStorage implements state.Trie, deleting the specified storage slot from
+// the tree. If the storage slot was not existent in the tree, no error will be
+// returned. If the tree is corrupted, an error will be returned.
+func (t *BinaryTrie) DeleteStorage(addr common.Address, key []byte) error {
+	k := utils.BinaryStorageSlotKey(addr[:], key)
+	return t.deleteLeaves(k[:utils.BinaryStemSize], int(k[utils.BinaryStemSize]))
+}
+
+// UpdateContractCode implements state.Trie, writing the provided contract code
+// into the tree. The chunks are grouped by stem to insert them at once.
+func (t *BinaryTrie) UpdateContractCode(addr common.Address, codeHash common.Hash, code []byte) error {
+	var (
+		chunks = ChunkifyCode(code)
+		values [][]byte
+		key    []byte
+	)
+	for i, chunknr := 0, uint64(0); i < len(chunks); i, chunknr = i+32, chunknr+1 {
+		groupOffset := (chunknr + 128) % 256
+		if groupOffset == 0 /* start of new group */ || chunknr == 0 /* first chunk in header group */ {
+			values = make([][]byte, binaryNodeWidth)
+			key = utils.BinaryCodeChunkKey(addr[:], uint256.NewInt(chunknr))
+		}
+		values[groupOffset] = chunks[i : i+32]
+
+		if groupOffset == 255 || len(chunks)-i <= 32 {
+			if err := t.updateStem(key[:utils.BinaryStemSize], values); err != nil {
+				return fmt.Errorf("UpdateContractCode (addr=%x) error: %w", addr[:], err)
+			}
+		}
+	}
+	return nil
+}
+
+// Hash returns the root hash of the tree. It does not write to the database and
+// can be used even if the tree doesn't have one.
+func (t *BinaryTrie) Hash() common.Hash {
+	return t.root.hash()
+}
+
+// Commit collects all dirty nodes in the tree and replaces them with their hash.
+// The nodes deleted from the tree are included in the returned set too, which is
+// nil if nothing changed. Once the tree is committed, it's not usable anymore.
+func (t *BinaryTrie) Commit(_ bool) (common.Hash, *trienode.NodeSet) {
+	root := t.Hash()
+
+	nodes := trienode.NewNodeSet(common.Hash{})
+	t.root = t.commit(t.root, nil, nodes)
+	for _, path := range t.tracer.deletedNodes() {
+		// Nodes relocated within the tree may overwrite the deleted ones
+		if _, ok := nodes.Nodes[path]; !ok {
+			nodes.AddNode([]byte(path), trienode.NewDeleted())
+		}
+	}
+	t.tracer.reset()
+
+	if len(nodes.Nodes) == 0 {
+		return root, nil
+	}
+	return root, nodes
+}
+
+// commit collects the dirty nodes of the subtree at the given path, returning
+// its hashed replacement.
+func (t *BinaryTrie) commit(n binaryNode, path []byte, nodes *trienode.NodeSet) binaryNode {
+	switch n := n.(type) {
+	case *binaryInternal:
+		if n.dirty {
+			for i := 0; i < 2; i++ {
+				n.children[i] = t.commit(n.children[i], append(path[:len(path):len(path)], byte(i)), nodes)
+			}
+			nodes.AddNode(path, trienode.New(n.hash(), serializeBinaryNode(n)))
+		}
+		return binaryHashed(n.hash())
+	case *binaryStem:
+		if n.dirty {
+			nodes.AddNode(path, trienode.New(n.hash(), serializeBinaryNode(n)))
+		}
+		return binaryHashed(n.hash())
+	default:
+		return n
+	}
+}
+
+// NodeIterator implements state.Trie. Iterating the binary tree is not supported
+// yet, an error is always returned.
+func (t *BinaryTrie) NodeIterator(startKey []byte) (NodeIterator, error) {
+	return nil, errors.New("binary tree iteration is not supported")
+}
+
+// Prove implements state.Trie, constructing a Merkle proof for key, which is a
+// 32-byte tree key. The result contains all encoded nodes on the path to the
+// stem of the key, keyed by their hash. The stem node includes the value, which
+// can be retrieved by verifying the proof with VerifyBinaryProof.
+//
+// If the tree does not contain the stem of the key, the returned proof contains
+// all nodes of the longest existing prefix of it, ending either with an empty
+// child or with a node of another stem, proving the absence of the key.
+func (t *BinaryTrie) Prove(key []byte, proofDb ethdb.KeyValueWriter) error {
+	if len(key) != common.HashLength {
+		return fmt.Errorf("invalid binary tree key length %d", len(key))
+	}
+	var (
+		ref  = &t.root
+		path []byte
+	)
+	for {
+		if h, ok := (*ref).(binaryHashed); ok {
+			n, err := t.resolve(h, path)
+			if err != nil {
+				return err
+			}
+			*ref = n
+		}
+		switch n := (*ref).(type) {
+		case binaryEmpty:
+			return nil
+		case *binaryStem:
+			hash := n.hash()
+			return proofDb.Put(hash[:], serializeBinaryNode(n))
+		case *binaryInternal:
+			hash := n.hash()
+			if err := proofDb.Put(hash[:], serializeBinaryNode(n)); err != nil {
+				return err
+			}
+			bit := stemBit(key, len(path))
+			ref, path = &n.children[bit], append(path, bit)
+		}
+	}
+}
+
+// VerifyBinaryProof checks the merkle proof of a binary tree key against the
+// given root hash. The value of the key is returned, or nil if the proof shows
+// its absence. An error is returned if the proof is invalid.
+func VerifyBinaryProof(rootHash common.Hash, key []byte, proofDb ethdb.KeyValueReader) ([]byte, error) {
+	if len(key) != common.HashLength {
+		return nil, fmt.Errorf("invalid binary tree key length %d", len(key))
+	}
+	want := rootHash
+	for depth := 0; ; depth++ {
+		if want == (common.Hash{}) {
+			return nil, nil // empty subtree, the key is not present
+		}
+		blob, _ := proofDb.Get(want[:])
+		if blob == nil {
+			return nil, fmt.Errorf("proof node %d (hash %064x) missing", depth, want)
+		}
+		n, err := deserializeBinaryNode(blob)
+		if err != nil {
+			return nil, fmt.Errorf("bad proof node %d: %v", depth, err)
+		}
+		if n.hash() != want {
+			return nil, fmt.Errorf("proof node %d hash mismatch: have %x, want %x", depth, n.hash(), want)
+		}
+		switch n := n.(type) {
+		case *binaryStem:
+			if !bytes.Equal(n.stem, key[:utils.BinaryStemSize]) {
+				return nil, nil // another stem, the key is not present
+			}
+			return n.values[key[utils.BinaryStemSize]], nil
+		case *binaryInternal:
+			if depth >= utils.BinaryStemSize*8 {
+				return nil, fmt.Errorf("proof node %d beyond the stem", depth)
+			}
+			want = n.children[stemBit(key, depth)].hash()
+		}
+	}
+}
+
+// Copy returns a deep-copied binary tree.
+func (t *BinaryTrie) Copy() *BinaryTrie {
+	return &BinaryTrie{
+		root:   t.root.copy(),
+		reader: t.reader,
+		tracer: t.tracer.copy(),
+	}
+}
+
+// IsVerkle indicates if the trie is a Verkle trie. The binary tree shares the
+// single tree layout of the verkle tree, and is handled the same way.
+func (t *BinaryTrie) IsVerkle() bool {
+	return true
+}
+
+// Witness returns a set containing all trie nodes that have been accessed.
+func (t *BinaryTrie) Witness() map[string]struct{} {
+	if len(t.tracer.accessList) == 0 {
+		return nil
+	}
+	witness := make(map[string]struct{}, len(t.tracer.accessList))
+	for _, node := range t.tracer.accessList {
+		witness[string(node)] = struct{}{}
+	}
+	return witness
+}
+
+// resolve loads the hashed node at the given path from the database.
+func (t *BinaryTrie) resolve(hash binaryHashed, path []byte) (binaryNode, error) {
+	blob, err := t.reader.node(path, common.Hash(hash))
+	if err != nil {
+		return nil, err
+	}
+	t.tracer.onRead(path, blob)
+
+	n, err := deserializeBinaryNode(blob)
+	if err != nil {
+		return nil, fmt.Errorf("node %x at path %x: %w", common.Hash(hash), path, err)
+	}
+	cached := common.Hash(hash)
+	switch n := n.(type) {
+	case *binaryInternal:
+		n.cached = &cached
+	case *binaryStem:
+		n.cached = &cached
+	}
+	return n, nil
+}
+
+// getStem returns the leaves of the given stem, or nil if it's not in the tree.
+// The returned values must not be modified.
+func (t *BinaryTrie) getStem(stem []byte) ([][]byte, error) {
+	var (
+		ref  = &t.root
+		path []byte
+	)
+	for {
+		if h, ok := (*ref).(binaryHashed); ok {
+			n, err := t.resolve(h, path)
+			if err != nil {
+				return nil, err
+			}
+			*ref = n
+		}
+		switch n := (*ref).(type) {
+		case binaryEmpty:
+			return nil, nil
+		case *binaryStem:
+			if !bytes.Equal(n.stem, stem) {
+				return nil, nil
+			}
+			return n.values, nil
+		case *binaryInternal:
+			bit := stemBit(stem, len(path))
+			ref, path = &n.children[bit], append(path, bit)
+		}
+	}
+}
+
+// updateStem writes the non-nil values into the leaves of the given stem.
+func (t *BinaryTrie) updateStem(stem []byte, values [][]byte) error {
+	root, err := t.insert(t.root, stem, values, nil)
+	if err != nil {
+		return err
+	}
+	t.root = root
+	return nil
+}
+
+// insert writes the non-nil values into the leaves of the given stem in the
+// subtree at the given path, returning the updated subtree.
+func (t *BinaryTrie) insert(n binaryNode, stem []byte, values [][]byte, path []byte) (binaryNode, error) {
+	switch n := n.(type) {
+	case binaryEmpty:
+		leaf := &binaryStem{
+			stem:   common.CopyBytes(stem),
+			values: make([][]byte, binaryNodeWidth),
+			dirty:  true,
+		}
+		for i, value := range values {
+			if value != nil {
+				leaf.values[i] = common.CopyBytes(value)
+			}
+		}
+		return leaf, nil
+
+	case binaryHashed:
+		resolved, err := t.resolve(n, path)
+		if err != nil {
+			return nil, err
+		}
+		return t.insert(resolved, stem, values, path)
+
+	case *binaryStem:
+		if bytes.Equal(n.stem, stem) {
+			for i, value := range values {
+				if value != nil {
+					n.values[i] = common.CopyBytes(value)
+				}
+			}
+			n.cached, n.dirty = nil, true
+			return n, nil
+		}
+		// The stems diverge, push the existing stem one level down and retry
+		// the insertion from the new internal node in its place.
+		internal := &binaryInternal{children: [2]binaryNode{binaryEmpty{}, binaryEmpty{}}, dirty: true}
+		internal.children[stemBit(n.stem, len(path))] = n
+		n.dirty = true
+		return t.insert(internal, stem, values, path)
+
+	case *binaryInternal:
+		bit := stemBit(stem, len(path))
+		child, err := t.insert(n.children[bit], stem, values, append(path[:len(path):len(path)], bit))
+		if err != nil {
+			return nil, err
+		}
+		n.children[bit] = child
+		n.cached, n.dirty = nil, true
+		return n, nil
+
+	default:
+		panic(fmt.Sprintf("%T: invalid node", n))
+	}
+}
+
+// deleteLeaves removes the given leaves of a stem from the tree.
+func (t *BinaryTrie) deleteLeaves(stem []byte, indices ...int) error {
+	root, _, err := t.delete(t.root, stem, indices, nil)
+	if err != nil {
+		return err
+	}
+	t.root = root
+	return nil
+}
+
+// delete removes the given leaves of a stem from the subtree at the given path,
+// returning the updated subtree and whether it changed. Stem nodes left without
+// leaves are removed, and the internal nodes left with a single stem below them
+// are collapsed to keep the tree in its canonical form.
+func (t *BinaryTrie) delete(n binaryNode, stem []byte, indices []int, path []byte) (binaryNode, bool, error) {
+	switch n := n.(type) {
+	case binaryEmpty:
+		return n, false, nil
+
+	case binaryHashed:
+		resolved, err := t.resolve(n, path)
+		if err != nil {
+			return nil, false, err
+		}
+		return t.delete(resolved, stem, indices, path)
+
+	case *binaryStem:
+		if !bytes.Equal(n.stem, stem) {
+			return n, false, nil
+		}
+		var changed bool
+		for _, i := range indices {
+			if n.values[i] != nil {
+				n.values[i], changed = nil, true
+			}
+		}
+		if !changed {
+			return n, false, nil
+		}
+		if n.empty() {
+			t.tracer.onDelete(path)
+			return binaryEmpty{}, true, nil
+		}
+		n.cached, n.dirty = nil, true
+		return n, true, nil
+
+	case *binaryInternal:
+		var (
+			bit       = stemBit(stem, len(path))
+			childPath = append(path[:len(path):len(path)], bit)
+			sibPath   = append(path[:len(path):len(path)], 1-bit)
+		)
+		child, changed, err := t.delete(n.children[bit], stem, indices, childPath)
+		if err != nil {
+			return nil, false, err
+		}
+		n.children[bit] = child
+		if !changed {
+			return n, false, nil
+		}
+		n.cached, n.dirty = nil, true
+
+		// If the subtree became empty, the sibling may need to replace the node
+		if _, ok := child.(binaryEmpty); ok {
+			if h, ok := n.children[1-bit].(binaryHashed); ok {
+				sibling, err := t.resolve(h, sibPath)
+				if err != nil {
+					return nil, false, err
+				}
+				n.children[1-bit] = sibling
+			}
+			switch sibling := n.children[1-bit].(type) {
+			case binaryEmpty:
+				t.tracer.onDelete(path)
+				return binaryEmpty{}, true, nil
+			case *binaryStem:
+				t.tracer.onDelete(sibPath)
+				sibling.dirty = true
+				return sibling, true, nil
+			}
+			return n, true, nil
+		}
+		// If a stem was pulled up from below without sibling, keep pulling it
+		if leaf, ok := child.(*binaryStem); ok {
+			if _, ok := n.children[1-bit].(binaryEmpty); ok {
+				t.tracer.onDelete(childPath)
+				leaf.dirty = true
+				return leaf, true, nil
+			}
+		}
+		return n, true, nil
+
+	default:
+		panic(fmt.Sprintf("%T: invalid node", n))
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/trie/utils"
+)
+
+const (
+	binaryNodeWidth  = 256 // Number of leaves grouped under a stem
+	binaryValueSize  = 32  // Size of the leaf values
+	binaryBitmapSize = binaryNodeWidth / 8
+
+	binaryInternalType = 1 // Serialization prefix of internal nodes
+	binaryStemType     = 2 // Serialization prefix of stem nodes
+)
+
+var errInvalidBinaryNode = errors.New("invalid binary tree node")
+
+// binaryNode is a node of the binary merkle tree (EIP-7864), which is one of
+// binaryEmpty, binaryHashed, *binaryInternal or *binaryStem.
+type binaryNode interface {
+	hash() common.Hash
+	copy() binaryNode
+}
+
+type (
+	// binaryEmpty is an empty subtree, hashing to zero.
+	binaryEmpty struct{}
+
+	// binaryHashed is a subtree persisted in the database, not resolved yet.
+	binaryHashed common.Hash
+
+	// binaryInternal is an internal node, branching on the bit of the stem
+	// at its depth.
+	binaryInternal struct {
+		children [2]binaryNode
+		cached   *common.Hash // Cached hash of the node, nil if modified since
+		dirty    bool         // Flag whether the node needs to be persisted
+	}
+
+	// binaryStem is a node holding the leaves of a stem.
+	binaryStem struct {
+		stem   []byte
+		values [][]byte
+		cached *common.Hash
+		dirty  bool
+	}
+)
+
+func (n binaryEmpty) hash() common.Hash  { return common.Hash{} }
+func (n binaryHashed) hash() common.Hash { return common.Hash(n) }
+
+func (n binaryEmpty) copy() binaryNode  { return n }
+func (n binaryHashed) copy() binaryNode { return n }
+
+func (n *binaryInternal) hash() common.Hash {
+	if n.cached == nil {
+		h := binaryHash(n.children[0].hash(), n.children[1].hash())
+		n.cached = &h
+	}
+	return *n.cached
+}
+
+func (n *binaryInternal) copy() binaryNode {
+	return &binaryInternal{
+		children: [2]binaryNode{n.children[0].copy(), n.children[1].copy()},
+		cached:   n.cached,
+		dirty:    n.dirty,
+	}
+}
+
+func (n *binaryStem) hash() common.Hash {
+	if n.cached != nil {
+		return *n.cached
+	}
+	// Merkleize the hashes of the leaves pairwise, up to the subtree root
+	level := make([]common.Hash, binaryNodeWidth)
+	for i, value := range n.values {
+		if value != nil {
+			level[i] = sha256.Sum256(value)
+		}
+	}
+	for len(level) > 1 {
+		for i := 0; i < len(level)/2; i++ {
+			level[i] = binaryHash(level[2*i], level[2*i+1])
+		}
+		level = level[:len(level)/2]
+	}
+	var buf [utils.BinaryStemSize + 1 + common.HashLength]byte
+	copy(buf[:], n.stem)
+	copy(buf[utils.BinaryStemSize+1:], level[0][:])
+	h := common.Hash(sha256.Sum256(buf[:]))
+	n.cached = &h
+	return h
+}
+
+func (n *binaryStem) copy() binaryNode {
+	values := make([][]byte, binaryNodeWidth)
+	for i, value := range n.values {
+		values[i] = common.CopyBytes(value)
+	}
+	return &binaryStem{
+		stem:   common.CopyBytes(n.stem),
+		values: values,
+		cached: n.cached,
+		dirty:  n.dirty,
+	}
+}
+
+// empty reports whether the stem node has no leaves left.
+func (n *binaryStem) empty() bool {
+	for _, value := range n.values {
+		if value != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// binaryHash hashes a pair of child hashes. Per EIP-7864, the hash of a pair
+// of empty subtrees is zero instead of the sha256 of the zero bytes.
+func binaryHash(left, right common.Hash) common.Hash {
+	if left == (common.Hash{}) && right == (common.Hash{}) {
+		return common.Hash{}
+	}
+	var buf [2 * common.HashLength]byte
+	copy(buf[:], left[:])
+	copy(buf[common.HashLength:], right[:])
+	return sha256.Sum256(buf[:])
+}
+
+// stemBit returns the bit of the stem at the given depth, the first bit being
+// the most significant bit of the first byte.
+func stemBit(stem []byte, depth int) byte {
+	return (stem[depth/8] >> (7 - depth%8)) & 1
+}
+
+// serializeBinaryNode encodes an internal node as the type prefix followed by
+// the hashes of its children, and a stem node as the type prefix followed by
+// the stem, the bitmap of the present leaves and their values.
+func serializeBinaryNode(n binaryNode) []byte {
+	switch n := n.(type) {
+	case *binaryInternal:
+		blob := make([]byte, 1+2*common.HashLength)
+		blob[0] = binaryInternalType
+		left, right := n.children[0].hash(), n.children[1].hash()
+		copy(blob[1:], left[:])
+		copy(blob[1+common.HashLength:], right[:])
+		return blob
+	case *binaryStem:
+		var (
+			bitmap [binaryBitmapSize]byte
+			values []byte
+		)
+		for i, value := range n.values {
+			if value != nil {
+				bitmap[i/8] |= 1 << (7 - i%8)
+				values = append(values, value...)
+			}
+		}
+		blob := make([]byte, 0, 1+utils.BinaryStemSize+binaryBitmapSize+len(values))
+		blob = append(blob, binaryStemType)
+		blob = append(blob, n.stem...)
+		blob = append(blob, bitmap[:]...)
+		return append(blob, values...)
+	default:
+		panic(fmt.Sprintf("%T: invalid node to serialize", n))
+	}
+}
+
+// deserializeBinaryNode decodes a node encoded by serializeBinaryNode.
+func deserializeBinaryNode(blob []byte) (binaryNode, error) {
+	if len(blob) == 0 {
+		return nil, errInvalidBinaryNode
+	}
+	switch blob[0] {
+	case binaryInternalType:
+		if len(blob) != 1+2*common.HashLength {
+			return nil, fmt.Errorf("%w: internal node size %d", errInvalidBinaryNode, len(blob))
+		}
+		n := new(binaryInternal)
+		for i := 0; i < 2; i++ {
+			if h := common.BytesToHash(blob[1+i*common.HashLength : 1+(i+1)*common.HashLength]); h != (common.Hash{}) {
+				n.children[i] = binaryHashed(h)
+			} else {
+				n.children[i] = binaryEmpty{}
+			}
+		}
+		return n, nil
+
+	case binaryStemType:
+		if len(blob) < 1+utils.BinaryStemSize+binaryBitmapSize {
+			return nil, fmt.Errorf("%w: stem node size %d", errInvalidBinaryNode, len(blob))
+		}
+		n := &binaryStem{
+			stem:   common.CopyBytes(blob[1 : 1+utils.BinaryStemSize]),
+			values: make([][]byte, binaryNodeWidth),
+		}
+		var (
+			bitmap = blob[1+utils.BinaryStemSize : 1+utils.BinaryStemSize+binaryBitmapSize]
+			values = blob[1+utils.BinaryStemSize+binaryBitmapSize:]
+		)
+		for i := 0; i < binaryNodeWidth; i++ {
+			if bitmap[i/8]&(1<<(7-i%8)) == 0 {
+				continue
+			}
+			if len(values) < binaryValueSize {
+				return nil, fmt.Errorf("%w: stem node values truncated", errInvalidBinaryNode)
+			}
+			n.values[i] = common.CopyBytes(values[:binaryValueSize])
+			values = values[binaryValueSize:]
+		}
+		if len(values) != 0 {
+			return nil, fmt.Errorf("%w: %d trailing bytes in stem node", errInvalidBinaryNode, len(values))
+		}
+		return n, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown type %d", errInvalidBinaryNode, blob[0])
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/trie/utils"
+	"github.com/holiman/uint256"
+)
+
+func TestBinaryTreeReadWrite(t *testing.T) {
+	db := newTestDatabase(rawdb.NewMemoryDatabase(), rawdb.PathScheme)
+	tr, _ := NewBinaryTrie(common.Hash{}, db)
+
+	for addr, acct := range accounts {
+		if err := tr.UpdateAccount(addr, acct, 0); err != nil {
+			t.Fatalf("Failed to update account, %v", err)
+		}
+		for key, val := range storages[addr] {
+			if err := tr.UpdateStorage(addr, key.Bytes(), val); err != nil {
+				t.Fatalf("Failed to update storage, %v", err)
+			}
+		}
+	}
+	for addr, acct := range accounts {
+		stored, err := tr.GetAccount(addr)
+		if err != nil {
+			t.Fatalf("Failed to get account, %v", err)
+		}
+		if !reflect.DeepEqual(stored, acct) {
+			t.Fatal("account is not matched")
+		}
+		for key, val := range storages[addr] {
+			stored, err := tr.GetStorage(addr, key.Bytes())
+			if err != nil {
+				t.Fatalf("Failed to get storage, %v", err)
+			}
+			if !bytes.Equal(stored, val) {
+				t.Fatal("storage is not matched")
+			}
+		}
+	}
+	if acct, err := tr.GetAccount(common.Address{3}); acct != nil || err != nil {
+		t.Fatalf("Unexpected account: %v, %v", acct, err)
+	}
+}
+
+// Tests the hashing of a tree holding a single stem against the EIP-7864
+// definition.
+func TestBinaryTreeHash(t *testing.T) {
+	tr, _ := NewBinaryTrie(common.Hash{}, newTestDatabase(rawdb.NewMemoryDatabase(), rawdb.PathScheme))
+	if tr.Hash() != (common.Hash{}) {
+		t.Fatalf("Empty tree hash mismatch: %x", tr.Hash())
+	}
+	addr := common.Address{1}
+	if err := tr.UpdateStorage(addr, common.Hash{}.Bytes(), []byte{1}); err != nil {
+		t.Fatalf("Failed to update storage, %v", err)
+	}
+	// The first storage slot is the leaf 64 of the account header stem
+	level := make([][]byte, 256)
+	for i := range level {
+		level[i] = make([]byte, 32)
+	}
+	leaf := sha256.Sum256(common.Hash{31: 1}.Bytes())
+	level[64] = leaf[:]
+	for len(level) > 1 {
+		var next [][]byte
+		for i := 0; i < len(level); i += 2 {
+			if bytes.Equal(level[i], make([]byte, 32)) && bytes.Equal(level[i+1], make([]byte, 32)) {
+				next = append(next, make([]byte, 32))
+				continue
+			}
+			h := sha256.Sum256(append(append([]byte{}, level[i]...), level[i+1]...))
+			next = append(next, h[:])
+		}
+		level = next
+	}
+	stem := utils.BinaryBasicDataKey(addr.Bytes())[:31]
+	want := sha256.Sum256(append(append(common.CopyBytes(stem), 0), level[0]...))
+	if have := tr.Hash(); have != common.Hash(want) {
+		t.Fatalf("Tree hash mismatch: have %x, want %x", have, want)
+	}
+}
+
+// Tests that the tree is canonical, i.e. its hash is independent from the order
+// of the updates and deletions, and that the committed nodes can be reloaded.
+func TestBinaryTreeCommit(t *testing.T) {
+	var (
+		db     = newTestDatabase(rawdb.NewMemoryDatabase(), rawdb.PathScheme)
+		addrs  []common.Address
+		code   = make([]byte, 300*31)
+		tr, _  = NewBinaryTrie(common.Hash{}, db)
+		ref, _ = NewBinaryTrie(common.Hash{}, newTestDatabase(rawdb.NewMemoryDatabase(), rawdb.PathScheme))
+	)
+	for i := range code {
+		code[i] = byte(i)
+	}
+	for i := 0; i < 64; i++ {
+		addrs = append(addrs, common.Address{byte(i), byte(i * 7)})
+	}
+	update := func(tr *BinaryTrie, addr common.Address) {
+		if err := tr.UpdateAccount(addr, &types.StateAccount{Nonce: 1, Balance: uint256.NewInt(uint64(addr[1])), CodeHash: crypto.Keccak256(code)}, len(code)); err != nil {
+			t.Fatalf("Failed to update account: %v", err)
+		}
+		if err := tr.UpdateContractCode(addr, crypto.Keccak256Hash(code), code); err != nil {
+			t.Fatalf("Failed to update code: %v", err)
+		}
+		if err := tr.UpdateStorage(addr, common.MaxHash.Bytes(), []byte{1}); err != nil {
+			t.Fatalf("Failed to update storage: %v", err)
+		}
+	}
+	// Fill the tree and commit it, half of the accounts being in the reference
+	for _, addr := range addrs {
+		update(tr, addr)
+	}
+	for i := len(addrs) - 1; i >= 0; i -= 2 {
+		update(ref, addrs[i])
+	}
+	root, nodes := tr.Commit(false)
+	if err := db.Update(root, types.EmptyRootHash, trienode.NewWithNodeSet(nodes)); err != nil {
+		t.Fatalf("Failed to update database: %v", err)
+	}
+	// Reload the tree and delete the other half of the accounts
+	tr, err := NewBinaryTrie(root, db)
+	if err != nil {
+		t.Fatalf("Failed to reopen tree: %v", err)
+	}
+	if tr.Hash() != root {
+		t.Fatalf("Reopened tree hash mismatch: have %x, want %x", tr.Hash(), root)
+	}
+	for i := 0; i < len(addrs); i += 2 {
+		acct, err := tr.GetAccount(addrs[i])
+		if err != nil || acct == nil || acct.Balance.Uint64() != uint64(addrs[i][1]) {
+			t.Fatalf("Account %x mismatch: %v, %v", addrs[i], acct, err)
+		}
+		if err := tr.DeleteAccount(addrs[i]); err != nil {
+			t.Fatalf("Failed to delete account: %v", err)
+		}
+		if err := tr.DeleteStorage(addrs[i], common.MaxHash.Bytes()); err != nil {
+			t.Fatalf("Failed to delete storage: %v", err)
+		}
+	}
+	// Account headers still hold the code chunks, drop them from both trees
+	for _, tree := range []*BinaryTrie{tr, ref} {
+		for _, addr := range addrs {
+			stem := utils.BinaryBasicDataKey(addr.Bytes())[:31]
+			indices := make([]int, 0, 128)
+			for i := 128; i < 256; i++ {
+				indices = append(indices, i)
+			}
+			if err := tree.deleteLeaves(stem, indices...); err != nil {
+				t.Fatalf("Failed to delete code chunks: %v", err)
+			}
+			for chunk := uint64(128); chunk < 300; chunk += 256 {
+				key := utils.BinaryCodeChunkKey(addr.Bytes(), uint256.NewInt(chunk))
+				indices := make([]int, 256)
+				for i := range indices {
+					indices[i] = i
+				}
+				if err := tree.deleteLeaves(key[:31], indices...); err != nil {
+					t.Fatalf("Failed to delete code chunks: %v", err)
+				}
+			}
+		}
+	}
+	// Restore the deleted code chunks of the remaining accounts
+	for i := 1; i < len(addrs); i += 2 {
+		for _, tree := range []*BinaryTrie{tr, ref} {
+			if err := tree.UpdateContractCode(addrs[i], crypto.Keccak256Hash(code), code); err != nil {
+				t.Fatalf("Failed to update code: %v", err)
+			}
+		}
+	}
+	if have, want := tr.Hash(), ref.Hash(); have != want {
+		t.Fatalf("Tree hash mismatch after deletions: have %x, want %x", have, want)
+	}
+	// Commit the deletions and ensure the result is loadable
+	newRoot, nodes := tr.Commit(false)
+	if nodes == nil {
+		t.Fatal("No nodes committed")
+	}
+	var deleted int
+	for _, n := range nodes.Nodes {
+		if n.IsDeleted() {
+			deleted++
+		}
+	}
+	if deleted == 0 {
+		t.Fatal("No deleted nodes committed")
+	}
+	if err := db.Update(newRoot, root, trienode.NewWithNodeSet(nodes)); err != nil {
+		t.Fatalf("Failed to update database: %v", err)
+	}
+	tr, err = NewBinaryTrie(newRoot, db)
+	if err != nil {
+		t.Fatalf("Failed to reopen tree: %v", err)
+	}
+	for i, addr := range addrs {
+		acct, err := tr.GetAccount(addr)
+		if err != nil {
+			t.Fatalf("Failed to get account: %v", err)
+		}
+		if (i%2 == 1) != (acct != nil) {
+			t.Fatalf("Account %x presence mismatch: %v", addr, acct)
+		}
+	}
+}
+
+func TestBinaryTreeProof(t *testing.T) {
+	tr, _ := NewBinaryTrie(common.Hash{}, newTestDatabase(rawdb.NewMemoryDatabase(), rawdb.PathScheme))
+	for addr, acct := range accounts {
+		if err := tr.UpdateAccount(addr, acct, 0); err != nil {
+			t.Fatalf("Failed to update account, %v", err)
+		}
+		for key, val := range storages[addr] {
+			if err := tr.UpdateStorage(addr, key.Bytes(), val); err != nil {
+				t.Fatalf("Failed to update storage, %v", err)
+			}
+		}
+	}
+	root := tr.Hash()
+	for addr := range accounts {
+		for key, val := range storages[addr] {
+			k := utils.BinaryStorageSlotKey(addr.Bytes(), key.Bytes())
+			proof := memorydb.New()
+			if err := tr.Prove(k, proof); err != nil {
+				t.Fatalf("Failed to prove key: %v", err)
+			}
+			value, err := VerifyBinaryProof(root, k, proof)
+			if err != nil {
+				t.Fatalf("Failed to verify proof: %v", err)
+			}
+			if !bytes.Equal(common.TrimLeftZeroes(value), val) {
+				t.Fatalf("Proven value mismatch: have %x, want %x", value, val)
+			}
+			// Corrupted proofs are rejected
+			if _, err := VerifyBinaryProof(common.Hash{1}, k, proof); err == nil {
+				t.Fatal("Proof verified against wrong root")
+			}
+		}
+	}
+	// Absent keys are proven, either in existing stems or not
+	for _, k := range [][]byte{
+		utils.BinaryStorageSlotKey(common.Address{1}.Bytes(), common.Hash{1}.Bytes()),
+		utils.BinaryBasicDataKey(common.Address{3}.Bytes()),
+	} {
+		proof := memorydb.New()
+		if err := tr.Prove(k, proof); err != nil {
+			t.Fatalf("Failed to prove key: %v", err)
+		}
+		value, err := VerifyBinaryProof(root, k, proof)
+		if err != nil {
+			t.Fatalf("Failed to verify absence proof: %v", err)
+		}
+		if value != nil {
+			t.Fatalf("Unexpected value for absent key: %x", value)
+		}
+	}
+}
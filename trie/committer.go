@@ -181,6 +181,51 @@ func ForGatherChildren(node []byte, onChild func(common.Hash)) {
 	forGatherChildren(mustDecodeNodeUnsafe(nil, node), onChild)
 }
 
+// ForGatherChildrenWithPath decodes the provided node and traverses the children
+// inside, invoking onChild for the referenced nodes and onLeaf for the embedded
+// values. The paths are in nibbles, relative to the provided node, without the
+// terminator flag.
+func ForGatherChildrenWithPath(node []byte, onChild func(path []byte, hash common.Hash), onLeaf func(path []byte, value []byte)) error {
+	n, err := decodeNode(nil, node)
+	if err != nil {
+		return err
+	}
+	forGatherChildrenWithPath(n, nil, onChild, onLeaf)
+	return nil
+}
+
+// forGatherChildrenWithPath traverses the node hierarchy and invokes the
+// callbacks for all the hashnode children and value leaves.
+func forGatherChildrenWithPath(n node, path []byte, onChild func([]byte, common.Hash), onLeaf func([]byte, []byte)) {
+	switch n := n.(type) {
+	case *shortNode:
+		key := n.Key
+		if hasTerm(key) {
+			key = key[:len(key)-1]
+		}
+		forGatherChildrenWithPath(n.Val, append(path, key...), onChild, onLeaf)
+	case *fullNode:
+		for i := 0; i < 17; i++ {
+			forGatherChildrenWithPath(n.Children[i], append(path, byte(i)), onChild, onLeaf)
+		}
+	case hashNode:
+		if onChild != nil {
+			onChild(common.CopyBytes(path), common.BytesToHash(n))
+		}
+	case valueNode:
+		if onLeaf != nil {
+			// Values in the 17th slot of full nodes are not addressed by a nibble
+			if len(path) > 0 && path[len(path)-1] == 16 {
+				path = path[:len(path)-1]
+			}
+			onLeaf(common.CopyBytes(path), n)
+		}
+	case nil:
+	default:
+		panic(fmt.Sprintf("unknown node type: %T", n))
+	}
+}
+
 // forGatherChildren traverses the node hierarchy and invokes the callback
 // for all the hashnode children.
 func forGatherChildren(n node, onChild func(hash common.Hash)) {
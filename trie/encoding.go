@@ -104,6 +104,13 @@ func keybytesToHex(str []byte) []byte {
 	return nibbles
 }
 
+// KeybytesToNibbles converts a key into its nibbles, omitting the terminator
+// flag, as the paths reported by ForGatherChildrenWithPath.
+func KeybytesToNibbles(key []byte) []byte {
+	nibbles := keybytesToHex(key)
+	return nibbles[:len(nibbles)-1]
+}
+
 // writeHexKey writes the hexkey into the given slice.
 // OBS! This method omits the termination flag.
 // OBS! The dst slice must be at least 2x as large as the key
@@ -24,6 +24,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/trie/trienode"
 )
 
 // Prove constructs a merkle proof for key. The result contains all encoded nodes
@@ -140,6 +141,65 @@ func VerifyProof(rootHash common.Hash, key []byte, proofDb ethdb.KeyValueReader)
 	}
 }
 
+// MultiProof is a set of trie nodes proving multiple keys, possibly across
+// several tries, in which the nodes shared by the paths of the keys are only
+// included once. The nodes supporting each key are tracked by a KeyProof.
+type MultiProof struct {
+	Nodes  [][]byte            // Deduplicated trie nodes of all the proofs
+	hashes map[common.Hash]int // Positions of the nodes in the set by hash
+}
+
+// NewMultiProof creates an empty multiproof.
+func NewMultiProof() *MultiProof {
+	return &MultiProof{hashes: make(map[common.Hash]int)}
+}
+
+// NewKeyProof returns a proof writer which adds the nodes of a single key proof
+// into the multiproof, e.g. as generated by Trie.Prove.
+func (p *MultiProof) NewKeyProof() *KeyProof {
+	return &KeyProof{set: p, Indices: []int{}}
+}
+
+// KeyProof records the nodes proving a single key into a multiproof. It
+// implements ethdb.KeyValueWriter.
+type KeyProof struct {
+	set     *MultiProof
+	Indices []int // Positions of the nodes proving the key in the multiproof, root first
+}
+
+// Put adds the node into the multiproof unless already present, and appends
+// its position to the indices of the key.
+func (k *KeyProof) Put(key []byte, value []byte) error {
+	hash := common.BytesToHash(key)
+	index, ok := k.set.hashes[hash]
+	if !ok {
+		index = len(k.set.Nodes)
+		k.set.Nodes = append(k.set.Nodes, common.CopyBytes(value))
+		k.set.hashes[hash] = index
+	}
+	k.Indices = append(k.Indices, index)
+	return nil
+}
+
+// Delete panics as there's no reason to remove a node from the proof.
+func (k *KeyProof) Delete(key []byte) error {
+	panic("not supported")
+}
+
+// VerifyMultiProof checks the proof of a single key within a multiproof. The
+// nodes referenced by the given indices must prove the value of the key in the
+// trie with the given root hash, as in VerifyProof.
+func VerifyMultiProof(rootHash common.Hash, key []byte, nodes [][]byte, indices []int) ([]byte, error) {
+	proof := make(trienode.ProofList, 0, len(indices))
+	for _, index := range indices {
+		if index < 0 || index >= len(nodes) {
+			return nil, fmt.Errorf("proof node index %d out of range [0, %d)", index, len(nodes))
+		}
+		proof = append(proof, nodes[index])
+	}
+	return VerifyProof(rootHash, key, proof.Set())
+}
+
 // proofToPath converts a merkle proof to trie node path. The main purpose of
 // this function is recovering a node path from the merkle proof stream. All
 // necessary nodes will be resolved and leave the remaining as hashnode.
@@ -93,6 +93,51 @@ func TestProof(t *testing.T) {
 	}
 }
 
+// Tests that a multiproof deduplicates the nodes shared by the proven keys and
+// that each key can be verified against its indices.
+func TestMultiProof(t *testing.T) {
+	trie, vals := randomTrie(500)
+	root := trie.Hash()
+
+	var (
+		proof  = NewMultiProof()
+		keys   [][]byte
+		proofs []*KeyProof
+		total  int
+	)
+	for _, kv := range vals {
+		kp := proof.NewKeyProof()
+		if err := trie.Prove(kv.k, kp); err != nil {
+			t.Fatalf("Failed to prove key %x: %v", kv.k, err)
+		}
+		keys, proofs = append(keys, kv.k), append(proofs, kp)
+		total += len(kp.Indices)
+	}
+	absent := randBytes(32)
+	kp := proof.NewKeyProof()
+	if err := trie.Prove(absent, kp); err != nil {
+		t.Fatalf("Failed to prove absent key: %v", err)
+	}
+	if len(proof.Nodes) >= total {
+		t.Fatalf("Nodes not deduplicated: %d nodes for %d proof elements", len(proof.Nodes), total)
+	}
+	for i, key := range keys {
+		val, err := VerifyMultiProof(root, key, proof.Nodes, proofs[i].Indices)
+		if err != nil {
+			t.Fatalf("Failed to verify proof for key %x: %v", key, err)
+		}
+		if !bytes.Equal(val, vals[string(key)].v) {
+			t.Fatalf("Verified value mismatch for key %x: have %x, want %x", key, val, vals[string(key)].v)
+		}
+	}
+	if val, err := VerifyMultiProof(root, absent, proof.Nodes, kp.Indices); err != nil || val != nil {
+		t.Fatalf("Failed to verify absence proof: %x, %v", val, err)
+	}
+	if _, err := VerifyMultiProof(root, keys[0], proof.Nodes, []int{len(proof.Nodes)}); err == nil {
+		t.Fatal("Out of range index accepted")
+	}
+}
+
 func TestOneElementProof(t *testing.T) {
 	trie := NewEmpty(newTestDatabase(rawdb.NewMemoryDatabase(), rawdb.HashScheme))
 	updateString(trie, "k", "v")
@@ -0,0 +1,72 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/holiman/uint256"
+)
+
+// BinaryStemSize is the length of the stem shared by the 256 leaves grouped
+// in the same stem node of the binary tree.
+const BinaryStemSize = 31
+
+// GetBinaryTreeKey computes the key of the binary tree (EIP-7864) for the given
+// address, tree index and sub index. The stem is the sha256 hash of the 32-byte
+// aligned address followed by the 32-byte little-endian tree index.
+func GetBinaryTreeKey(address []byte, treeIndex *uint256.Int, subIndex byte) []byte {
+	var buf [64]byte
+	if len(address) < 32 {
+		copy(buf[32-len(address):32], address)
+	} else {
+		copy(buf[:32], address)
+	}
+	for i := 0; i < len(treeIndex); i++ {
+		binary.LittleEndian.PutUint64(buf[32+i*8:], treeIndex[i])
+	}
+	key := sha256.Sum256(buf[:])
+	key[BinaryStemSize] = subIndex
+	return key[:]
+}
+
+// BinaryBasicDataKey returns the binary tree key of the basic data field for
+// the specified account.
+func BinaryBasicDataKey(address []byte) []byte {
+	return GetBinaryTreeKey(address, zero, BasicDataLeafKey)
+}
+
+// BinaryCodeHashKey returns the binary tree key of the code hash field for
+// the specified account.
+func BinaryCodeHashKey(address []byte) []byte {
+	return GetBinaryTreeKey(address, zero, CodeHashLeafKey)
+}
+
+// BinaryCodeChunkKey returns the binary tree key of the code chunk for the
+// specified account.
+func BinaryCodeChunkKey(address []byte, chunk *uint256.Int) []byte {
+	treeIndex, subIndex := codeChunkIndex(chunk)
+	return GetBinaryTreeKey(address, treeIndex, subIndex)
+}
+
+// BinaryStorageSlotKey returns the binary tree key of the storage slot for the
+// specified account. The slots are laid out identically to the verkle tree.
+func BinaryStorageSlotKey(address []byte, storageKey []byte) []byte {
+	treeIndex, subIndex := StorageIndex(storageKey)
+	return GetBinaryTreeKey(address, treeIndex, subIndex)
+}
@@ -33,6 +33,7 @@ import (
 type Config struct {
 	Preimages bool           // Flag whether the preimage of node key is recorded
 	IsVerkle  bool           // Flag whether the db is holding a verkle tree
+	IsBinary  bool           // Flag whether the verkle tree is the binary merkle tree of EIP-7864
 	HashDB    *hashdb.Config // Configs for hash-based scheme
 	PathDB    *pathdb.Config // Configs for experimental path-based scheme
 }
@@ -254,6 +255,30 @@ func (db *Database) Dereference(root common.Hash) error {
 	return nil
 }
 
+// TrackWrites installs a callback notified of every trie node persisted to disk,
+// or removes it if nil is passed. It's only supported by hash-based database and
+// will return an error for others.
+func (db *Database) TrackWrites(tracker func(hash common.Hash)) error {
+	hdb, ok := db.backend.(*hashdb.Database)
+	if !ok {
+		return errors.New("not supported")
+	}
+	hdb.TrackWrites(tracker)
+	return nil
+}
+
+// DeleteNodes removes the given trie nodes from disk, except the ones still held
+// in memory or retained by the keep callback, without racing against concurrent
+// commits. It's only supported by hash-based database and will return an error
+// for others.
+func (db *Database) DeleteNodes(hashes []common.Hash, keep func(hash common.Hash) bool) (int, error) {
+	hdb, ok := db.backend.(*hashdb.Database)
+	if !ok {
+		return 0, errors.New("not supported")
+	}
+	return hdb.DeleteNodes(hashes, keep)
+}
+
 // Recover rollbacks the database to a specified historical point. The state is
 // supported as the rollback destination only if it's canonical state and the
 // corresponding trie histories are existent. It's only supported by path-based
@@ -312,11 +337,28 @@ func (db *Database) Journal(root common.Hash) error {
 	return pdb.Journal(root)
 }
 
+// ScrubReport returns the report of the trie node integrity scrubber, nil if it
+// was never run. It's only supported by path-based database and will return an
+// error for others.
+func (db *Database) ScrubReport() (*pathdb.ScrubReport, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return nil, errors.New("not supported")
+	}
+	return pdb.ScrubReport()
+}
+
 // IsVerkle returns the indicator if the database is holding a verkle tree.
 func (db *Database) IsVerkle() bool {
 	return db.config.IsVerkle
 }
 
+// IsBinary returns the indicator if the database is holding a binary merkle
+// tree. It's a variant of the verkle mode, sharing the single tree layout.
+func (db *Database) IsBinary() bool {
+	return db.config.IsVerkle && db.config.IsBinary
+}
+
 // Disk returns the underlying disk database.
 func (db *Database) Disk() ethdb.Database {
 	return db.disk
@@ -95,6 +95,8 @@ type Database struct {
 	dirtiesSize  common.StorageSize // Storage size of the dirty node cache (exc. metadata)
 	childrenSize common.StorageSize // Storage size of the external children tracking
 
+	tracker func(hash common.Hash) // Callback notified of every node persisted to disk
+
 	lock sync.RWMutex
 }
 
@@ -343,6 +345,9 @@ func (db *Database) Cap(limit common.StorageSize) error {
 		// Fetch the oldest referenced node and push into the batch
 		node := db.dirties[oldest]
 		rawdb.WriteLegacyTrieNode(batch, oldest, node.node)
+		if db.tracker != nil {
+			db.tracker(oldest)
+		}
 
 		// If we exceeded the ideal batch size, commit and reset
 		if batch.ValueSize() >= ethdb.IdealBatchSize {
@@ -466,6 +471,9 @@ func (db *Database) commit(hash common.Hash, batch ethdb.Batch, uncacher *cleane
 	}
 	// If we've reached an optimal batch size, commit and start over
 	rawdb.WriteLegacyTrieNode(batch, hash, node.node)
+	if db.tracker != nil {
+		db.tracker(hash)
+	}
 	if batch.ValueSize() >= ethdb.IdealBatchSize {
 		if err := batch.Write(); err != nil {
 			return err
@@ -585,6 +593,46 @@ func (db *Database) Update(root common.Hash, parent common.Hash, block uint64, n
 	return nil
 }
 
+// TrackWrites installs a callback which is notified of the hash of every trie
+// node persisted to disk from now on, replacing any previous one. Passing nil
+// stops the tracking. The callback runs with the database lock held, so it must
+// not call back into the database.
+func (db *Database) TrackWrites(tracker func(hash common.Hash)) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.tracker = tracker
+}
+
+// DeleteNodes removes the given trie nodes from disk, except the ones which are
+// still cached as dirty or which the keep callback asks to retain. The deletion
+// is exclusive with persisting nodes, so a node flushed concurrently is either
+// reported to keep before being deleted, or written back after the deletion.
+// The number of deleted nodes is returned.
+func (db *Database) DeleteNodes(hashes []common.Hash, keep func(hash common.Hash) bool) (int, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	var (
+		batch   = db.diskdb.NewBatch()
+		deleted int
+	)
+	for _, hash := range hashes {
+		if _, ok := db.dirties[hash]; ok {
+			continue
+		}
+		if keep != nil && keep(hash) {
+			continue
+		}
+		rawdb.DeleteLegacyTrieNode(batch, hash)
+		deleted++
+	}
+	if err := batch.Write(); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
 // Size returns the current storage size of the memory cache in front of the
 // persistent database layer.
 //
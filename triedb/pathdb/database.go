@@ -114,6 +114,7 @@ type Config struct {
 	CleanCacheSize  int    // Maximum memory allowance (in bytes) for caching clean nodes
 	WriteBufferSize int    // Maximum memory allowance (in bytes) for write buffer
 	ReadOnly        bool   // Flag whether the database is opened in read only mode.
+	Scrub           bool   // Flag whether to verify the persisted trie nodes in the background
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -136,6 +137,9 @@ func (c *Config) fields() []interface{} {
 	list = append(list, "cache", common.StorageSize(c.CleanCacheSize))
 	list = append(list, "buffer", common.StorageSize(c.WriteBufferSize))
 	list = append(list, "history", c.StateHistory)
+	if c.Scrub {
+		list = append(list, "scrub", true)
+	}
 	return list
 }
 
@@ -198,6 +202,8 @@ type Database struct {
 	tree    *layerTree                   // The group for all known layers
 	freezer ethdb.ResettableAncientStore // Freezer for storing trie histories, nil possible in tests
 	lock    sync.RWMutex                 // Lock to prevent mutations from happening at the same time
+
+	scrubAbort chan chan struct{} // Notification channel to abort the background scrubber, nil if not running
 }
 
 // New attempts to load an already existing layer from a persistent key-value
@@ -245,6 +251,13 @@ func New(diskdb ethdb.Database, config *Config, isVerkle bool) *Database {
 		fields = append(fields, "verkle", true)
 	}
 	log.Info("Initialized path database", fields...)
+
+	// Start verifying the persisted trie nodes in the background if requested.
+	// The scrubber is only supported by the merkle tree.
+	if config.Scrub && !db.readOnly && !db.isVerkle {
+		db.scrubAbort = make(chan chan struct{})
+		go db.scrub(db.scrubAbort)
+	}
 	return db
 }
 
@@ -498,6 +511,14 @@ func (db *Database) Recoverable(root common.Hash) bool {
 
 // Close closes the trie database and the held freezer.
 func (db *Database) Close() error {
+	// Terminate the background scrubber before acquiring the lock, which
+	// is held by the scrubber while checking the nodes.
+	if db.scrubAbort != nil {
+		ch := make(chan struct{})
+		db.scrubAbort <- ch
+		<-ch
+		db.scrubAbort = nil
+	}
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
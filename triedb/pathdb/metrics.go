@@ -60,4 +60,7 @@ var (
 	historyBuildTimeMeter  = metrics.NewRegisteredTimer("pathdb/history/time", nil)
 	historyDataBytesMeter  = metrics.NewRegisteredMeter("pathdb/history/bytes/data", nil)
 	historyIndexBytesMeter = metrics.NewRegisteredMeter("pathdb/history/bytes/index", nil)
+
+	scrubNodeMeter       = metrics.NewRegisteredMeter("pathdb/scrub/nodes", nil)
+	scrubCorruptionMeter = metrics.NewRegisteredMeter("pathdb/scrub/corruptions", nil)
 )
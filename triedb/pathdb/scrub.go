@@ -0,0 +1,382 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// scrubBatchSize is the number of persisted trie nodes checked by the
+	// scrubber in a single step, during which the database lock is held.
+	scrubBatchSize = 1000
+
+	// scrubStepInterval is the pause between two scrub steps, throttling the
+	// disk reads of the background scrubber.
+	scrubStepInterval = 100 * time.Millisecond
+
+	// scrubPassInterval is the pause between two complete scrub passes.
+	scrubPassInterval = 24 * time.Hour
+
+	// maxScrubCorruptions is the maximum number of corruptions detailed in the
+	// scrub report, the ones beyond it are only counted.
+	maxScrubCorruptions = 1024
+)
+
+// ScrubCorruption describes a trie node found corrupted by the scrubber.
+type ScrubCorruption struct {
+	Owner    common.Hash   `json:"owner"`    // Owner of the storage trie, zero for the account trie
+	Path     hexutil.Bytes `json:"path"`     // Path of the corrupted node in nibbles
+	Reason   string        `json:"reason"`   // Description of the corruption
+	Expected common.Hash   `json:"expected"` // Hash referenced by the parent, zero if unknown
+	Actual   common.Hash   `json:"actual"`   // Hash of the persisted node, zero if missing
+	Time     uint64        `json:"time"`     // Unix timestamp the corruption was found at
+}
+
+// ScrubReport is the progress and the findings of the trie node scrubber,
+// persisted in the database across restarts.
+type ScrubReport struct {
+	Pass        uint64            `json:"pass"`         // Number of the current scrub pass, starting from one
+	Started     uint64            `json:"started"`      // Unix timestamp the current pass was started at
+	Finished    uint64            `json:"finished"`     // Unix timestamp the current pass was finished at, zero if running
+	Marker      hexutil.Bytes     `json:"marker"`       // Database key of the next node to check, empty if finished
+	Accounts    uint64            `json:"accountNodes"` // Number of account trie nodes checked in the current pass
+	Storages    uint64            `json:"storageNodes"` // Number of storage trie nodes checked in the current pass
+	Corrupted   uint64            `json:"corrupted"`    // Number of corruptions found in the current pass
+	Corruptions []ScrubCorruption `json:"corruptions"`  // Details of the first corruptions found in the current pass
+}
+
+// ReadScrubReport retrieves the scrub report persisted in the database, nil
+// if the scrubber was never run.
+func ReadScrubReport(db ethdb.KeyValueReader) (*ScrubReport, error) {
+	blob := rawdb.ReadTrieScrubReport(db)
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	report := new(ScrubReport)
+	if err := rlp.DecodeBytes(blob, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Done reports whether the current scrub pass is finished.
+func (r *ScrubReport) Done() bool {
+	return r.Pass == 0 || len(r.Marker) == 0
+}
+
+// Progress returns the estimated completion of the current pass in the range
+// [0, 1], the account trie being accounted for the first half.
+func (r *ScrubReport) Progress() float64 {
+	if r.Pass == 0 {
+		return 0
+	}
+	if len(r.Marker) == 0 {
+		return 1
+	}
+	var (
+		prefix   = r.Marker[:1]
+		position float64
+		scale    = 1.0
+	)
+	switch {
+	case bytes.Equal(prefix, rawdb.TrieNodeAccountPrefix):
+		// Account trie nodes are keyed by their path in nibbles
+		for i := 1; i < len(r.Marker) && i <= 16; i++ {
+			scale /= 16
+			position += float64(r.Marker[i]) * scale
+		}
+		return position / 2
+	case bytes.Equal(prefix, rawdb.TrieNodeStoragePrefix):
+		// Storage trie nodes are keyed by their owner hash first
+		for i := 1; i < len(r.Marker) && i <= 8; i++ {
+			scale /= 256
+			position += float64(r.Marker[i]) * scale
+		}
+		return 0.5 + position/2
+	}
+	return 0
+}
+
+// Suggestions returns the recommended actions for repairing the corruptions
+// found by the current pass.
+func (r *ScrubReport) Suggestions() []string {
+	if r.Corrupted == 0 {
+		return nil
+	}
+	var (
+		accountTrie bool
+		owners      = make(map[common.Hash]struct{})
+	)
+	for _, c := range r.Corruptions {
+		if c.Owner == (common.Hash{}) {
+			accountTrie = true
+		} else {
+			owners[c.Owner] = struct{}{}
+		}
+	}
+	suggestions := []string{
+		"Check the health of the storage device holding the database, corrupted trie nodes are commonly caused by failing hardware",
+	}
+	if accountTrie {
+		suggestions = append(suggestions, "The account trie is corrupted, remove the state with 'geth removedb' (keeping the ancient chain data) and resynchronize it with snap sync")
+	} else {
+		suggestions = append(suggestions, fmt.Sprintf("The storage tries of %d accounts are corrupted, accessing them will fail with missing trie node errors", len(owners)))
+		suggestions = append(suggestions, "Remove the state with 'geth removedb' (keeping the ancient chain data) and resynchronize it with snap sync to repair them")
+	}
+	if r.Corrupted > uint64(len(r.Corruptions)) {
+		suggestions = append(suggestions, fmt.Sprintf("Only the first %d of %d corruptions are detailed in the report", len(r.Corruptions), r.Corrupted))
+	}
+	return suggestions
+}
+
+// scrubber verifies the integrity of the trie nodes persisted in the key-value
+// store, by rehashing every referenced node and comparing it against the hash
+// held by its parent. The nodes are checked in database order, the progress
+// being tracked by the marker of the report, to be resumable across steps and
+// restarts.
+type scrubber struct {
+	db     ethdb.KeyValueStore
+	report *ScrubReport
+}
+
+// newScrubber creates a scrubber resuming the report persisted in the database.
+func newScrubber(db ethdb.KeyValueStore) *scrubber {
+	report, err := ReadScrubReport(db)
+	if err != nil {
+		log.Warn("Failed to load trie scrub report", "err", err)
+	}
+	if report == nil {
+		report = new(ScrubReport)
+	}
+	return &scrubber{db: db, report: report}
+}
+
+// save persists the report into the database.
+func (s *scrubber) save() {
+	blob, err := rlp.EncodeToBytes(s.report)
+	if err != nil {
+		log.Crit("Failed to encode trie scrub report", "err", err) // impossible to happen
+	}
+	rawdb.WriteTrieScrubReport(s.db, blob)
+}
+
+// step checks up to limit nodes from the marker onwards, starting a new pass
+// if the last one is finished. It returns whether the pass was finished.
+func (s *scrubber) step(limit int) bool {
+	if s.report.Done() {
+		s.report = &ScrubReport{
+			Pass:    s.report.Pass + 1,
+			Started: uint64(time.Now().Unix()),
+			Marker:  common.CopyBytes(rawdb.TrieNodeAccountPrefix),
+		}
+		log.Info("Started trie node scrub pass", "pass", s.report.Pass)
+	}
+	var (
+		prefix = s.report.Marker[:1]
+		it     = s.db.NewIterator(prefix, s.report.Marker[1:])
+		count  int
+	)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if count >= limit {
+			s.report.Marker = common.CopyBytes(key)
+			return false
+		}
+		if bytes.Equal(prefix, rawdb.TrieNodeAccountPrefix) {
+			ok, path := rawdb.ResolveAccountTrieNodeKey(key)
+			if !ok {
+				continue
+			}
+			s.checkNode(common.Hash{}, path, it.Value())
+			s.report.Accounts++
+		} else {
+			ok, owner, path := rawdb.ResolveStorageTrieNode(key)
+			if !ok {
+				continue
+			}
+			s.checkNode(owner, path, it.Value())
+			s.report.Storages++
+		}
+		count++
+		scrubNodeMeter.Mark(1)
+	}
+	if err := it.Error(); err != nil {
+		// Leave the marker untouched, the step will be retried
+		log.Warn("Failed to iterate trie nodes", "err", err)
+		return false
+	}
+	// Move over to the storage tries if the account trie is done
+	if bytes.Equal(prefix, rawdb.TrieNodeAccountPrefix) {
+		s.report.Marker = common.CopyBytes(rawdb.TrieNodeStoragePrefix)
+		return false
+	}
+	s.report.Marker = nil
+	s.report.Finished = uint64(time.Now().Unix())
+	return true
+}
+
+// checkNode decodes the given node and verifies the children referenced by it,
+// including the storage trie roots referenced by account leaves.
+func (s *scrubber) checkNode(owner common.Hash, path []byte, blob []byte) {
+	err := trie.ForGatherChildrenWithPath(blob, func(sub []byte, hash common.Hash) {
+		s.checkChild(owner, append(common.CopyBytes(path), sub...), hash)
+	}, func(sub []byte, value []byte) {
+		if owner != (common.Hash{}) {
+			return
+		}
+		full := append(common.CopyBytes(path), sub...)
+		if len(full) != 2*common.HashLength {
+			s.corrupt(owner, path, fmt.Sprintf("account leaf at depth %d", len(full)), common.Hash{}, crypto.Keccak256Hash(blob))
+			return
+		}
+		var account types.StateAccount
+		if err := rlp.DecodeBytes(value, &account); err != nil {
+			s.corrupt(owner, path, fmt.Sprintf("undecodable account: %v", err), common.Hash{}, crypto.Keccak256Hash(blob))
+			return
+		}
+		if account.Root != types.EmptyRootHash {
+			s.checkChild(nibblesToHash(full), nil, account.Root)
+		}
+	})
+	if err != nil {
+		s.corrupt(owner, path, fmt.Sprintf("undecodable node: %v", err), common.Hash{}, crypto.Keccak256Hash(blob))
+	}
+}
+
+// checkChild verifies that the node at the given position is present and
+// matches the hash referenced by its parent.
+func (s *scrubber) checkChild(owner common.Hash, path []byte, want common.Hash) {
+	var blob []byte
+	if owner == (common.Hash{}) {
+		blob = rawdb.ReadAccountTrieNode(s.db, path)
+	} else {
+		blob = rawdb.ReadStorageTrieNode(s.db, owner, path)
+	}
+	if len(blob) == 0 {
+		s.corrupt(owner, path, "missing node", want, common.Hash{})
+		return
+	}
+	if have := crypto.Keccak256Hash(blob); have != want {
+		s.corrupt(owner, path, "hash mismatch", want, have)
+	}
+}
+
+// corrupt records a corruption in the report.
+func (s *scrubber) corrupt(owner common.Hash, path []byte, reason string, expected, actual common.Hash) {
+	log.Warn("Found corrupted trie node", "owner", owner, "path", fmt.Sprintf("%x", path), "reason", reason, "expected", expected, "actual", actual)
+	scrubCorruptionMeter.Mark(1)
+
+	s.report.Corrupted++
+	if len(s.report.Corruptions) < maxScrubCorruptions {
+		s.report.Corruptions = append(s.report.Corruptions, ScrubCorruption{
+			Owner:    owner,
+			Path:     common.CopyBytes(path),
+			Reason:   reason,
+			Expected: expected,
+			Actual:   actual,
+			Time:     uint64(time.Now().Unix()),
+		})
+	}
+}
+
+// nibblesToHash converts a full 64-nibble path into the hash it represents.
+func nibblesToHash(nibbles []byte) common.Hash {
+	var hash common.Hash
+	for i := 0; i < common.HashLength; i++ {
+		hash[i] = nibbles[2*i]<<4 | nibbles[2*i+1]
+	}
+	return hash
+}
+
+// Scrub runs the trie node scrubber over the given database until the current
+// pass is finished, or a new one if the last pass is already finished. It must
+// not be used while the database is being mutated.
+func Scrub(db ethdb.KeyValueStore) *ScrubReport {
+	var (
+		s      = newScrubber(db)
+		logged = time.Now()
+	)
+	for !s.step(scrubBatchSize) {
+		s.save()
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Scrubbing trie nodes", "pass", s.report.Pass, "progress", fmt.Sprintf("%.2f%%", s.report.Progress()*100),
+				"accounts", s.report.Accounts, "storages", s.report.Storages, "corrupted", s.report.Corrupted)
+			logged = time.Now()
+		}
+	}
+	s.save()
+	return s.report
+}
+
+// scrub runs the trie node scrubber in the background, throttled and pausing
+// while the database is syncing, until it's aborted.
+func (db *Database) scrub(abort chan chan struct{}) {
+	var (
+		s    = newScrubber(db.diskdb)
+		wait time.Duration
+	)
+	if s.report.Pass != 0 && s.report.Done() {
+		wait = time.Until(time.Unix(int64(s.report.Finished), 0).Add(scrubPassInterval))
+	}
+	for {
+		timer := time.NewTimer(wait)
+		select {
+		case ch := <-abort:
+			timer.Stop()
+			ch <- struct{}{}
+			return
+		case <-timer.C:
+		}
+		db.lock.RLock()
+		if db.waitSync || db.readOnly {
+			db.lock.RUnlock()
+			wait = time.Minute
+			continue
+		}
+		done := s.step(scrubBatchSize)
+		db.lock.RUnlock()
+		s.save()
+
+		wait = scrubStepInterval
+		if done {
+			log.Info("Finished trie node scrub pass", "pass", s.report.Pass, "accounts", s.report.Accounts,
+				"storages", s.report.Storages, "corrupted", s.report.Corrupted, "elapsed", common.PrettyDuration(time.Duration(s.report.Finished-s.report.Started)*time.Second))
+			wait = scrubPassInterval
+		}
+	}
+}
+
+// ScrubReport returns the report of the trie node scrubber, nil if it was never
+// run on the database.
+func (db *Database) ScrubReport() (*ScrubReport, error) {
+	return ReadScrubReport(db.diskdb)
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/testrand"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
+)
+
+// makeScrubState persists an account trie along with the storage tries of half
+// of the accounts, returning the sorted account hashes.
+func makeScrubState(db ethdb.KeyValueStore) []common.Hash {
+	var accounts []common.Hash
+	for i := 0; i < 500; i++ {
+		accounts = append(accounts, testrand.Hash())
+	}
+	slices.SortFunc(accounts, common.Hash.Cmp)
+
+	accTrie := trie.NewStackTrie(func(path []byte, hash common.Hash, blob []byte) {
+		rawdb.WriteAccountTrieNode(db, path, blob)
+	})
+	for i, hash := range accounts {
+		root := types.EmptyRootHash
+		if i%2 == 0 {
+			owner := hash
+			storageTrie := trie.NewStackTrie(func(path []byte, hash common.Hash, blob []byte) {
+				rawdb.WriteStorageTrieNode(db, owner, path, blob)
+			})
+			var slots []common.Hash
+			for j := 0; j < 50; j++ {
+				slots = append(slots, testrand.Hash())
+			}
+			slices.SortFunc(slots, common.Hash.Cmp)
+			for _, slot := range slots {
+				val, _ := rlp.EncodeToBytes(testrand.Bytes(32))
+				storageTrie.Update(slot.Bytes(), val)
+			}
+			root = storageTrie.Hash()
+		}
+		blob, _ := rlp.EncodeToBytes(&types.StateAccount{
+			Nonce:    uint64(i),
+			Balance:  uint256.NewInt(uint64(i)),
+			Root:     root,
+			CodeHash: types.EmptyCodeHash.Bytes(),
+		})
+		accTrie.Update(hash.Bytes(), blob)
+	}
+	accTrie.Hash()
+	return accounts
+}
+
+func TestScrub(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	accounts := makeScrubState(db)
+
+	// Scrub the intact state in small steps, ensuring no corruption is found
+	s := newScrubber(db)
+	for !s.step(200) {
+		s.save()
+		if progress := s.report.Progress(); progress < 0 || progress > 1 {
+			t.Fatalf("Invalid progress: %f", progress)
+		}
+	}
+	s.save()
+	report, err := ReadScrubReport(db)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	if report.Pass != 1 || !report.Done() || report.Finished == 0 {
+		t.Fatalf("Unexpected report state: pass %d, marker %x, finished %d", report.Pass, report.Marker, report.Finished)
+	}
+	if report.Accounts == 0 || report.Storages == 0 {
+		t.Fatalf("No nodes checked: accounts %d, storages %d", report.Accounts, report.Storages)
+	}
+	if report.Corrupted != 0 || len(report.Suggestions()) != 0 {
+		t.Fatalf("Unexpected corruptions: %v", report.Corruptions)
+	}
+	// Corrupt an account trie node, drop a storage trie root and rerun
+	var (
+		it      = db.NewIterator(rawdb.TrieNodeAccountPrefix, []byte{0x1})
+		corrupt []byte
+	)
+	for it.Next() {
+		if ok, path := rawdb.ResolveAccountTrieNodeKey(it.Key()); ok && len(path) > 1 {
+			corrupt = common.CopyBytes(path)
+			break
+		}
+	}
+	it.Release()
+	blob := rawdb.ReadAccountTrieNode(db, corrupt)
+	blob[len(blob)-1] ^= 0xff
+	rawdb.WriteAccountTrieNode(db, corrupt, blob)
+	rawdb.DeleteStorageTrieNode(db, accounts[0], nil)
+
+	report = Scrub(db)
+	if report.Pass != 2 {
+		t.Fatalf("Unexpected pass: have %d, want 2", report.Pass)
+	}
+	var foundNode, foundRoot bool
+	for _, c := range report.Corruptions {
+		switch {
+		case c.Owner == (common.Hash{}) && bytes.Equal(c.Path, corrupt) && c.Reason == "hash mismatch":
+			foundNode = true
+		case c.Owner == accounts[0] && len(c.Path) == 0:
+			if c.Reason != "missing node" {
+				t.Fatalf("Unexpected corruption of storage root: %v", c)
+			}
+			foundRoot = true
+		}
+	}
+	if !foundNode || !foundRoot {
+		t.Fatalf("Corruptions not reported: node %t, storage root %t, report %v", foundNode, foundRoot, report.Corruptions)
+	}
+	if len(report.Suggestions()) == 0 {
+		t.Fatal("No repair suggested")
+	}
+}